@@ -0,0 +1,223 @@
+// Package annotation parses the annotations of the column comments, which let a database schema drive the
+// generated code, e.g. "用户状态 @enum(1:ACTIVE,2:DISABLED) @sensitive @json(status_code)".
+//
+// The supported annotations are:
+//
+//	@enum(ACTIVE,DISABLED)       the values of an enum, numbered from 1
+//	@enum(1:ACTIVE,2:DISABLED)   the values of an enum, with the numbers stored in the column
+//	@sensitive                   the field is never returned in the responses
+//	@json(status_code)           the JSON name of the field
+//	@min(0) @max(100)            the range of a numeric field, @range(0,100) sets both
+//	@minlen(1) @maxlen(32)       the length of a string field, @notempty is @minlen(1)
+//	@pattern(^[a-z]+$)           the regular expression a string field matches
+//
+// An annotation starts a word of the comment, or directly follows non-ASCII text, and its name is
+// case-insensitive. The unknown and malformed annotations, e.g. an e-mail address or @min(abc), are kept
+// as text in the comment.
+package annotation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EnumValue is a value of an @enum annotation.
+type EnumValue struct {
+	Name string
+	// Number is the number of the value, either given by the annotation or its position from 1.
+	Number int64
+}
+
+// Annotations are the annotations of a column comment.
+type Annotations struct {
+	// Comment is the human-readable comment, without its annotations.
+	Comment string
+	Enum    []EnumValue
+	// Numbered reports whether the enum values are given with their numbers, e.g. @enum(1:ACTIVE).
+	Numbered  bool
+	Sensitive bool
+	// JSON is the JSON name of the field, or empty to keep the default one.
+	JSON string
+	// Min and Max are the range of a numeric field, as written in the annotation.
+	Min, Max string
+	// MinLen and MaxLen are the length of a string field, or 0 when unset.
+	MinLen, MaxLen int
+	Pattern        string
+}
+
+// Validated reports whether the annotations have a validation hint.
+func (a Annotations) Validated() bool {
+	return a.Min != "" || a.Max != "" || a.MinLen > 0 || a.MaxLen > 0 || a.Pattern != ""
+}
+
+var (
+	reName   = regexp.MustCompile(`^@([A-Za-z]+)`)
+	reIdent  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	reNumber = regexp.MustCompile(`^[-+]?(?:\d+\.?\d*|\.\d+)$`)
+	reSpaces = regexp.MustCompile(`[ \t]{2,}`)
+)
+
+// Parse parses the annotations of a column comment.
+func Parse(comment string) Annotations {
+	var (
+		a    Annotations
+		text strings.Builder
+	)
+	for i := 0; i < len(comment); {
+		// An annotation starts a word, so the e-mail addresses are left out.
+		if comment[i] != '@' || (i > 0 && isWordByte(comment[i-1])) {
+			text.WriteByte(comment[i])
+			i++
+			continue
+		}
+		n := annotationLen(comment[i:])
+		if n == 0 || !a.apply(comment[i:i+n]) {
+			text.WriteByte(comment[i])
+			i++
+			continue
+		}
+		i += n
+	}
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(reSpaces.ReplaceAllString(line, " "))
+	}
+	a.Comment = strings.TrimSpace(strings.Join(lines, "\n"))
+	return a
+}
+
+// annotationLen returns the length of the annotation at the start of s, e.g. @json(name), or 0 if there
+// is none. The arguments may hold balanced parentheses, e.g. @pattern(^(a|b)$).
+func annotationLen(s string) int {
+	m := reName.FindString(s)
+	if m == "" {
+		return 0
+	}
+	if len(s) == len(m) || s[len(m)] != '(' {
+		if len(s) > len(m) && (isWordByte(s[len(m)]) || strings.IndexByte(".@-", s[len(m)]) >= 0) {
+			return 0
+		}
+		return len(m)
+	}
+	depth := 0
+	for i := len(m); i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// apply applies an annotation, e.g. @json(name), and reports whether it is a known and valid one.
+func (a *Annotations) apply(s string) bool {
+	name, args, hasArgs := strings.Cut(s[1:], "(")
+	if hasArgs {
+		args = strings.TrimSpace(args[:len(args)-1])
+	}
+	switch strings.ToLower(name) {
+	case "enum":
+		return hasArgs && a.enum(args)
+	case "sensitive":
+		a.Sensitive = !hasArgs
+		return !hasArgs
+	case "json":
+		if !reIdent.MatchString(args) {
+			return false
+		}
+		a.JSON = args
+	case "min":
+		if !reNumber.MatchString(args) {
+			return false
+		}
+		a.Min = strings.TrimPrefix(args, "+")
+	case "max":
+		if !reNumber.MatchString(args) {
+			return false
+		}
+		a.Max = strings.TrimPrefix(args, "+")
+	case "range":
+		lower, upper, ok := strings.Cut(args, ",")
+		lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+		if !ok || !reNumber.MatchString(lower) || !reNumber.MatchString(upper) {
+			return false
+		}
+		a.Min, a.Max = strings.TrimPrefix(lower, "+"), strings.TrimPrefix(upper, "+")
+	case "minlen":
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 0 {
+			return false
+		}
+		a.MinLen = n
+	case "maxlen":
+		n, err := strconv.Atoi(args)
+		if err != nil || n <= 0 {
+			return false
+		}
+		a.MaxLen = n
+	case "notempty":
+		if hasArgs {
+			return false
+		}
+		a.MinLen = max(a.MinLen, 1)
+	case "pattern":
+		if _, err := regexp.Compile(args); err != nil || args == "" {
+			return false
+		}
+		a.Pattern = args
+	default:
+		return false
+	}
+	return true
+}
+
+// enum parses the values of an @enum annotation, either all numbered, e.g. 1:ACTIVE,2:DISABLED, or none.
+func (a *Annotations) enum(args string) bool {
+	var (
+		values   []EnumValue
+		numbered bool
+	)
+	for i, v := range strings.Split(args, ",") {
+		num, name, ok := strings.Cut(v, ":")
+		if !ok {
+			num, name = "", num
+		}
+		if i > 0 && ok != numbered {
+			return false
+		}
+		numbered = ok
+		name = strings.TrimSpace(name)
+		if !reIdent.MatchString(name) {
+			return false
+		}
+		value := EnumValue{Name: name, Number: int64(i + 1)}
+		if ok {
+			n, err := strconv.ParseInt(strings.TrimSpace(num), 10, 64)
+			if err != nil {
+				return false
+			}
+			value.Number = n
+		}
+		for _, other := range values {
+			if other.Name == value.Name || other.Number == value.Number {
+				return false
+			}
+		}
+		values = append(values, value)
+	}
+	a.Enum, a.Numbered = values, numbered
+	return true
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore. The other bytes, e.g. the ones of
+// the Chinese text, may be followed by an annotation.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
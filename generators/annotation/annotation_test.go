@@ -0,0 +1,73 @@
+package annotation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    Annotations
+	}{
+		{
+			comment: "用户状态 @enum(1:ACTIVE,2:DISABLED) @sensitive @json(status_code)",
+			want: Annotations{
+				Comment:   "用户状态",
+				Enum:      []EnumValue{{Name: "ACTIVE", Number: 1}, {Name: "DISABLED", Number: 2}},
+				Numbered:  true,
+				Sensitive: true,
+				JSON:      "status_code",
+			},
+		},
+		{
+			comment: "性别@enum(MALE, FEMALE) 默认未知",
+			want: Annotations{
+				Comment: "性别 默认未知",
+				Enum:    []EnumValue{{Name: "MALE", Number: 1}, {Name: "FEMALE", Number: 2}},
+			},
+		},
+		{
+			comment: "Age @range(0, 150)",
+			want:    Annotations{Comment: "Age", Min: "0", Max: "150"},
+		},
+		{
+			comment: "@Min(-1.5) score @max(+10)",
+			want:    Annotations{Comment: "score", Min: "-1.5", Max: "10"},
+		},
+		{
+			comment: "Code @notempty @maxlen(8) @pattern(^(A|B)[0-9]\\)?$)",
+			want:    Annotations{Comment: "Code", MinLen: 1, MaxLen: 8, Pattern: `^(A|B)[0-9]\)?$`},
+		},
+		{
+			comment: "Mail of the user, e.g. admin@example.com",
+			want:    Annotations{Comment: "Mail of the user, e.g. admin@example.com"},
+		},
+		{
+			comment: "Unknown @todo, malformed @min(abc) @enum(1:A,B) @json(a b) @sensitive.",
+			want:    Annotations{Comment: "Unknown @todo, malformed @min(abc) @enum(1:A,B) @json(a b) @sensitive."},
+		},
+		{
+			comment: "Duplicated @enum(A,A) and unterminated @pattern(a",
+			want:    Annotations{Comment: "Duplicated @enum(A,A) and unterminated @pattern(a"},
+		},
+		{
+			comment: "First line @sensitive\nsecond line",
+			want:    Annotations{Comment: "First line\nsecond line", Sensitive: true},
+		},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.comment); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q):\n got: %+v\nwant: %+v", tt.comment, got, tt.want)
+		}
+	}
+}
+
+func TestValidated(t *testing.T) {
+	if Parse("@sensitive @enum(A)").Validated() {
+		t.Error("expected no validation hint")
+	}
+	if !Parse("@maxlen(10)").Validated() {
+		t.Error("expected a validation hint")
+	}
+}
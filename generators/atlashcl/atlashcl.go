@@ -0,0 +1,157 @@
+// Package atlashcl builds a database schema from Atlas HCL files (schema.hcl), which describe the
+// schema of a service declaratively. It lets the importers generate code from the desired schema of
+// such a service, without a database.
+//
+// The files are evaluated by the HCL codec of Atlas for MySQL, PostgreSQL or SQLite. The dialect is
+// given by the dialect parameter of the path, e.g. "schema.hcl?dialect=postgres". Without it, the
+// dialects are tried in the order MySQL, PostgreSQL and SQLite, and the first that evaluates the files
+// wins.
+package atlashcl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// Scheme is the DSN scheme of Atlas HCL files, e.g. "hcl://schema.hcl?dialect=mysql". The path may
+// also be a folder of .hcl files.
+const Scheme = "hcl"
+
+// dialect is a dialect whose HCL codec evaluates the files.
+type dialect struct {
+	name string
+	eval schemahcl.Evaluator
+	// format formats a column type, to fill the raw type that the evaluation leaves empty.
+	format func(schema.Type) (string, error)
+}
+
+var dialects = []dialect{
+	{name: "mysql", eval: mysql.EvalHCL, format: mysql.FormatType},
+	{name: "postgres", eval: postgres.EvalHCL, format: postgres.FormatType},
+	{name: "sqlite", eval: sqlite.EvalHCL, format: sqlite.FormatType},
+}
+
+// aliases holds the other names of the dialects.
+var aliases = map[string]string{
+	"mariadb":    "mysql",
+	"tidb":       "mysql",
+	"postgresql": "postgres",
+	"sqlite3":    "sqlite",
+}
+
+// Load evaluates the HCL file, or the .hcl files of a folder, and returns the schema they describe.
+// The path may end with a dialect parameter, e.g. "schema.hcl?dialect=postgres".
+func Load(path string) (*schema.Schema, error) {
+	s, _, err := LoadDialect(path)
+	return s, err
+}
+
+// LoadDialect is like Load, but also returns the name of the dialect that evaluated the files:
+// "mysql", "postgres" or "sqlite".
+func LoadDialect(path string) (*schema.Schema, string, error) {
+	path, query, _ := strings.Cut(path, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, "", fmt.Errorf("atlashcl: %w", err)
+	}
+	files, err := hclFiles(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("atlashcl: %w", err)
+	}
+	candidates := dialects
+	if name := strings.ToLower(params.Get("dialect")); name != "" {
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		candidates = nil
+		for _, d := range dialects {
+			if d.name == name {
+				candidates = append(candidates, d)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, "", fmt.Errorf("atlashcl: unknown dialect %q, expected mysql, postgres or sqlite", name)
+		}
+	}
+	var errs []error
+	for _, d := range candidates {
+		s, err := d.load(files)
+		if err == nil {
+			return s, d.name, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", d.name, err))
+	}
+	if len(errs) == 1 {
+		return nil, "", fmt.Errorf("atlashcl: %s: %w", path, errs[0])
+	}
+	return nil, "", fmt.Errorf("atlashcl: %s: no dialect evaluates the schema:\n%w", path, errors.Join(errs...))
+}
+
+// hclFiles returns the file of the path, or the .hcl files of the folder.
+func hclFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.hcl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .hcl files in %s", path)
+	}
+	return files, nil
+}
+
+// load evaluates the files with the codec of the dialect.
+func (d dialect) load(files []string) (*schema.Schema, error) {
+	parser := hclparse.NewParser()
+	for _, f := range files {
+		if _, diags := parser.ParseHCLFile(f); diags.HasErrors() {
+			return nil, diags
+		}
+	}
+	var realm schema.Realm
+	if err := d.eval.Eval(parser, &realm, nil); err != nil {
+		return nil, err
+	}
+	if len(realm.Schemas) == 0 {
+		return nil, errors.New("no schema is defined")
+	}
+	for _, s := range realm.Schemas {
+		for _, t := range s.Tables {
+			for _, c := range t.Columns {
+				if c.Type == nil || c.Type.Raw != "" || c.Type.Type == nil {
+					continue
+				}
+				if raw, err := d.format(c.Type.Type); err == nil {
+					c.Type.Raw = raw
+				}
+			}
+		}
+	}
+	if len(realm.Schemas) == 1 {
+		return realm.Schemas[0], nil
+	}
+	// The tables of all the schemas are imported, and keep the schema they belong to.
+	s := &schema.Schema{Name: realm.Schemas[0].Name, Realm: &realm}
+	for _, rs := range realm.Schemas {
+		s.Tables = append(s.Tables, rs.Tables...)
+		s.Views = append(s.Views, rs.Views...)
+	}
+	return s, nil
+}
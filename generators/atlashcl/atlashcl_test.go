@@ -0,0 +1,171 @@
+package atlashcl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+)
+
+const mysqlHCL = `
+schema "shop" {
+  charset = "utf8mb4"
+}
+
+table "users" {
+  schema  = schema.shop
+  comment = "the users"
+  column "id" {
+    type           = bigint
+    unsigned       = true
+    auto_increment = true
+  }
+  column "email" {
+    type    = varchar(255)
+    comment = "login email"
+  }
+  column "status" {
+    type    = enum("active", "disabled")
+    default = "active"
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "users_email_key" {
+    unique  = true
+    columns = [column.email]
+  }
+}
+`
+
+const postgresHCL = `
+schema "public" {}
+
+enum "status" {
+  schema = schema.public
+  values = ["active", "disabled"]
+}
+
+table "users" {
+  schema = schema.public
+  column "id" {
+    type = bigserial
+  }
+  column "status" {
+    type = enum.status
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+
+table "posts" {
+  schema = schema.public
+  column "id" {
+    type = uuid
+  }
+  column "user_id" {
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "posts_user_id_fkey" {
+    columns     = [column.user_id]
+    ref_columns = [table.users.column.id]
+    on_delete   = CASCADE
+  }
+}
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMySQL(t *testing.T) {
+	s, err := Load(writeFile(t, t.TempDir(), "schema.hcl", mysqlHCL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, ok := s.Table("users")
+	if !ok || s.Name != "shop" {
+		t.Fatalf("expected the users table of the shop schema, got %+v", s)
+	}
+	id, _ := users.Column("id")
+	if id.Type.Raw != "bigint unsigned" || !hasAttr[*mysql.AutoIncrement](id.Attrs) {
+		t.Errorf("expected id to be an auto-increment bigint unsigned, got %q %+v", id.Type.Raw, id.Attrs)
+	}
+	email, _ := users.Column("email")
+	if !hasAttr[*schema.Comment](email.Attrs) || email.Type.Raw != "varchar(255)" {
+		t.Errorf("expected email to be a commented varchar(255), got %q %+v", email.Type.Raw, email.Attrs)
+	}
+	if len(email.Indexes) != 1 || !email.Indexes[0].Unique {
+		t.Errorf("expected email to be unique, got %+v", email.Indexes)
+	}
+	status, _ := users.Column("status")
+	if e, ok := status.Type.Type.(*schema.EnumType); !ok || len(e.Values) != 2 {
+		t.Errorf("expected status to be an enum, got %#v", status.Type.Type)
+	}
+}
+
+func TestLoadPostgres(t *testing.T) {
+	dir := t.TempDir()
+	// The schema may be split into several files of a folder.
+	parts := strings.SplitAfter(postgresHCL, "\n}\n")
+	writeFile(t, dir, "schema.hcl", parts[0]+parts[1])
+	writeFile(t, dir, "posts.hcl", strings.Join(parts[2:], ""))
+
+	for _, path := range []string{dir, dir + "?dialect=postgresql"} {
+		s, err := Load(path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		users, _ := s.Table("users")
+		id, _ := users.Column("id")
+		if _, ok := id.Type.Type.(*postgres.SerialType); !ok || id.Type.Raw != "bigserial" {
+			t.Errorf("%s: expected id to be a bigserial, got %q %#v", path, id.Type.Raw, id.Type.Type)
+		}
+		posts, _ := s.Table("posts")
+		if len(posts.ForeignKeys) != 1 || posts.ForeignKeys[0].RefTable != users || posts.ForeignKeys[0].OnDelete != schema.Cascade {
+			t.Errorf("%s: expected user_id to reference users on delete cascade, got %+v", path, posts.ForeignKeys)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	pg := writeFile(t, dir, "pg.hcl", postgresHCL)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: pg + "?dialect=mysql", want: "mysql:"},
+		{path: pg + "?dialect=oracle", want: `unknown dialect "oracle"`},
+		{path: writeFile(t, dir, "bad.hcl", "table {"), want: "no dialect evaluates the schema"},
+		{path: filepath.Join(dir, "missing.hcl"), want: "no such file"},
+	}
+	for _, tt := range tests {
+		_, err := Load(tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), "atlashcl: ") {
+			t.Errorf("Load(%q): expected an error containing %q, got %v", tt.path, tt.want, err)
+		}
+	}
+}
+
+func hasAttr[T schema.Attr](attrs []schema.Attr) bool {
+	for _, a := range attrs {
+		if _, ok := a.(T); ok {
+			return true
+		}
+	}
+	return false
+}
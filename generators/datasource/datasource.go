@@ -0,0 +1,71 @@
+// Package datasource resolves the data source given to the importers, so that sql2orm and sql2proto
+// accept the same DSNs: a database URL, a SQLite database file, a DDL file or text, a folder of
+// migrations, or a DBML, Prisma or Atlas HCL file.
+package datasource
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tx7do/go-wind-toolkit/generators/atlashcl"
+	"github.com/tx7do/go-wind-toolkit/generators/dbml"
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
+	"github.com/tx7do/go-wind-toolkit/generators/prisma"
+)
+
+// sqliteHeader is the magic header of the SQLite database files.
+const sqliteHeader = "SQLite format 3\x00"
+
+// Normalize normalizes the DSN to ensure it has a valid scheme.
+// If the input is a SQLite database file, it will be prefixed with "sqlite://".
+// If the input is any other file path, it will be prefixed with "file://".
+// If the input is a directory, it is a folder of migrations and will be prefixed with "migrations://".
+// If the input is a .dbml, .prisma or .hcl file, it will be prefixed with "dbml://", "prisma://" or "hcl://".
+// If it's SQL text content, it will be prefixed with "text://".
+// If it already has a scheme (mysql://, postgres://, etc.), it's returned as-is.
+func Normalize(dsn string) string {
+	// Check if it already has a scheme
+	if strings.Contains(dsn, "://") {
+		return dsn
+	}
+
+	// Check if it's a file path
+	if info, err := os.Stat(dsn); err == nil {
+		if info.IsDir() {
+			return migrations.Scheme + "://" + dsn
+		}
+		switch strings.ToLower(filepath.Ext(dsn)) {
+		case ".dbml":
+			return dbml.Scheme + "://" + dsn
+		case ".prisma":
+			return prisma.Scheme + "://" + dsn
+		case ".hcl":
+			return atlashcl.Scheme + "://" + dsn
+		}
+		if IsSQLiteFile(dsn) {
+			return "sqlite://" + dsn
+		}
+		return "file://" + dsn
+	}
+
+	// Treat it as SQL text content
+	return "text://" + dsn
+}
+
+// IsSQLiteFile reports whether the file at the given path is a SQLite database,
+// by checking the "SQLite format 3" magic header.
+func IsSQLiteFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	header := make([]byte, len(sqliteHeader))
+	if _, err = io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return string(header) == sqliteHeader
+}
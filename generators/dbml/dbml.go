@@ -0,0 +1,310 @@
+// Package dbml builds a database schema from a DBML document, the language of dbdiagram.io. It lets
+// the importers generate code from a data model designed in dbdiagram.io, without a database.
+//
+// The tables, their columns, indexes and notes, the enums and the relationships are read. The notes
+// become the comments of the tables and columns, and the relationships become foreign keys, except
+// the many-to-many ones which have no foreign key. The column types are SQL types, parsed as MySQL,
+// PostgreSQL or SQLite types, preferring the database_type of the Project.
+package dbml
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
+)
+
+// Scheme is the DSN scheme of a DBML file, e.g. "dbml://docs/schema.dbml".
+const Scheme = "dbml"
+
+// Load reads the DBML file and returns its schema.
+func Load(path string) (*schema.Schema, error) {
+	s, _, err := LoadDialect(path)
+	return s, err
+}
+
+// LoadDialect is like Load, but also returns the name of the dialect of the database_type of the
+// Project: "mysql", "postgres", "sqlite", or "" if the Project sets none of them.
+func LoadDialect(path string) (*schema.Schema, string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("dbml: %w", err)
+	}
+	s, dialect, err := build(string(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("dbml: %s: %w", path, err)
+	}
+	return s, dialect, nil
+}
+
+// Parse parses a DBML document and returns its schema.
+func Parse(src string) (*schema.Schema, error) {
+	s, _, err := build(src)
+	if err != nil {
+		return nil, fmt.Errorf("dbml: %w", err)
+	}
+	return s, nil
+}
+
+func build(src string) (*schema.Schema, string, error) {
+	doc, err := parse(src)
+	if err != nil {
+		return nil, "", err
+	}
+	s, err := doc.build()
+	if err != nil {
+		return nil, "", err
+	}
+	return s, doc.dialect(), nil
+}
+
+// build converts the document to an Atlas schema, resolving the enums and the relationships.
+func (d *document) build() (*schema.Schema, error) {
+	s := &schema.Schema{}
+	enums := make(map[string]*schema.EnumType)
+	for _, e := range d.enums {
+		enums[strings.ToLower(e.name)] = &schema.EnumType{T: shortName(e.name), Values: e.values}
+	}
+	tables := make(map[string]*schema.Table)
+	for _, dt := range d.tables {
+		t, err := d.table(dt, enums)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tables[strings.ToLower(dt.name)]; ok {
+			return nil, fmt.Errorf("line %d: table %q is defined twice", dt.line, dt.name)
+		}
+		// A relationship may name a table by its qualified name, its name or its alias.
+		for _, name := range []string{dt.name, shortName(dt.name), dt.alias} {
+			if name != "" {
+				tables[strings.ToLower(name)] = t
+			}
+		}
+		s.AddTables(t)
+	}
+	for _, r := range d.refs {
+		if err := addForeignKey(tables, r); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+	}
+	for _, t := range s.Tables {
+		for _, idx := range t.Indexes {
+			for _, part := range idx.Parts {
+				if part.C != nil {
+					part.C.Indexes = append(part.C.Indexes, idx)
+				}
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			for _, c := range fk.Columns {
+				c.ForeignKeys = append(c.ForeignKeys, fk)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (d *document) table(dt *table, enums map[string]*schema.EnumType) (*schema.Table, error) {
+	t := schema.NewTable(shortName(dt.name))
+	if dt.note != "" {
+		t.SetComment(dt.note)
+	}
+	var pk []*schema.Column
+	for _, dc := range dt.columns {
+		if _, ok := t.Column(dc.name); ok {
+			return nil, fmt.Errorf("line %d: column %q is defined twice in table %q", dc.line, dc.name, dt.name)
+		}
+		c := schema.NewColumn(dc.name)
+		c.Type = &schema.ColumnType{Raw: dc.typ, Null: !dc.notNull && !dc.pk}
+		if e, ok := enums[strings.ToLower(dc.typ)]; ok {
+			c.Type.Type = e
+		} else {
+			c.Type.Type = d.parseType(dc.typ)
+		}
+		setDefault(c, dc.def)
+		if dc.increment {
+			c.AddAttrs(d.autoIncrement())
+		}
+		if dc.note != "" {
+			c.SetComment(dc.note)
+		}
+		t.AddColumns(c)
+		if dc.pk {
+			pk = append(pk, c)
+		}
+		if dc.unique {
+			t.AddIndexes(&schema.Index{
+				Name:   t.Name + "_" + c.Name + "_key",
+				Unique: true,
+				Table:  t,
+				Parts:  []*schema.IndexPart{{C: c}},
+			})
+		}
+	}
+	if len(pk) > 0 {
+		idx := &schema.Index{Name: t.Name + "_pkey", Unique: true, Table: t}
+		for i, c := range pk {
+			idx.Parts = append(idx.Parts, &schema.IndexPart{SeqNo: i, C: c})
+		}
+		t.SetPrimaryKey(idx)
+	}
+	for _, di := range dt.indexes {
+		idx := &schema.Index{Name: di.name, Unique: di.unique || di.pk, Table: t}
+		var names []string
+		for i, part := range di.parts {
+			ip := &schema.IndexPart{SeqNo: i}
+			if part.expr != "" {
+				ip.X = &schema.RawExpr{X: part.expr}
+				names = append(names, "expr")
+			} else if c, ok := t.Column(part.column); ok {
+				ip.C = c
+				names = append(names, part.column)
+			} else {
+				return nil, fmt.Errorf("line %d: column %q does not exist in table %q", di.line, part.column, dt.name)
+			}
+			idx.Parts = append(idx.Parts, ip)
+		}
+		if di.pk {
+			if idx.Name == "" {
+				idx.Name = t.Name + "_pkey"
+			}
+			for _, part := range idx.Parts {
+				if part.C != nil {
+					part.C.Type.Null = false
+				}
+			}
+			t.SetPrimaryKey(idx)
+			continue
+		}
+		if idx.Name == "" {
+			suffix := "idx"
+			if idx.Unique {
+				suffix = "key"
+			}
+			idx.Name = t.Name + "_" + strings.Join(names, "_") + "_" + suffix
+		}
+		t.AddIndexes(idx)
+	}
+	return t, nil
+}
+
+// dialect returns the name of the dialect of the database_type of the Project, or "" if it is unknown.
+func (d *document) dialect() string {
+	switch strings.ToLower(d.dbType) {
+	case "postgresql", "postgres":
+		return "postgres"
+	case "mysql", "mariadb":
+		return "mysql"
+	case "sqlite":
+		return "sqlite"
+	}
+	return ""
+}
+
+// parseType parses a column type as a type of the dialect of the Project, and then of any dialect.
+func (d *document) parseType(raw string) schema.Type {
+	parse, ok := map[string]func(string) (schema.Type, error){
+		"mysql":    mysql.ParseType,
+		"postgres": postgres.ParseType,
+		"sqlite":   sqlite.ParseType,
+	}[d.dialect()]
+	if !ok {
+		return migrations.ParseType(raw)
+	}
+	typ, err := parse(raw)
+	if err != nil {
+		return migrations.ParseType(raw)
+	}
+	switch typ.(type) {
+	case *schema.UnsupportedType, *postgres.UserDefinedType, *sqlite.UserDefinedType:
+		return migrations.ParseType(raw)
+	}
+	return typ
+}
+
+// autoIncrement returns the attribute of an increment column, for the database_type of the Project.
+func (d *document) autoIncrement() schema.Attr {
+	switch d.dialect() {
+	case "postgres":
+		return &postgres.Identity{Generation: "BY DEFAULT"}
+	case "sqlite":
+		return &sqlite.AutoIncrement{}
+	default:
+		return &mysql.AutoIncrement{}
+	}
+}
+
+// setDefault sets the default value of a column: a string, a number, a boolean or an `expression`.
+func setDefault(c *schema.Column, value []token) {
+	switch {
+	case len(value) == 1 && value[0].kind == tokString:
+		c.Default = &schema.Literal{V: "'" + strings.ReplaceAll(value[0].val, "'", "''") + "'"}
+	case len(value) == 1 && value[0].kind == tokExpr:
+		c.Default = &schema.RawExpr{X: value[0].val}
+	case len(value) == 1 && (value[0].kind == tokNumber || value[0].is("true", "false")):
+		c.Default = &schema.Literal{V: value[0].val}
+	case len(value) == 2 && value[0].punct("-") && value[1].kind == tokNumber:
+		c.Default = &schema.Literal{V: "-" + value[1].val}
+	}
+}
+
+// addForeignKey adds the foreign key of a relationship to the table that holds it: the left table of
+// a many-to-one or one-to-one relationship, and the right table of a one-to-many one.
+func addForeignKey(tables map[string]*schema.Table, r *ref) error {
+	from, to := r.from, r.to
+	switch r.op {
+	case "<>":
+		return nil
+	case "<":
+		from, to = to, from
+	}
+	t, ok := tables[strings.ToLower(from.table)]
+	if !ok {
+		return fmt.Errorf("table %q does not exist", from.table)
+	}
+	refTable, ok := tables[strings.ToLower(to.table)]
+	if !ok {
+		return fmt.Errorf("table %q does not exist", to.table)
+	}
+	if len(from.columns) != len(to.columns) {
+		return fmt.Errorf("the relationship between %s%v and %s%v has a different number of columns", from.table, from.columns, to.table, to.columns)
+	}
+	fk := &schema.ForeignKey{
+		Symbol:   r.name,
+		Table:    t,
+		RefTable: refTable,
+		OnDelete: r.onDelete,
+		OnUpdate: r.onUpdate,
+	}
+	for i := range from.columns {
+		c, ok := t.Column(from.columns[i])
+		if !ok {
+			return fmt.Errorf("column %q does not exist in table %q", from.columns[i], t.Name)
+		}
+		rc, ok := refTable.Column(to.columns[i])
+		if !ok {
+			return fmt.Errorf("column %q does not exist in table %q", to.columns[i], refTable.Name)
+		}
+		fk.Columns, fk.RefColumns = append(fk.Columns, c), append(fk.RefColumns, rc)
+	}
+	if fk.Symbol == "" {
+		fk.Symbol = t.Name + "_" + strings.Join(from.columns, "_") + "_fkey"
+	}
+	if slices.ContainsFunc(t.ForeignKeys, func(k *schema.ForeignKey) bool { return k.Symbol == fk.Symbol }) {
+		return fmt.Errorf("foreign key %q already exists on table %q", fk.Symbol, t.Name)
+	}
+	t.ForeignKeys = append(t.ForeignKeys, fk)
+	return nil
+}
+
+// shortName returns a name without its schema, e.g. users for auth.users.
+func shortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,190 @@
+package dbml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+)
+
+const doc = `
+Project shop {
+  database_type: 'PostgreSQL'
+  Note: 'The shop'
+}
+
+// The users of the shop.
+Table auth.users as U [note: 'users of the shop'] {
+  id bigint [pk, increment]
+  email varchar(255) [not null, unique, note: 'login email']
+  status user_status [not null, default: 'active']
+  score "double precision" [default: -1.5]
+  tags text[]
+  created_at timestamp [default: ` + "`now()`" + `]
+  Note {
+    '''
+    The users,
+    with their status.
+    '''
+  }
+}
+
+Table posts {
+  id bigint [pk]
+  user_id bigint [ref: > U.id]
+  title varchar(200)
+  /* the slug of the title */
+  slug varchar(200)
+
+  indexes {
+    (user_id, slug) [unique, name: 'posts_user_slug']
+    title
+    ` + "`lower(title)`" + `
+  }
+}
+
+Table tags {
+  id int [pk]
+  name varchar
+}
+
+Table post_tags {
+  post_id bigint
+  tag_id int
+
+  indexes {
+    (post_id, tag_id) [pk]
+  }
+}
+
+Enum user_status {
+  active
+  "disabled" [note: 'cannot sign in']
+}
+
+Ref post_tags_post: post_tags.post_id > posts.id [delete: cascade, update: no action]
+Ref {
+  tags.id < post_tags.tag_id
+  posts.id <> tags.id
+}
+
+TableGroup blog {
+  posts
+  tags
+}
+`
+
+func attr[T schema.Attr](attrs []schema.Attr) (T, bool) {
+	for _, a := range attrs {
+		if t, ok := a.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tbl := range s.Tables {
+		names = append(names, tbl.Name)
+	}
+	if want := []string{"users", "posts", "tags", "post_tags"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected the tables %v, got %v", want, names)
+	}
+
+	users, _ := s.Table("users")
+	if c, _ := attr[*schema.Comment](users.Attrs); c == nil || c.Text != "The users,\nwith their status." {
+		t.Errorf("expected the note of the table body to be the comment, got %+v", c)
+	}
+	id, _ := users.Column("id")
+	if _, ok := attr[*postgres.Identity](id.Attrs); !ok || id.Type.Null {
+		t.Errorf("expected id to be a not null identity column, got %+v", id)
+	}
+	if users.PrimaryKey == nil || users.PrimaryKey.Parts[0].C != id {
+		t.Errorf("expected id to be the primary key, got %+v", users.PrimaryKey)
+	}
+	email, _ := users.Column("email")
+	if c, _ := attr[*schema.Comment](email.Attrs); c == nil || c.Text != "login email" {
+		t.Errorf("expected the note of email to be its comment, got %+v", email.Attrs)
+	}
+	if len(email.Indexes) != 1 || !email.Indexes[0].Unique || email.Indexes[0].Name != "users_email_key" {
+		t.Errorf("expected email to be unique, got %+v", email.Indexes)
+	}
+	status, _ := users.Column("status")
+	if e, ok := status.Type.Type.(*schema.EnumType); !ok || !reflect.DeepEqual(e.Values, []string{"active", "disabled"}) {
+		t.Errorf("expected status to be the user_status enum, got %#v", status.Type.Type)
+	}
+	if d, ok := status.Default.(*schema.Literal); !ok || d.V != "'active'" {
+		t.Errorf("expected the default of status to be 'active', got %#v", status.Default)
+	}
+	score, _ := users.Column("score")
+	if _, ok := score.Type.Type.(*schema.FloatType); !ok || !score.Type.Null {
+		t.Errorf("expected score to be a nullable float, got %#v", score.Type)
+	}
+	if d, ok := score.Default.(*schema.Literal); !ok || d.V != "-1.5" {
+		t.Errorf("expected the default of score to be -1.5, got %#v", score.Default)
+	}
+	if tags, _ := users.Column("tags"); tags.Type.Raw != "text[]" {
+		t.Errorf("expected tags to be a text array, got %q", tags.Type.Raw)
+	}
+	if c, _ := users.Column("created_at"); c.Default == nil || c.Default.(*schema.RawExpr).X != "now()" {
+		t.Errorf("expected the default of created_at to be now(), got %#v", c.Default)
+	}
+
+	posts, _ := s.Table("posts")
+	if len(posts.ForeignKeys) != 1 {
+		t.Fatalf("expected the foreign key of user_id, got %+v", posts.ForeignKeys)
+	}
+	fk := posts.ForeignKeys[0]
+	if fk.Symbol != "posts_user_id_fkey" || fk.RefTable != users || fk.RefColumns[0] != id {
+		t.Errorf("expected user_id to reference users.id, got %+v", fk)
+	}
+	var indexes []string
+	for _, idx := range posts.Indexes {
+		indexes = append(indexes, idx.Name)
+	}
+	if want := []string{"posts_user_slug", "posts_title_idx", "posts_expr_idx"}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("expected the indexes %v, got %v", want, indexes)
+	}
+
+	postTags, _ := s.Table("post_tags")
+	if pk := postTags.PrimaryKey; pk == nil || len(pk.Parts) != 2 || pk.Parts[0].C.Type.Null {
+		t.Errorf("expected the composite primary key of post_tags, got %+v", pk)
+	}
+	var refs []string
+	for _, fk := range postTags.ForeignKeys {
+		refs = append(refs, fk.Symbol+"->"+fk.RefTable.Name+"/"+string(fk.OnDelete))
+	}
+	if want := []string{"post_tags_post->posts/CASCADE", "post_tags_tag_id_fkey->tags/"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("expected the foreign keys %v, got %v", want, refs)
+	}
+	if tags, _ := s.Table("tags"); len(tags.ForeignKeys) != 0 {
+		t.Errorf("expected the many-to-many relationship to have no foreign key, got %+v", tags.ForeignKeys)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "Table users {\n  id int [pk\n", want: "unterminated settings"},
+		{src: "Table users {\n  id int\n}\nRef: users.id > pets.owner_id", want: "line 4: table \"pets\" does not exist"},
+		{src: "Table users {\n  id int\n  indexes {\n    name\n  }\n}", want: "line 4: column \"name\" does not exist"},
+		{src: "Table users {\n  id int\n  id bigint\n}", want: "line 3: column \"id\" is defined twice"},
+		{src: "Note: 'unterminated", want: "unterminated '"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), "dbml: ") {
+			t.Errorf("Parse(%q): expected an error containing %q, got %v", tt.src, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package dbml
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // a keyword or a bare identifier
+	tokQuoted                  // a quoted identifier: "name"
+	tokString                  // a string: 'text' or '''text'''
+	tokExpr                    // an expression between backticks: `now()`
+	tokNumber                  // a numeric literal
+	tokPunct                   // a single punctuation character
+)
+
+type token struct {
+	kind tokenKind
+	// val is the text of the token, without its quotes.
+	val string
+	// line is the line of the token, to locate the errors.
+	line int
+}
+
+func (t token) is(keywords ...string) bool {
+	if t.kind != tokWord {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.val, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) punct(c string) bool {
+	return t.kind == tokPunct && t.val == c
+}
+
+func (t token) ident() bool {
+	return t.kind == tokWord || t.kind == tokQuoted
+}
+
+// tokenize splits a DBML document into tokens, dropping the comments.
+func tokenize(src string) ([]token, error) {
+	var (
+		tokens []token
+		s      = []rune(src)
+		line   = 1
+	)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(s) && s[i+1] == '/':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			j := i + 2
+			for j+1 < len(s) && (s[j] != '*' || s[j+1] != '/') {
+				j++
+			}
+			if j+1 >= len(s) {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(string(s[i:j]), "\n")
+			i = j + 2
+		case c == '\'' && i+2 < len(s) && s[i+1] == '\'' && s[i+2] == '\'':
+			end := strings.Index(string(s[i+3:]), "'''")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated multi-line string", line)
+			}
+			val := string(s[i+3:])[:end]
+			tokens = append(tokens, token{kind: tokString, val: trimIndent(val), line: line})
+			line += strings.Count(val, "\n")
+			i += 3 + len([]rune(val)) + 3
+		case c == '\'' || c == '"' || c == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteRune(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, c)
+			}
+			kind := map[rune]tokenKind{'\'': tokString, '"': tokQuoted, '`': tokExpr}[c]
+			tokens = append(tokens, token{kind: kind, val: b.String(), line: line})
+			line += strings.Count(b.String(), "\n")
+			i = j + 1
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(s) && (unicode.IsDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, val: string(s[i:j]), line: line})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(s[j]) || unicode.IsDigit(s[j]) || s[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, val: string(s[i:j]), line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokPunct, val: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// trimIndent removes the common indentation of the lines of a multi-line string.
+func trimIndent(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,651 @@
+package dbml
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// document is a parsed DBML document, before its names are resolved.
+type document struct {
+	// dbType is the database_type of the Project, e.g. "PostgreSQL".
+	dbType string
+	tables []*table
+	enums  []*enum
+	refs   []*ref
+}
+
+type table struct {
+	name    string
+	alias   string
+	note    string
+	columns []*column
+	indexes []*index
+	line    int
+}
+
+type column struct {
+	name      string
+	typ       string
+	note      string
+	pk        bool
+	notNull   bool
+	unique    bool
+	increment bool
+	def       []token
+	line      int
+}
+
+type index struct {
+	name   string
+	parts  []indexPart
+	pk     bool
+	unique bool
+	line   int
+}
+
+// indexPart is a column or an expression of an index.
+type indexPart struct {
+	column string
+	expr   string
+}
+
+type enum struct {
+	name   string
+	values []string
+}
+
+// ref is a relationship between the columns of two tables. The op is one of ">" (many-to-one),
+// "<" (one-to-many), "-" (one-to-one) and "<>" (many-to-many).
+type ref struct {
+	name     string
+	from, to endpoint
+	op       string
+	onDelete schema.ReferenceOption
+	onUpdate schema.ReferenceOption
+	line     int
+}
+
+type endpoint struct {
+	table   string
+	columns []string
+}
+
+// setting is an element of a settings list, e.g. "not null" or "note: 'text'".
+type setting struct {
+	key   string
+	value []token
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return token{kind: tokPunct}
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+	return t
+}
+
+// line returns the line of the next token, or of the last one at the end of the document.
+func (p *parser) line() int {
+	if p.eof() {
+		if len(p.toks) == 0 {
+			return 1
+		}
+		return p.toks[len(p.toks)-1].line
+	}
+	return p.peek().line
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line(), fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expect(c string) error {
+	if !p.peek().punct(c) {
+		if p.eof() {
+			return p.errorf("expected %q, got the end of the document", c)
+		}
+		return p.errorf("expected %q, got %q", c, p.peek().val)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	if !p.peek().ident() {
+		return "", p.errorf("expected a name, got %q", p.peek().val)
+	}
+	return p.next().val, nil
+}
+
+// qualified reads a name that may be qualified by its schema, e.g. auth.users.
+func (p *parser) qualified() (string, error) {
+	name, err := p.ident()
+	if err != nil {
+		return "", err
+	}
+	for p.peek().punct(".") && p.peekAt(1).ident() {
+		p.next()
+		name += "." + p.next().val
+	}
+	return name, nil
+}
+
+// skipBlock skips the tokens up to the next block and the block itself.
+func (p *parser) skipBlock() error {
+	for !p.eof() && !p.peek().punct("{") {
+		p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		if p.eof() {
+			return p.errorf("unterminated block")
+		}
+		switch t := p.next(); {
+		case t.punct("{"):
+			depth++
+		case t.punct("}"):
+			depth--
+		}
+	}
+	return nil
+}
+
+// note reads the string of a "Note: 'text'" or "Note { 'text' }" element.
+func (p *parser) note() (string, error) {
+	block := p.peek().punct("{")
+	p.next()
+	if p.peek().kind != tokString {
+		return "", p.errorf("expected the text of the note")
+	}
+	text := p.next().val
+	if block {
+		if err := p.expect("}"); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+// settings reads a settings list, e.g. [pk, not null, note: 'text'].
+func (p *parser) settings() ([]setting, error) {
+	if !p.peek().punct("[") {
+		return nil, nil
+	}
+	p.next()
+	var (
+		list  []setting
+		cur   setting
+		key   []string
+		value bool
+		depth int
+	)
+	flush := func() {
+		cur.key = strings.ToLower(strings.Join(key, " "))
+		if cur.key != "" || len(cur.value) > 0 {
+			list = append(list, cur)
+		}
+		cur, key, value = setting{}, nil, false
+	}
+	for {
+		if p.eof() {
+			return nil, p.errorf("unterminated settings")
+		}
+		t := p.next()
+		switch {
+		case depth == 0 && t.punct("]"):
+			flush()
+			return list, nil
+		case depth == 0 && t.punct(","):
+			flush()
+		case depth == 0 && t.punct(":") && !value:
+			value = true
+		case value:
+			if t.punct("(") || t.punct("[") {
+				depth++
+			} else if t.punct(")") || t.punct("]") {
+				depth--
+			}
+			cur.value = append(cur.value, t)
+		default:
+			key = append(key, t.val)
+		}
+	}
+}
+
+// parse parses a DBML document.
+func parse(src string) (*document, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	doc := &document{}
+	for !p.eof() {
+		switch t := p.next(); {
+		case t.is("Table"):
+			err = p.table(doc)
+		case t.is("Enum"):
+			err = p.enum(doc)
+		case t.is("Ref"):
+			err = p.refs(doc)
+		case t.is("Project"):
+			err = p.project(doc)
+		default:
+			// TableGroup, TablePartial, sticky notes and the other elements do not describe the tables.
+			err = p.skipBlock()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func (p *parser) project(doc *document) error {
+	for !p.eof() && !p.peek().punct("{") {
+		p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated Project")
+		}
+		if t := p.next(); t.is("database_type") && p.peek().punct(":") && p.peekAt(1).kind == tokString {
+			p.next()
+			doc.dbType = p.next().val
+		}
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) table(doc *document) error {
+	t := &table{line: p.line()}
+	var err error
+	if t.name, err = p.qualified(); err != nil {
+		return err
+	}
+	if p.peek().is("as") {
+		p.next()
+		if t.alias, err = p.ident(); err != nil {
+			return err
+		}
+	}
+	settings, err := p.settings()
+	if err != nil {
+		return err
+	}
+	for _, s := range settings {
+		if s.key == "note" && len(s.value) == 1 {
+			t.note = s.value[0].val
+		}
+	}
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated table %q", t.name)
+		}
+		switch next := p.peekAt(1); {
+		case p.peek().is("Note") && (next.punct(":") || next.punct("{")):
+			p.next()
+			if t.note, err = p.note(); err != nil {
+				return err
+			}
+		case p.peek().is("indexes") && next.punct("{"):
+			p.next()
+			err = p.indexes(t)
+		case p.peek().is("checks", "records") && (next.punct("{") || next.punct("(")):
+			err = p.skipBlock()
+		case p.peek().punct("~"):
+			// The injection of a TablePartial.
+			p.next()
+			_, err = p.ident()
+		default:
+			var c *column
+			if c, err = p.column(doc, t); err == nil {
+				t.columns = append(t.columns, c)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	p.next()
+	doc.tables = append(doc.tables, t)
+	return nil
+}
+
+func (p *parser) column(doc *document, t *table) (*column, error) {
+	c := &column{line: p.line()}
+	var err error
+	if c.name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if c.typ, err = p.columnType(); err != nil {
+		return nil, err
+	}
+	settings, err := p.settings()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range settings {
+		switch s.key {
+		case "pk", "primary key":
+			c.pk = true
+		case "not null":
+			c.notNull = true
+		case "null":
+			c.notNull = false
+		case "unique":
+			c.unique = true
+		case "increment":
+			c.increment = true
+		case "default":
+			c.def = s.value
+		case "note":
+			if len(s.value) == 1 {
+				c.note = s.value[0].val
+			}
+		case "ref":
+			r, err := inlineRef(c.name, s.value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", c.line, err)
+			}
+			r.from.table, r.line = t.name, c.line
+			doc.refs = append(doc.refs, r)
+		}
+	}
+	return c, nil
+}
+
+// columnType reads the type of a column, e.g. varchar(255), "double precision", int[] or auth.status.
+func (p *parser) columnType() (string, error) {
+	typ, err := p.qualified()
+	if err != nil {
+		return "", err
+	}
+	if p.peek().punct("(") {
+		p.next()
+		var args []string
+		for !p.peek().punct(")") {
+			if p.eof() {
+				return "", p.errorf("unterminated type %q", typ)
+			}
+			if t := p.next(); !t.punct(",") {
+				args = append(args, t.val)
+			}
+		}
+		p.next()
+		typ += "(" + strings.Join(args, ",") + ")"
+	}
+	if p.peek().punct("[") && p.peekAt(1).punct("]") {
+		p.pos += 2
+		typ += "[]"
+	}
+	return typ, nil
+}
+
+func (p *parser) indexes(t *table) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated indexes of table %q", t.name)
+		}
+		idx := &index{line: p.line()}
+		if p.peek().punct("(") {
+			p.next()
+			for !p.peek().punct(")") {
+				part, err := p.indexPart()
+				if err != nil {
+					return err
+				}
+				idx.parts = append(idx.parts, part)
+				if p.peek().punct(",") {
+					p.next()
+				}
+			}
+			p.next()
+		} else {
+			part, err := p.indexPart()
+			if err != nil {
+				return err
+			}
+			idx.parts = append(idx.parts, part)
+		}
+		settings, err := p.settings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			switch s.key {
+			case "pk":
+				idx.pk = true
+			case "unique":
+				idx.unique = true
+			case "name":
+				if len(s.value) == 1 {
+					idx.name = s.value[0].val
+				}
+			}
+		}
+		t.indexes = append(t.indexes, idx)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) indexPart() (indexPart, error) {
+	if p.peek().kind == tokExpr {
+		return indexPart{expr: p.next().val}, nil
+	}
+	name, err := p.ident()
+	if err != nil {
+		return indexPart{}, err
+	}
+	return indexPart{column: name}, nil
+}
+
+func (p *parser) enum(doc *document) error {
+	name, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	e := &enum{name: name}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated enum %q", name)
+		}
+		v, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if _, err = p.settings(); err != nil {
+			return err
+		}
+		e.values = append(e.values, v)
+	}
+	p.next()
+	doc.enums = append(doc.enums, e)
+	return nil
+}
+
+// refs reads a "Ref name: a.b > c.d" definition, or a "Ref name { ... }" block of them.
+func (p *parser) refs(doc *document) error {
+	var name string
+	if p.peek().ident() {
+		name = p.next().val
+	}
+	block := p.peek().punct("{")
+	if !block {
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+	} else {
+		p.next()
+	}
+	for {
+		r, err := p.ref()
+		if err != nil {
+			return err
+		}
+		if !block {
+			// The name of a block does not name its relationships, which would share it.
+			r.name = name
+		}
+		doc.refs = append(doc.refs, r)
+		if !block || p.peek().punct("}") {
+			break
+		}
+	}
+	if block {
+		return p.expect("}")
+	}
+	return nil
+}
+
+func (p *parser) ref() (*ref, error) {
+	r := &ref{line: p.line()}
+	var err error
+	if r.from, err = p.endpoint(); err != nil {
+		return nil, err
+	}
+	if r.op, err = p.op(); err != nil {
+		return nil, err
+	}
+	if r.to, err = p.endpoint(); err != nil {
+		return nil, err
+	}
+	settings, err := p.settings()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range settings {
+		switch s.key {
+		case "delete":
+			r.onDelete = referenceOption(s.value)
+		case "update":
+			r.onUpdate = referenceOption(s.value)
+		}
+	}
+	return r, nil
+}
+
+func (p *parser) op() (string, error) {
+	switch t := p.next(); {
+	case t.punct("<") && p.peek().punct(">"):
+		p.next()
+		return "<>", nil
+	case t.punct("<"), t.punct(">"), t.punct("-"):
+		return t.val, nil
+	default:
+		return "", fmt.Errorf("line %d: expected a relationship, got %q", t.line, t.val)
+	}
+}
+
+// endpoint reads the columns of a relationship, e.g. users.id, auth.users.id or merchants.(id, country).
+func (p *parser) endpoint() (endpoint, error) {
+	var parts []string
+	for {
+		if p.peek().punct("(") {
+			p.next()
+			var e endpoint
+			for !p.peek().punct(")") {
+				c, err := p.ident()
+				if err != nil {
+					return e, err
+				}
+				e.columns = append(e.columns, c)
+				if p.peek().punct(",") {
+					p.next()
+				}
+			}
+			p.next()
+			if len(parts) == 0 {
+				return e, p.errorf("the columns %v have no table", e.columns)
+			}
+			e.table = strings.Join(parts, ".")
+			return e, nil
+		}
+		name, err := p.ident()
+		if err != nil {
+			return endpoint{}, err
+		}
+		parts = append(parts, name)
+		if !p.peek().punct(".") {
+			break
+		}
+		p.next()
+	}
+	if len(parts) < 2 {
+		return endpoint{}, p.errorf("expected table.column, got %q", parts[0])
+	}
+	return endpoint{table: strings.Join(parts[:len(parts)-1], "."), columns: parts[len(parts)-1:]}, nil
+}
+
+// inlineRef reads the relationship of a column setting, e.g. "ref: > users.id".
+func inlineRef(column string, value []token) (*ref, error) {
+	p := &parser{toks: value}
+	op, err := p.op()
+	if err != nil {
+		return nil, err
+	}
+	to, err := p.endpoint()
+	if err != nil {
+		return nil, err
+	}
+	// The table of the column is set by the caller.
+	return &ref{from: endpoint{columns: []string{column}}, op: op, to: to}, nil
+}
+
+func referenceOption(value []token) schema.ReferenceOption {
+	var words []string
+	for _, t := range value {
+		words = append(words, strings.ToLower(t.val))
+	}
+	switch strings.Join(words, " ") {
+	case "cascade":
+		return schema.Cascade
+	case "restrict":
+		return schema.Restrict
+	case "set null":
+		return schema.SetNull
+	case "set default":
+		return schema.SetDefault
+	case "no action":
+		return schema.NoAction
+	}
+	return ""
+}
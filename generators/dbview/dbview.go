@@ -0,0 +1,132 @@
+// Package dbview lists the views of a database and their columns, so that the ent and protobuf
+// importers import the same views. The open source build of Atlas does not inspect the views.
+//
+// The views are the plain views of MySQL, PostgreSQL and SQLite. The materialized views of PostgreSQL
+// are left out: they cannot be created from their query alone, as the views of ent are.
+package dbview
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// View is a view of a database schema.
+type View struct {
+	Name string
+	// Def is the query of the view, e.g. "SELECT id, name FROM users".
+	Def     string
+	Comment string
+}
+
+// Column is a column of a view.
+type Column struct {
+	Name string
+	// Type is the column type, as formatted by the database, e.g. "character varying(64)".
+	Type    string
+	Null    bool
+	Comment string
+}
+
+// queries are the queries listing the views of a schema, and the columns of a view.
+type queries struct {
+	views   string
+	columns string
+	// qualified is set when the queries take the schema name as their first argument.
+	qualified bool
+}
+
+// dialects holds the queries of the dialects, named as by ent: "mysql", "postgres" and "sqlite3".
+var dialects = map[string]queries{
+	"mysql": {
+		qualified: true,
+		// The views of MySQL have no comment.
+		views:   "SELECT TABLE_NAME, VIEW_DEFINITION, '' FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME",
+		columns: "SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE = 'YES', COLUMN_COMMENT FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+	},
+	"postgres": {
+		qualified: true,
+		views: "SELECT c.relname, pg_get_viewdef(c.oid), COALESCE(obj_description(c.oid, 'pg_class'), '') FROM pg_class c " +
+			"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relkind = 'v' ORDER BY c.relname",
+		columns: "SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull, COALESCE(col_description(a.attrelid, a.attnum), '') " +
+			"FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+			"WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum",
+	},
+	"sqlite3": {
+		views:   "SELECT name, sql, '' FROM sqlite_master WHERE type = 'view' ORDER BY name",
+		columns: `SELECT name, type, "notnull" = 0, '' FROM pragma_table_info(?)`,
+	},
+}
+
+// sqliteViewDef matches the CREATE VIEW statement kept by SQLite, to extract the query of the view.
+var sqliteViewDef = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP(?:ORARY)?\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?.+?\s+AS\s+(.*?)\s*;?\s*$`)
+
+// Views returns the views of a database schema, ordered by name. SQLite has a single schema, so the
+// schema name is ignored there. The dialects that are not supported have no views.
+func Views(ctx context.Context, db *sql.DB, dialect, schemaName string) ([]View, error) {
+	q, ok := dialects[dialect]
+	if !ok {
+		return nil, nil
+	}
+	rows, err := db.QueryContext(ctx, q.views, q.args(schemaName)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var views []View
+	for rows.Next() {
+		var (
+			v            View
+			def, comment sql.NullString
+		)
+		if err = rows.Scan(&v.Name, &def, &comment); err != nil {
+			return nil, err
+		}
+		v.Def, v.Comment = query(dialect, def.String), comment.String
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+// query returns the query of a view definition. SQLite keeps the CREATE VIEW statement of the view.
+func query(dialect, def string) string {
+	if m := sqliteViewDef.FindStringSubmatch(def); dialect == "sqlite3" && m != nil {
+		def = m[1]
+	}
+	return strings.TrimSpace(def)
+}
+
+// Columns returns the columns of a view, in their order.
+func Columns(ctx context.Context, db *sql.DB, dialect, schemaName, view string) ([]Column, error) {
+	q, ok := dialects[dialect]
+	if !ok {
+		return nil, nil
+	}
+	rows, err := db.QueryContext(ctx, q.columns, append(q.args(schemaName), view)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []Column
+	for rows.Next() {
+		var (
+			c       Column
+			comment sql.NullString
+		)
+		if err = rows.Scan(&c.Name, &c.Type, &c.Null, &comment); err != nil {
+			return nil, err
+		}
+		c.Comment = comment.String
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// args returns the arguments of the queries of a schema.
+func (q queries) args(schemaName string) []any {
+	if q.qualified {
+		return []any{schemaName}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package dbview
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		dialect, def, want string
+	}{
+		{dialect: "sqlite3", def: "CREATE VIEW user_scores AS SELECT id, score FROM users", want: "SELECT id, score FROM users"},
+		{dialect: "sqlite3", def: "create temp view if not exists \"v\" as\n  select 1;", want: "select 1"},
+		{dialect: "postgres", def: " SELECT users.id\n   FROM users;", want: "SELECT users.id\n   FROM users;"},
+		{dialect: "mysql", def: "select `users`.`id` AS `id` from `users`", want: "select `users`.`id` AS `id` from `users`"},
+	}
+	for _, tt := range tests {
+		if got := query(tt.dialect, tt.def); got != tt.want {
+			t.Errorf("query(%q, %q) = %q, want %q", tt.dialect, tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	if args := dialects["postgres"].args("public"); len(args) != 1 || args[0] != "public" {
+		t.Errorf("expected the postgres queries to take the schema name, got %v", args)
+	}
+	if args := dialects["sqlite3"].args("main"); len(args) != 0 {
+		t.Errorf("expected the sqlite queries to take no schema name, got %v", args)
+	}
+}
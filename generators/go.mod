@@ -5,6 +5,23 @@ go 1.25.0
 require github.com/tx7do/go-utils v1.1.37
 
 require (
+	ariga.io/atlas v1.1.0
+	github.com/hashicorp/hcl/v2 v2.13.0
+	github.com/jinzhu/inflection v1.0.0
 	github.com/tx7do/go-utils/code_generator v0.0.1
 	golang.org/x/text v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
+	github.com/bmatcuk/doublestar v1.3.4 // indirect
+	github.com/go-openapi/inflect v0.19.0 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
+	github.com/zclconf/go-cty v1.14.4 // indirect
+	github.com/zclconf/go-cty-yaml v1.1.0 // indirect
+	golang.org/x/mod v0.34.0 // indirect
 )
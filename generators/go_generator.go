@@ -125,6 +125,11 @@ func (g *GoGenerator) GenerateEntRepo(ctx context.Context, opts code_generator.O
 		opts.OutputName = stringcase.ToSnakeCase(modelName) + "_repo.go"
 	}
 
+	// ent 不为视图生成写入的构造器，只读模型使用单独的只读仓库
+	if readOnly, _ := opts.Vars["ReadOnly"].(bool); readOnly {
+		return g.Generate(ctx, opts, "ent_view_repo.tpl")
+	}
+
 	return g.Generate(ctx, opts, "ent_repo.tpl")
 }
 
@@ -167,6 +172,38 @@ func (g *GoGenerator) GenerateGormRepo(ctx context.Context, opts code_generator.
 	return g.Generate(ctx, opts, "gorm_repo.tpl")
 }
 
+func (g *GoGenerator) GenerateBunClient(ctx context.Context, opts code_generator.Options) (outputPath string, err error) {
+	if g.CodeGenerator == nil {
+		return "", os.ErrInvalid
+	}
+	return g.Generate(ctx, opts, "bun_client.tpl")
+}
+
+func (g *GoGenerator) GenerateBunRepo(ctx context.Context, opts code_generator.Options) (outputPath string, err error) {
+	if g.CodeGenerator == nil {
+		return "", os.ErrInvalid
+	}
+
+	var modelName string
+	if v, ok := opts.Vars["Model"]; ok {
+		modelName, _ = v.(string)
+	}
+
+	if _, ok := opts.Vars["ClassName"]; !ok {
+		opts.Vars["ClassName"] = stringcase.ToPascalCase(modelName) + "Repo"
+	}
+
+	if _, ok := opts.Vars["ApiPackageVersion"]; !ok {
+		opts.Vars["ApiPackageVersion"] = "v1"
+	}
+
+	if opts.OutputName == "" {
+		opts.OutputName = stringcase.ToSnakeCase(modelName) + "_repo.go"
+	}
+
+	return g.Generate(ctx, opts, "bun_repo.tpl")
+}
+
 func (g *GoGenerator) GenerateGrpcServer(ctx context.Context, opts code_generator.Options) (outputPath string, err error) {
 	if g.CodeGenerator == nil {
 		return "", os.ErrInvalid
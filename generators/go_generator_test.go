@@ -1,9 +1,20 @@
 package generators
 
 import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/tx7do/go-utils/code_generator"
+
+	"github.com/tx7do/go-wind-toolkit/generators/annotation"
 )
 
 func TestGoGenerator_Template_Main(t *testing.T) {
@@ -93,6 +104,202 @@ func TestGoGenerator_Template_EntRepo(t *testing.T) {
 	}
 }
 
+func TestGoGenerator_Template_EntRepoSensitiveFields(t *testing.T) {
+	g := NewGoGenerator()
+
+	opts := code_generator.Options{
+		OutDir:     "./output",
+		OutputName: "account_repo.go",
+		Module:     "github.com/example/myproject",
+		Vars: map[string]any{
+			"Service":    "user",
+			"ApiPackage": "userV1",
+			"Model":      "account",
+			"Fields": DataFieldArray{
+				{Name: "username", Type: "string"},
+				{Name: "password_hash", Type: "string", Sensitive: true},
+			},
+		},
+	}
+
+	path, err := g.GenerateEntRepo(t.Context(), opts)
+	if err != nil {
+		t.Fatalf("Generate ent_repo.go failed: %v", err)
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(buf)
+	for _, want := range []string{
+		"dto.PasswordHash = nil",
+		"r.hideSensitive(item)",
+		"return r.hideSensitive(dto), err",
+		"return r.hideSensitive(r.mapper.ToDTO(ret)), nil",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("ent repo with a sensitive field misses %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "dto.Username") {
+		t.Errorf("ent repo hides a field that is not sensitive:\n%s", content)
+	}
+}
+
+func TestGoGenerator_Template_RepoEnumFields(t *testing.T) {
+	g := NewGoGenerator()
+
+	fields := DataFieldArray{
+		{Name: "status", Type: "string", Enum: []annotation.EnumValue{{Name: "ACTIVE", Number: 1}, {Name: "DISABLED", Number: 2}}},
+		{Name: "level", Type: "int32", Enum: []annotation.EnumValue{{Name: "LOW", Number: 1}, {Name: "HIGH", Number: 2}}, EnumNumbered: true},
+		{Name: "kind", Type: "int32", Enum: []annotation.EnumValue{{Name: "A", Number: 1}}},
+	}
+	tests := []struct {
+		generate func(context.Context, code_generator.Options) (string, error)
+		want     []string
+		notWant  []string
+	}{
+		{
+			generate: g.GenerateEntRepo,
+			want: []string{
+				`copierutil.NewEnumTypeConverterPair[userV1.Account_Status, account.Status](
+		map[int32]string{1: "ACTIVE", 2: "DISABLED"},
+		map[string]int32{"ACTIVE": 1, "DISABLED": 2},
+	))`,
+				`copierutil.NewEnumTypeConverterPair[userV1.Account_Level, account.Level](
+		map[int32]string{1: "1", 2: "2"},
+		map[string]int32{"1": 1, "2": 2},
+	))`,
+			},
+			// The integer column of an enum without numbers stays an integer field.
+			notWant: []string{"Account_Kind"},
+		},
+		{
+			generate: g.GenerateGormRepo,
+			want: []string{
+				`copierutil.NewEnumTypeConverterPair[userV1.Account_Status, string](
+		map[int32]string{1: "ACTIVE", 2: "DISABLED"},
+		map[string]int32{"ACTIVE": 1, "DISABLED": 2},
+	))`,
+			},
+			// The integer columns are converted by reflect.
+			notWant: []string{"Account_Level", "Account_Kind"},
+		},
+	}
+	for i, tt := range tests {
+		opts := code_generator.Options{
+			OutDir:     "./output",
+			OutputName: fmt.Sprintf("account_enum_repo_%d.go", i),
+			Module:     "github.com/example/myproject",
+			Vars: map[string]any{
+				"Service":    "user",
+				"ApiPackage": "userV1",
+				"Model":      "account",
+				"Fields":     fields,
+			},
+		}
+		path, err := tt.generate(t.Context(), opts)
+		if err != nil {
+			t.Fatalf("Generate repo failed: %v", err)
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(buf)
+		for _, want := range tt.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("repo misses the enum converter %q:\n%s", want, content)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(content, notWant) {
+				t.Errorf("repo has an unexpected enum converter %q:\n%s", notWant, content)
+			}
+		}
+	}
+}
+
+func TestGoGenerator_Template_EntViewRepo(t *testing.T) {
+	g := NewGoGenerator()
+
+	for _, fields := range []DataFieldArray{
+		{{Name: "id", Type: "uint32"}, {Name: "score", Type: "int64"}, {Name: "token", Type: "string", Sensitive: true}},
+		{{Name: "total", Type: "int64"}},
+	} {
+		opts := code_generator.Options{
+			OutDir:     "./output",
+			OutputName: "user_score_repo.go",
+			Module:     "github.com/example/myproject",
+			Vars: map[string]any{
+				"Service":    "report",
+				"ApiPackage": "reportV1",
+				"Model":      "user_score",
+				"Fields":     fields,
+				"ReadOnly":   true,
+			},
+		}
+
+		path, err := g.GenerateEntRepo(t.Context(), opts)
+		if err != nil {
+			t.Fatalf("Generate ent_repo.go failed: %v", err)
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(buf)
+		assertImportsUsed(t, content)
+
+		// ent 只为视图生成 Query、Select、GroupBy 和 Client
+		for _, s := range []string{
+			"UserScoreCreate", "UserScoreCreateBulk", "UserScoreUpdate", "UserScoreUpdateOne", "UserScoreDelete",
+			"entCrud.Repository", "entCrud.NewRepository", "IDEQ", ") Create(", ") Update(", ") Delete(",
+		} {
+			if strings.Contains(content, s) {
+				t.Errorf("ent repo of a view contains %q:\n%s", s, content)
+			}
+		}
+		for _, s := range []string{") List(", ") Count(", ") Get(", ".UserScore.Query()"} {
+			if !strings.Contains(content, s) {
+				t.Errorf("ent repo of a view misses %q:\n%s", s, content)
+			}
+		}
+		if fields.HasField("id") != strings.Contains(content, "userscore.FieldID") {
+			t.Errorf("ent repo of a view queries by a missing id column:\n%s", content)
+		}
+	}
+}
+
+// assertImportsUsed 检查生成的代码能够解析，并且没有未使用的导入
+func assertImportsUsed(t *testing.T, content string) {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, 0)
+	if err != nil {
+		t.Fatalf("parse generated code failed: %v\n%s", err, content)
+	}
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !used[name] {
+			t.Errorf("generated code does not use the import %q:\n%s", importPath, content)
+		}
+	}
+}
+
 func TestGoGenerator_Template_GormClient(t *testing.T) {
 	g := NewGoGenerator()
 
@@ -144,6 +351,40 @@ func TestGoGenerator_Template_GormRepo(t *testing.T) {
 	}
 }
 
+func TestGoGenerator_Template_BunClient(t *testing.T) {
+	g := NewGoGenerator()
+
+	opts := code_generator.Options{
+		OutDir: "./output",
+		Module: "github.com/example/myproject",
+		Vars: map[string]any{
+			"Service": "user",
+		},
+	}
+
+	if _, err := g.GenerateBunClient(t.Context(), opts); err != nil {
+		t.Fatalf("Generate bun_client.go failed: %v", err)
+	}
+}
+
+func TestGoGenerator_Template_BunRepo(t *testing.T) {
+	g := NewGoGenerator()
+
+	opts := code_generator.Options{
+		OutDir: "./output",
+		Module: "github.com/example/myproject",
+		Vars: map[string]any{
+			"Service":    "user",
+			"ApiPackage": "userV1",
+			"Model":      "user",
+		},
+	}
+
+	if _, err := g.GenerateBunRepo(t.Context(), opts); err != nil {
+		t.Fatalf("Generate bun_repo.go failed: %v", err)
+	}
+}
+
 func TestGoGenerator_Template_GrpcServer(t *testing.T) {
 	g := NewGoGenerator()
 
@@ -238,3 +479,44 @@ func TestGoGenerator_Template_Service(t *testing.T) {
 		t.Fatalf("Generate service.go failed: %v", err)
 	}
 }
+
+func TestGoGenerator_Template_ReadOnlyService(t *testing.T) {
+	g := NewGoGenerator()
+
+	for _, isGrpc := range []bool{true, false} {
+		opts := code_generator.Options{
+			OutDir: "./output",
+			Module: "github.com/example/myproject",
+			Vars: map[string]any{
+				"TargetApiPackageName":    "report",
+				"TargetApiPackageVersion": "v1",
+
+				"SourceApiPackageName":    "report",
+				"SourceApiPackageVersion": "v1",
+
+				"Service":  "report",
+				"Model":    "user_score",
+				"IsGrpc":   isGrpc,
+				"ReadOnly": true,
+			},
+		}
+
+		path, err := g.GenerateService(t.Context(), opts)
+		if err != nil {
+			t.Fatalf("Generate service.go failed: %v", err)
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(buf)
+		for _, s := range []string{") Create(", ") Update(", ") Delete(", "emptypb", "middleware/auth"} {
+			if strings.Contains(content, s) {
+				t.Errorf("service of a read-only model contains %q:\n%s", s, content)
+			}
+		}
+		if !strings.Contains(content, ") List(") || !strings.Contains(content, ") Get(") {
+			t.Errorf("service of a read-only model misses List or Get:\n%s", content)
+		}
+	}
+}
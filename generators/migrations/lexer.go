@@ -0,0 +1,222 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // a keyword or a bare identifier
+	tokQuoted                  // a quoted identifier: `name`, "name" or [name]
+	tokString                  // a string literal: 'text', E'text' or $$text$$
+	tokNumber                  // a numeric literal
+	tokPunct                   // a single punctuation character
+)
+
+type token struct {
+	kind tokenKind
+	// raw is the text of the token as written in the migration.
+	raw string
+	// val is the unquoted value of identifiers and strings.
+	val string
+}
+
+// is reports whether the token is one of the given keywords, case-insensitively.
+func (t token) is(keywords ...string) bool {
+	if t.kind != tokWord {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.raw, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) punct(c string) bool {
+	return t.kind == tokPunct && t.raw == c
+}
+
+// ident reports whether the token can name a table, a column or an index.
+func (t token) ident() bool {
+	return t.kind == tokWord || t.kind == tokQuoted
+}
+
+// statements splits the SQL text into statements of tokens, dropping the comments.
+func statements(sql string) ([][]token, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		stmts [][]token
+		stmt  []token
+	)
+	for _, t := range tokens {
+		if t.punct(";") {
+			if len(stmt) > 0 {
+				stmts = append(stmts, stmt)
+			}
+			stmt = nil
+			continue
+		}
+		stmt = append(stmt, t)
+	}
+	if len(stmt) > 0 {
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
+}
+
+// tokenize splits the SQL text into tokens. It understands the quoting and the comments of MySQL,
+// PostgreSQL and SQLite, including the dollar-quoted strings of the PostgreSQL function bodies.
+func tokenize(sql string) ([]token, error) {
+	var (
+		tokens []token
+		s      = []rune(sql)
+	)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '-' && i+1 < len(s) && s[i+1] == '-', c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			j := i + 2
+			for j+1 < len(s) && (s[j] != '*' || s[j+1] != '/') {
+				j++
+			}
+			if j+1 >= len(s) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = j + 2
+		case c == '\'':
+			val, n, err := quoted(s[i:], '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, raw: string(s[i : i+n]), val: val})
+			i += n
+		case (c == 'E' || c == 'e' || c == 'N' || c == 'n') && i+1 < len(s) && s[i+1] == '\'':
+			val, n, err := quoted(s[i+1:], '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, raw: string(s[i+1 : i+1+n]), val: val})
+			i += 1 + n
+		case c == '`', c == '"':
+			val, n, err := quoted(s[i:], c)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokQuoted, raw: string(s[i : i+n]), val: val})
+			i += n
+		case c == '[':
+			// A bracketed identifier of SQLite, or the brackets of a PostgreSQL array type.
+			end := indexRune(s[i:], ']')
+			if end > 1 && !unicode.IsDigit(s[i+1]) && isIdent(s[i+1:i+end]) {
+				tokens = append(tokens, token{kind: tokQuoted, raw: string(s[i : i+end+1]), val: string(s[i+1 : i+end])})
+				i += end + 1
+				continue
+			}
+			tokens = append(tokens, token{kind: tokPunct, raw: "["})
+			i++
+		case c == '$' && i+1 < len(s) && (s[i+1] == '$' || unicode.IsLetter(s[i+1]) || s[i+1] == '_'):
+			end := indexRune(s[i+1:], '$')
+			if end < 0 || !isIdent(s[i+1:i+1+end]) && end > 0 {
+				tokens = append(tokens, token{kind: tokPunct, raw: "$"})
+				i++
+				continue
+			}
+			tag := string(s[i : i+end+2])
+			body := string(s[i+len([]rune(tag)):])
+			n := strings.Index(body, tag)
+			if n < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string %s", tag)
+			}
+			val := body[:n]
+			raw := tag + val + tag
+			tokens = append(tokens, token{kind: tokString, raw: raw, val: val})
+			i += len([]rune(raw))
+		case unicode.IsDigit(c) || c == '.' && i+1 < len(s) && unicode.IsDigit(s[i+1]):
+			j := i
+			for j < len(s) && (unicode.IsDigit(s[j]) || s[j] == '.' || s[j] == 'e' || s[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, raw: string(s[i:j]), val: string(s[i:j])})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(s[j]) || unicode.IsDigit(s[j]) || s[j] == '_' || s[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, raw: string(s[i:j]), val: string(s[i:j])})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokPunct, raw: string(c), val: string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// quoted reads the quoted text at the start of s, where a doubled quote stands for the quote itself.
+// Backslash escapes are honored in strings, as MySQL does. It returns the unquoted value and the
+// length of the quoted text.
+func quoted(s []rune, q rune) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && q == '\'' && i+1 < len(s):
+			i++
+			b.WriteRune(unescape(s[i]))
+		case s[i] == q && i+1 < len(s) && s[i+1] == q:
+			i++
+			b.WriteRune(q)
+		case s[i] == q:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted text %s", string(s[:min(len(s), 20)]))
+}
+
+func unescape(c rune) rune {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	}
+	return c
+}
+
+func indexRune(s []rune, c rune) int {
+	for i, r := range s {
+		if r == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func isIdent(s []rune) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+// Package migrations builds a database schema from a folder of SQL migrations, by replaying their DDL
+// statements in version order into an in-memory Atlas schema. It lets the importers read the schema of
+// a project whose source of truth is its migrations rather than a database or a single DDL file.
+//
+// The folder may follow the naming of goose ("00001_create_users.sql", with its "-- +goose Up" and
+// "-- +goose Down" sections), golang-migrate ("1_create_users.up.sql" and "1_create_users.down.sql")
+// or Flyway ("V1__create_users.sql", "V1.1__add_email.sql" and the repeatable "R__views.sql"). The
+// down and undo migrations are skipped.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// Scheme is the DSN scheme of a folder of migrations, e.g. "migrations://db/migrations".
+const Scheme = "migrations"
+
+// File is a migration file of a folder.
+type File struct {
+	// Name is the base name of the file.
+	Name string
+	// Path is the path of the file.
+	Path string
+	// Version is the version of the migration. It is empty for the repeatable Flyway migrations, which
+	// are applied after the versioned ones.
+	Version string
+}
+
+var (
+	// reFlyway matches the versioned, the undo and the repeatable migrations of Flyway.
+	reFlyway = regexp.MustCompile(`^([VUR])(\d+(?:[._]\d+)*)?__.*\.sql$`)
+	// reGolangMigrate matches the up and down migrations of golang-migrate.
+	reGolangMigrate = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+	// reGoose matches the migrations of goose, and the plain numbered migrations, e.g. of Atlas.
+	reGoose = regexp.MustCompile(`^(\d+)(?:_.*)?\.sql$`)
+
+	reGooseUp    = regexp.MustCompile(`(?m)^\s*--\s*\+goose\s+Up\b.*$`)
+	reGooseDown  = regexp.MustCompile(`(?m)^\s*--\s*\+goose\s+Down\b.*$`)
+	reGooseBegin = regexp.MustCompile(`(?m)^\s*--\s*\+goose\s+StatementBegin\b.*$`)
+	reGooseEnd   = regexp.MustCompile(`(?m)^\s*--\s*\+goose\s+StatementEnd\b.*$`)
+)
+
+// Files returns the up migrations of the folder, in the order they are applied.
+func Files(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: %w", err)
+	}
+	var versioned, repeatable []File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(name), ".sql") {
+			continue
+		}
+		f := File{Name: name, Path: filepath.Join(dir, name)}
+		switch m := reFlyway.FindStringSubmatch(name); {
+		case m != nil && m[1] == "V" && m[2] != "":
+			f.Version = m[2]
+		case m != nil && m[1] == "R" && m[2] == "":
+			repeatable = append(repeatable, f)
+			continue
+		case m != nil:
+			// The undo migrations of Flyway.
+			continue
+		default:
+			if m := reGolangMigrate.FindStringSubmatch(name); m != nil {
+				if m[2] == "down" {
+					continue
+				}
+				f.Version = m[1]
+			} else if m := reGoose.FindStringSubmatch(name); m != nil {
+				f.Version = m[1]
+			} else {
+				return nil, fmt.Errorf("migrations: %s: the file name has no version", name)
+			}
+		}
+		versioned = append(versioned, f)
+	}
+	slices.SortStableFunc(versioned, func(a, b File) int {
+		return compareVersions(a.Version, b.Version)
+	})
+	for i := 1; i < len(versioned); i++ {
+		if compareVersions(versioned[i-1].Version, versioned[i].Version) == 0 {
+			return nil, fmt.Errorf("migrations: %s and %s have the same version", versioned[i-1].Name, versioned[i].Name)
+		}
+	}
+	slices.SortFunc(repeatable, func(a, b File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return append(versioned, repeatable...), nil
+}
+
+// compareVersions compares two versions part by part, numerically, so that 2 comes before 10 and
+// 1.2 before 1.10. The parts are separated by dots or underscores, as in Flyway.
+func compareVersions(a, b string) int {
+	pa, pb := strings.FieldsFunc(a, isSeparator), strings.FieldsFunc(b, isSeparator)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		x, y := strings.TrimLeft(pa[i], "0"), strings.TrimLeft(pb[i], "0")
+		if len(x) != len(y) {
+			return len(x) - len(y)
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+func isSeparator(r rune) bool {
+	return r == '.' || r == '_'
+}
+
+// Load replays the up migrations of the folder and returns the resulting schema.
+func Load(dir string) (*schema.Schema, error) {
+	files, err := Files(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("migrations: no migration files in %s", dir)
+	}
+	r := newReplayer()
+	for _, f := range files {
+		content, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: %w", err)
+		}
+		if err = r.file(string(content)); err != nil {
+			return nil, fmt.Errorf("migrations: %s: %w", f.Name, err)
+		}
+	}
+	s, err := r.link()
+	if err != nil {
+		return nil, fmt.Errorf("migrations: %w", err)
+	}
+	return s, nil
+}
+
+// Parse replays the statements of a single SQL text and returns the resulting schema.
+func Parse(sql string) (*schema.Schema, error) {
+	r := newReplayer()
+	if err := r.file(sql); err != nil {
+		return nil, fmt.Errorf("migrations: %w", err)
+	}
+	s, err := r.link()
+	if err != nil {
+		return nil, fmt.Errorf("migrations: %w", err)
+	}
+	return s, nil
+}
+
+// file replays a migration file. Only the Up section of a goose migration is replayed, and a goose
+// StatementBegin/StatementEnd block is a single statement.
+func (r *replayer) file(content string) error {
+	if loc := reGooseUp.FindStringIndex(content); loc != nil {
+		content = content[loc[1]:]
+		if loc = reGooseDown.FindStringIndex(content); loc != nil {
+			content = content[:loc[0]]
+		}
+	}
+	for content != "" {
+		begin := reGooseBegin.FindStringIndex(content)
+		if begin == nil {
+			return r.exec(content)
+		}
+		if err := r.exec(content[:begin[0]]); err != nil {
+			return err
+		}
+		content = content[begin[1]:]
+		end := reGooseEnd.FindStringIndex(content)
+		if end == nil {
+			return errors.New("StatementBegin without StatementEnd")
+		}
+		if err := r.block(content[:end[0]]); err != nil {
+			return err
+		}
+		content = content[end[1]:]
+	}
+	return nil
+}
+
+// block replays a goose StatementBegin/StatementEnd block as a single statement.
+func (r *replayer) block(sql string) error {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return err
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].punct(";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.apply(tokens)
+}
@@ -0,0 +1,304 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "goose",
+			files: []string{"00010_add_email.sql", "00002_create_pets.sql", "00001_create_users.sql"},
+			want:  []string{"00001_create_users.sql", "00002_create_pets.sql", "00010_add_email.sql"},
+		},
+		{
+			name:  "golang-migrate",
+			files: []string{"10_add_email.up.sql", "10_add_email.down.sql", "2_create_users.up.sql", "2_create_users.down.sql"},
+			want:  []string{"2_create_users.up.sql", "10_add_email.up.sql"},
+		},
+		{
+			name:  "flyway",
+			files: []string{"R__views.sql", "V1.10__c.sql", "V1.2__b.sql", "V1__a.sql", "U1.2__b.sql", "README.md"},
+			want:  []string{"V1__a.sql", "V1.2__b.sql", "V1.10__c.sql", "R__views.sql"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := make(map[string]string)
+			for _, name := range tt.files {
+				files[name] = ""
+			}
+			got, err := Files(writeFiles(t, files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, f := range got {
+				names = append(names, f.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Files() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	if _, err := Files(writeFiles(t, map[string]string{"1_a.sql": "", "001_b.sql": ""})); err == nil {
+		t.Error("Files() with duplicate versions: expected an error")
+	}
+	if _, err := Files(writeFiles(t, map[string]string{"schema.sql": ""})); err == nil {
+		t.Error("Files() with an unversioned file: expected an error")
+	}
+}
+
+// attr returns the attribute of type T.
+func attr[T schema.Attr](attrs []schema.Attr) (T, bool) {
+	for _, a := range attrs {
+		if v, ok := a.(T); ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func columnNames(t *schema.Table) []string {
+	var names []string
+	for _, c := range t.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func mustTable(t *testing.T, s *schema.Schema, name string) *schema.Table {
+	t.Helper()
+	tbl, ok := s.Table(name)
+	if !ok {
+		t.Fatalf("table %q is missing", name)
+	}
+	return tbl
+}
+
+func TestLoadGoose(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"00001_create_users.sql": `
+-- +goose Up
+CREATE TABLE users (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(64) NOT NULL,
+  legacy TEXT
+);
+-- +goose Down
+DROP TABLE users;
+`,
+		"00002_create_pets.sql": `
+-- +goose Up
+CREATE TABLE pets (
+  id BIGSERIAL PRIMARY KEY,
+  owner_id BIGINT NOT NULL,
+  name TEXT
+);
+ALTER TABLE pets ADD CONSTRAINT pets_owner_fk FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX pets_owner_name ON pets (owner_id, name);
+-- +goose StatementBegin
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.name = lower(NEW.name);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+-- +goose Down
+DROP TABLE pets;
+`,
+		"00003_alter_users.sql": `
+-- +goose Up
+ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users DROP COLUMN legacy;
+ALTER TABLE users ALTER COLUMN name TYPE TEXT, ALTER COLUMN name DROP NOT NULL;
+CREATE TABLE tmp (id INT);
+DROP TABLE IF EXISTS tmp;
+COMMENT ON COLUMN users.email IS 'login email';
+-- +goose Down
+ALTER TABLE users DROP COLUMN email;
+`,
+	})
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Tables) != 2 {
+		t.Fatalf("got %d tables, want 2", len(s.Tables))
+	}
+
+	users := mustTable(t, s, "users")
+	if got, want := columnNames(users), []string{"id", "name", "email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("users columns = %v, want %v", got, want)
+	}
+	name, _ := users.Column("name")
+	if name.Type.Raw != "text" || !name.Type.Null {
+		t.Errorf("users.name = %s null=%v, want a nullable text", name.Type.Raw, name.Type.Null)
+	}
+	email, _ := users.Column("email")
+	if email.Type.Null {
+		t.Error("users.email is nullable")
+	}
+	if d, ok := email.Default.(*schema.Literal); !ok || d.V != "''" {
+		t.Errorf("users.email default = %#v, want ''", email.Default)
+	}
+	if c, ok := attr[*schema.Comment](email.Attrs); !ok || c.Text != "login email" {
+		t.Errorf("users.email has no comment 'login email'")
+	}
+	id, _ := users.Column("id")
+	if _, ok := id.Type.Type.(*postgres.SerialType); !ok || id.Type.Null {
+		t.Errorf("users.id = %T null=%v, want a not null serial", id.Type.Type, id.Type.Null)
+	}
+
+	pets := mustTable(t, s, "pets")
+	if len(pets.ForeignKeys) != 1 {
+		t.Fatalf("pets has %d foreign keys, want 1", len(pets.ForeignKeys))
+	}
+	fk := pets.ForeignKeys[0]
+	if fk.Symbol != "pets_owner_fk" || fk.RefTable != users || fk.RefColumns[0] != id || fk.OnDelete != schema.Cascade {
+		t.Errorf("unexpected foreign key %s -> %v", fk.Symbol, fk.RefTable)
+	}
+	idx, ok := pets.Index("pets_owner_name")
+	if !ok || !idx.Unique || len(idx.Parts) != 2 {
+		t.Fatalf("unexpected index %#v", idx)
+	}
+	owner, _ := pets.Column("owner_id")
+	if len(owner.Indexes) != 1 || len(owner.ForeignKeys) != 1 {
+		t.Errorf("pets.owner_id has %d indexes and %d foreign keys, want 1 and 1", len(owner.Indexes), len(owner.ForeignKeys))
+	}
+}
+
+func TestParseMySQL(t *testing.T) {
+	s, err := Parse("" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(32) NOT NULL COMMENT 'user''s name',\n" +
+		"  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `uk_name` (`name`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='users';\n" +
+		"CREATE TABLE orders (id int PRIMARY KEY, user_id int unsigned, KEY (user_id), CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));\n" +
+		"ALTER TABLE `users` ADD COLUMN `nick` varchar(16) NULL AFTER `id`, MODIFY COLUMN `name` varchar(64) NOT NULL COMMENT 'name';\n" +
+		"ALTER TABLE users CHANGE updated_at modified_at datetime NOT NULL;\n" +
+		"ALTER TABLE users DROP INDEX uk_name, ADD UNIQUE INDEX uk_nick (nick);\n" +
+		"ALTER TABLE orders DROP FOREIGN KEY fk_user;\n" +
+		"RENAME TABLE orders TO purchases;\n" +
+		"INSERT INTO users (name) VALUES ('a;b');\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := mustTable(t, s, "users")
+	if got, want := columnNames(users), []string{"id", "nick", "name", "modified_at"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("users columns = %v, want %v", got, want)
+	}
+	if c, ok := attr[*schema.Comment](users.Attrs); !ok || c.Text != "users" {
+		t.Errorf("users has no comment 'users'")
+	}
+	id, _ := users.Column("id")
+	if typ, ok := id.Type.Type.(*schema.IntegerType); !ok || !typ.Unsigned {
+		t.Errorf("users.id = %s, want an int unsigned", id.Type.Raw)
+	}
+	if _, ok := attr[*mysql.AutoIncrement](id.Attrs); !ok {
+		t.Error("users.id is not auto-increment")
+	}
+	name, _ := users.Column("name")
+	if c, ok := attr[*schema.Comment](name.Attrs); name.Type.Raw != "varchar(64)" || !ok || c.Text != "name" {
+		t.Errorf("users.name = %s, want a varchar(64) commented 'name'", name.Type.Raw)
+	}
+	modified, _ := users.Column("modified_at")
+	if modified.Type.Null || modified.Default != nil {
+		t.Errorf("users.modified_at is nullable or has a default")
+	}
+	if len(users.Indexes) != 1 || users.Indexes[0].Name != "uk_nick" {
+		t.Errorf("users indexes = %v, want uk_nick", users.Indexes)
+	}
+
+	purchases := mustTable(t, s, "purchases")
+	if len(purchases.ForeignKeys) != 0 || len(purchases.Indexes) != 1 {
+		t.Errorf("purchases has %d foreign keys and %d indexes, want 0 and 1", len(purchases.ForeignKeys), len(purchases.Indexes))
+	}
+}
+
+func TestParsePostgresEnum(t *testing.T) {
+	s, err := Parse(`
+CREATE TYPE "status" AS ENUM ('active', 'blocked');
+CREATE TABLE public.accounts (
+  id integer GENERATED ALWAYS AS IDENTITY,
+  status status NOT NULL DEFAULT 'active'::status,
+  CONSTRAINT accounts_pk PRIMARY KEY (id)
+);
+ALTER TYPE status ADD VALUE 'deleted' AFTER 'blocked';
+ALTER TABLE ONLY public.accounts RENAME COLUMN status TO state;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := mustTable(t, s, "accounts")
+	state, ok := accounts.Column("state")
+	if !ok {
+		t.Fatal("accounts.state is missing")
+	}
+	e, ok := state.Type.Type.(*schema.EnumType)
+	if !ok || !reflect.DeepEqual(e.Values, []string{"active", "blocked", "deleted"}) {
+		t.Errorf("accounts.state = %#v, want the status enum", state.Type.Type)
+	}
+	if accounts.PrimaryKey == nil || accounts.PrimaryKey.Name != "accounts_pk" {
+		t.Error("accounts has no primary key accounts_pk")
+	}
+	id, _ := accounts.Column("id")
+	if _, ok := attr[*postgres.Identity](id.Attrs); !ok {
+		t.Error("accounts.id is not an identity column")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{"missing table", "ALTER TABLE users ADD COLUMN name text;"},
+		{"missing column", "CREATE TABLE users (id int); ALTER TABLE users DROP COLUMN name;"},
+		{"duplicate column", "CREATE TABLE users (id int); ALTER TABLE users ADD id int;"},
+		{"missing referenced table", "CREATE TABLE pets (owner_id int REFERENCES users (id));"},
+		{"unterminated string", "CREATE TABLE users (name text DEFAULT 'x);"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.sql); err == nil {
+				t.Errorf("Parse(%q): expected an error", tt.sql)
+			}
+		})
+	}
+
+	// IF EXISTS and IF NOT EXISTS make the statements idempotent.
+	if _, err := Parse("CREATE TABLE IF NOT EXISTS t (id int); CREATE TABLE IF NOT EXISTS t (id int); " +
+		"ALTER TABLE IF EXISTS missing ADD c int; ALTER TABLE t DROP COLUMN IF EXISTS c; DROP TABLE IF EXISTS missing;"); err != nil {
+		t.Error(err)
+	}
+}
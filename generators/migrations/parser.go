@@ -0,0 +1,191 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parser reads the tokens of a statement, or of a part of it.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+// peek returns the next token, or the zero token at the end of the statement.
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return token{}
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+	return t
+}
+
+// rest returns the tokens left in the statement.
+func (p *parser) rest() []token {
+	toks := p.toks[p.pos:]
+	p.pos = len(p.toks)
+	return toks
+}
+
+// accept consumes the given sequence of keywords if the next tokens match it.
+func (p *parser) accept(keywords ...string) bool {
+	for i, k := range keywords {
+		if !p.peekAt(i).is(k) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *parser) acceptPunct(c string) bool {
+	if p.peek().punct(c) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(keywords ...string) error {
+	if !p.accept(keywords...) {
+		return fmt.Errorf("expected %s, got %q", strings.Join(keywords, " "), p.peek().raw)
+	}
+	return nil
+}
+
+// qualified reads a name qualified by dots, e.g. public.users, and returns its parts.
+func (p *parser) qualified() ([]string, error) {
+	var parts []string
+	for {
+		t := p.next()
+		if !t.ident() || t.raw == "" {
+			return nil, fmt.Errorf("expected a name, got %q", t.raw)
+		}
+		parts = append(parts, t.val)
+		if !p.peek().punct(".") {
+			return parts, nil
+		}
+		p.next()
+	}
+}
+
+// name reads a possibly qualified name and returns it without its schema, as the migrations are
+// replayed into a single schema.
+func (p *parser) name() (string, error) {
+	parts, err := p.qualified()
+	if err != nil {
+		return "", err
+	}
+	return parts[len(parts)-1], nil
+}
+
+// names reads a list of names separated by commas.
+func (p *parser) names() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.acceptPunct(",") {
+			return names, nil
+		}
+	}
+}
+
+// group reads a parenthesized group and returns the tokens between the parentheses.
+func (p *parser) group() ([]token, error) {
+	if !p.peek().punct("(") {
+		return nil, fmt.Errorf("expected (, got %q", p.peek().raw)
+	}
+	start, depth := p.pos+1, 0
+	for !p.eof() {
+		t := p.next()
+		switch {
+		case t.punct("("):
+			depth++
+		case t.punct(")"):
+			if depth--; depth == 0 {
+				return p.toks[start : p.pos-1], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unbalanced parentheses")
+}
+
+// until reads the tokens up to the end of the statement or to the first keyword, outside parentheses,
+// for which stop returns true. The first token is always read.
+func (p *parser) until(stop func(p *parser) bool) []token {
+	start, depth := p.pos, 0
+	for !p.eof() {
+		t := p.peek()
+		if depth == 0 && p.pos > start && stop(p) {
+			break
+		}
+		switch {
+		case t.punct("("):
+			depth++
+		case t.punct(")"):
+			depth--
+		}
+		p.next()
+	}
+	return p.toks[start:p.pos]
+}
+
+// split splits the tokens on the commas that are outside parentheses.
+func split(toks []token) [][]token {
+	var (
+		parts [][]token
+		start int
+		depth int
+	)
+	for i, t := range toks {
+		switch {
+		case t.punct("("):
+			depth++
+		case t.punct(")"):
+			depth--
+		case t.punct(",") && depth == 0:
+			parts = append(parts, toks[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+	return parts
+}
+
+// render writes the tokens back as SQL, with a space between the words only.
+func render(toks []token) string {
+	var b strings.Builder
+	for i, t := range toks {
+		if i > 0 && t.kind != tokPunct && toks[i-1].kind != tokPunct {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.raw)
+	}
+	return b.String()
+}
+
+// describe returns the beginning of a statement, to locate it in the errors.
+func describe(stmt []token) string {
+	return render(stmt[:min(len(stmt), 3)])
+}
@@ -0,0 +1,1328 @@
+package migrations
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+)
+
+// replayer applies the DDL statements of the migrations to an in-memory schema. The statements that do
+// not change the tables, e.g. INSERT or CREATE FUNCTION, are skipped.
+type replayer struct {
+	s *schema.Schema
+	// enums holds the enum types created by CREATE TYPE, by their lower-cased name. The columns of an
+	// enum type share its EnumType, so that ALTER TYPE ... ADD VALUE changes all of them.
+	enums map[string]*schema.EnumType
+	// pending holds the foreign keys whose referenced table is not created yet.
+	pending []*pendingKey
+}
+
+type pendingKey struct {
+	fk         *schema.ForeignKey
+	refTable   string
+	refColumns []string
+}
+
+// reference is a REFERENCES clause.
+type reference struct {
+	symbol   string
+	table    string
+	columns  []string
+	onDelete schema.ReferenceOption
+	onUpdate schema.ReferenceOption
+}
+
+// columnDef is a column definition with its inline constraints.
+type columnDef struct {
+	c          *schema.Column
+	primary    bool
+	unique     bool
+	uniqueName string
+	ref        *reference
+	first      bool
+	after      string
+}
+
+func newReplayer() *replayer {
+	return &replayer{
+		s:     &schema.Schema{},
+		enums: make(map[string]*schema.EnumType),
+	}
+}
+
+// exec replays the statements of the SQL text.
+func (r *replayer) exec(sql string) error {
+	stmts, err := statements(sql)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err = r.apply(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply replays a single statement.
+func (r *replayer) apply(stmt []token) error {
+	p := &parser{toks: stmt}
+	var err error
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		for p.accept("TEMPORARY") || p.accept("TEMP") || p.accept("UNLOGGED") || p.accept("GLOBAL") || p.accept("LOCAL") {
+		}
+		switch {
+		case p.accept("TABLE"):
+			err = r.createTable(p)
+		case p.accept("INDEX"):
+			err = r.createIndex(p, false)
+		case p.accept("UNIQUE", "INDEX"):
+			err = r.createIndex(p, true)
+		case p.accept("FULLTEXT", "INDEX"), p.accept("SPATIAL", "INDEX"):
+			err = r.createIndex(p, false)
+		case p.accept("TYPE"):
+			err = r.createType(p)
+		}
+	case p.accept("ALTER", "TABLE"):
+		err = r.alterTable(p)
+	case p.accept("ALTER", "TYPE"):
+		err = r.alterType(p)
+	case p.accept("DROP", "TABLE"):
+		err = r.dropTables(p)
+	case p.accept("DROP", "INDEX"):
+		err = r.dropIndexes(p)
+	case p.accept("DROP", "TYPE"):
+		p.accept("IF", "EXISTS")
+		var names []string
+		if names, err = p.names(); err == nil {
+			for _, name := range names {
+				delete(r.enums, strings.ToLower(name))
+			}
+		}
+	case p.accept("RENAME", "TABLE"):
+		err = r.renameTables(p)
+	case p.accept("COMMENT", "ON"):
+		err = r.comment(p)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", describe(stmt), err)
+	}
+	return r.resolve(false)
+}
+
+// table returns the table of the given name. The names are compared case-insensitively, as the
+// migrations may quote them or not.
+func (r *replayer) table(name string) *schema.Table {
+	for _, t := range r.s.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func column(t *schema.Table, name string) *schema.Column {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func columns(t *schema.Table, names []string) ([]*schema.Column, error) {
+	cs := make([]*schema.Column, 0, len(names))
+	for _, name := range names {
+		c := column(t, name)
+		if c == nil {
+			return nil, fmt.Errorf("column %q does not exist in table %q", name, t.Name)
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+func (r *replayer) createTable(p *parser) error {
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if r.table(name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("table %q already exists", name)
+	}
+	t := &schema.Table{Name: name, Schema: r.s}
+	switch {
+	case p.accept("LIKE"):
+		// CREATE TABLE copy LIKE users, in MySQL.
+		if err = r.copyColumns(t, p); err != nil {
+			return err
+		}
+	case p.peek().punct("("):
+		body, err := p.group()
+		if err != nil {
+			return err
+		}
+		// The columns are defined first, so that the table constraints can reference all of them.
+		var constraints [][]token
+		for _, elem := range split(body) {
+			ep := &parser{toks: elem}
+			switch {
+			case isConstraint(ep):
+				constraints = append(constraints, elem)
+			case ep.accept("LIKE"):
+				if err = r.copyColumns(t, ep); err != nil {
+					return err
+				}
+			default:
+				if err = r.addColumn(t, ep, false); err != nil {
+					return err
+				}
+			}
+		}
+		for _, elem := range constraints {
+			if err = r.addConstraint(t, &parser{toks: elem}); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("table %q has no column definitions", name)
+	}
+	if p.accept("AS") {
+		return fmt.Errorf("table %q: CREATE TABLE ... AS is not supported", name)
+	}
+	tableOptions(t, p)
+	r.s.Tables = append(r.s.Tables, t)
+	return nil
+}
+
+// copyColumns copies the columns and the primary key of the table named by the parser.
+func (r *replayer) copyColumns(t *schema.Table, p *parser) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	src := r.table(name)
+	if src == nil {
+		return fmt.Errorf("table %q does not exist", name)
+	}
+	for _, c := range src.Columns {
+		cp := *c
+		cp.Indexes, cp.ForeignKeys = nil, nil
+		t.Columns = append(t.Columns, &cp)
+	}
+	if src.PrimaryKey != nil {
+		var names []string
+		for _, part := range src.PrimaryKey.Parts {
+			names = append(names, part.C.Name)
+		}
+		return setPrimaryKey(t, "", names)
+	}
+	return nil
+}
+
+// isConstraint reports whether the element of a table definition is a constraint or an index rather
+// than a column. A column may be named key or index in PostgreSQL.
+func isConstraint(p *parser) bool {
+	switch t := p.peek(); {
+	case t.is("CONSTRAINT", "PRIMARY", "FOREIGN", "CHECK", "EXCLUDE", "FULLTEXT", "SPATIAL"):
+		return true
+	case t.is("UNIQUE", "KEY", "INDEX"):
+		next := p.peekAt(1)
+		return next.punct("(") || next.is("KEY", "INDEX", "USING") || next.ident() && (p.peekAt(2).punct("(") || p.peekAt(2).is("USING"))
+	}
+	return false
+}
+
+// addConstraint adds a table constraint or an index to the table.
+func (r *replayer) addConstraint(t *schema.Table, p *parser) error {
+	var symbol string
+	if p.accept("CONSTRAINT") && !p.peek().is("PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE") {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		symbol = name
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		skipIndexType(p)
+		names, err := columnList(p)
+		if err != nil {
+			return err
+		}
+		return setPrimaryKey(t, symbol, names)
+	case p.accept("UNIQUE"):
+		_ = p.accept("KEY") || p.accept("INDEX")
+		return addIndex(t, indexName(p, symbol), true, p)
+	case p.accept("FOREIGN", "KEY"):
+		if p.peek().ident() {
+			// FOREIGN KEY fk_name (column), in MySQL.
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			if symbol == "" {
+				symbol = name
+			}
+		}
+		names, err := columnList(p)
+		if err != nil {
+			return err
+		}
+		ref, err := references(p)
+		if err != nil {
+			return err
+		}
+		ref.symbol = symbol
+		return r.addForeignKey(t, names, ref)
+	case p.accept("FULLTEXT"), p.accept("SPATIAL"), p.accept("KEY"), p.accept("INDEX"):
+		_ = p.accept("KEY") || p.accept("INDEX")
+		return addIndex(t, indexName(p, symbol), false, p)
+	}
+	// CHECK and EXCLUDE constraints do not change the columns.
+	return nil
+}
+
+// indexName reads the optional name of an index.
+func indexName(p *parser, symbol string) string {
+	if p.peek().ident() && !p.peek().is("USING") {
+		if name, err := p.name(); err == nil {
+			return name
+		}
+	}
+	return symbol
+}
+
+func skipIndexType(p *parser) {
+	if p.accept("USING") {
+		p.next()
+	}
+}
+
+// columnList reads a parenthesized list of columns, e.g. the columns of a primary key.
+func columnList(p *parser) ([]string, error) {
+	body, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, part := range split(body) {
+		if len(part) == 0 || !part[0].ident() {
+			return nil, fmt.Errorf("expected a column name, got %q", render(part))
+		}
+		names = append(names, part[0].val)
+	}
+	return names, nil
+}
+
+// addIndex adds the index whose parts are read by the parser. Its default name follows PostgreSQL.
+func addIndex(t *schema.Table, name string, unique bool, p *parser) error {
+	skipIndexType(p)
+	body, err := p.group()
+	if err != nil {
+		return err
+	}
+	idx := &schema.Index{Name: name, Unique: unique, Table: t}
+	var names []string
+	for i, part := range split(body) {
+		if len(part) == 0 {
+			return fmt.Errorf("empty index part in %q", render(body))
+		}
+		ip := &schema.IndexPart{SeqNo: i}
+		if part[0].ident() && (len(part) == 1 || !part[1].punct("(")) {
+			c := column(t, part[0].val)
+			if c == nil {
+				return fmt.Errorf("column %q does not exist in table %q", part[0].val, t.Name)
+			}
+			ip.C = c
+			names = append(names, c.Name)
+		} else {
+			ip.X = &schema.RawExpr{X: render(part)}
+			names = append(names, "expr")
+		}
+		ip.Desc = slices.ContainsFunc(part, func(t token) bool { return t.is("DESC") })
+		idx.Parts = append(idx.Parts, ip)
+	}
+	if idx.Name == "" {
+		suffix := "idx"
+		if unique {
+			suffix = "key"
+		}
+		idx.Name = t.Name + "_" + strings.Join(names, "_") + "_" + suffix
+	}
+	if findIndex(t, idx.Name) != nil {
+		return fmt.Errorf("index %q already exists on table %q", idx.Name, t.Name)
+	}
+	t.Indexes = append(t.Indexes, idx)
+	return nil
+}
+
+func setPrimaryKey(t *schema.Table, name string, names []string) error {
+	cs, err := columns(t, names)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = t.Name + "_pkey"
+	}
+	pk := &schema.Index{Name: name, Unique: true, Table: t}
+	for i, c := range cs {
+		c.Type.Null = false
+		pk.Parts = append(pk.Parts, &schema.IndexPart{SeqNo: i, C: c})
+	}
+	t.PrimaryKey = pk
+	return nil
+}
+
+// references reads a REFERENCES clause.
+func references(p *parser) (*reference, error) {
+	if err := p.expect("REFERENCES"); err != nil {
+		return nil, err
+	}
+	table, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	ref := &reference{table: table}
+	if p.peek().punct("(") {
+		if ref.columns, err = columnList(p); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		switch {
+		case p.accept("ON", "DELETE"):
+			ref.onDelete = referenceOption(p)
+		case p.accept("ON", "UPDATE"):
+			ref.onUpdate = referenceOption(p)
+		case p.accept("MATCH"), p.accept("INITIALLY"):
+			p.next()
+		case p.accept("DEFERRABLE"), p.accept("NOT", "DEFERRABLE"):
+		default:
+			return ref, nil
+		}
+	}
+}
+
+func referenceOption(p *parser) schema.ReferenceOption {
+	switch {
+	case p.accept("CASCADE"):
+		return schema.Cascade
+	case p.accept("RESTRICT"):
+		return schema.Restrict
+	case p.accept("SET", "NULL"):
+		return schema.SetNull
+	case p.accept("SET", "DEFAULT"):
+		return schema.SetDefault
+	case p.accept("NO", "ACTION"):
+		return schema.NoAction
+	}
+	return ""
+}
+
+// addForeignKey adds a foreign key to the table. It references its table once that table exists.
+func (r *replayer) addForeignKey(t *schema.Table, names []string, ref *reference) error {
+	cs, err := columns(t, names)
+	if err != nil {
+		return err
+	}
+	symbol := ref.symbol
+	if symbol == "" {
+		symbol = t.Name + "_" + strings.Join(names, "_") + "_fkey"
+	}
+	fk := &schema.ForeignKey{
+		Symbol:   symbol,
+		Table:    t,
+		Columns:  cs,
+		OnDelete: ref.onDelete,
+		OnUpdate: ref.onUpdate,
+	}
+	t.ForeignKeys = append(t.ForeignKeys, fk)
+	r.pending = append(r.pending, &pendingKey{fk: fk, refTable: ref.table, refColumns: ref.columns})
+	return nil
+}
+
+// resolve links the pending foreign keys to the tables they reference. When final is set, the
+// foreign keys that reference missing tables or columns are an error.
+func (r *replayer) resolve(final bool) error {
+	var pending []*pendingKey
+	for _, k := range r.pending {
+		if !slices.Contains(k.fk.Table.ForeignKeys, k.fk) || !slices.Contains(r.s.Tables, k.fk.Table) {
+			// The foreign key or its table was dropped.
+			continue
+		}
+		ref := r.table(k.refTable)
+		if ref == nil {
+			if final {
+				return fmt.Errorf("foreign key %q of table %q references the missing table %q", k.fk.Symbol, k.fk.Table.Name, k.refTable)
+			}
+			pending = append(pending, k)
+			continue
+		}
+		names := k.refColumns
+		if len(names) == 0 && ref.PrimaryKey != nil {
+			for _, part := range ref.PrimaryKey.Parts {
+				names = append(names, part.C.Name)
+			}
+		}
+		cs, err := columns(ref, names)
+		if err == nil && len(cs) != len(k.fk.Columns) {
+			err = fmt.Errorf("foreign key %q of table %q references %d columns of table %q, expected %d", k.fk.Symbol, k.fk.Table.Name, len(cs), ref.Name, len(k.fk.Columns))
+		}
+		if err != nil {
+			if final {
+				return err
+			}
+			pending = append(pending, k)
+			continue
+		}
+		k.fk.RefTable, k.fk.RefColumns = ref, cs
+	}
+	r.pending = pending
+	return nil
+}
+
+// addColumn adds the column defined by the parser to the table.
+func (r *replayer) addColumn(t *schema.Table, p *parser, ifNotExists bool) error {
+	d, err := r.columnDef(p)
+	if err != nil {
+		return err
+	}
+	if column(t, d.c.Name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("column %q already exists in table %q", d.c.Name, t.Name)
+	}
+	t.Columns = append(t.Columns, d.c)
+	if err = moveColumn(t, d); err != nil {
+		return err
+	}
+	return r.columnConstraints(t, d)
+}
+
+// modifyColumn redefines a column, as MODIFY and CHANGE do in MySQL. The column keeps its indexes and
+// foreign keys.
+func (r *replayer) modifyColumn(t *schema.Table, name string, p *parser) error {
+	c := column(t, name)
+	if c == nil {
+		return fmt.Errorf("column %q does not exist in table %q", name, t.Name)
+	}
+	d, err := r.columnDef(p)
+	if err != nil {
+		return err
+	}
+	if other := column(t, d.c.Name); other != nil && other != c {
+		return fmt.Errorf("column %q already exists in table %q", d.c.Name, t.Name)
+	}
+	c.Name, c.Type, c.Default, c.Attrs = d.c.Name, d.c.Type, d.c.Default, d.c.Attrs
+	if t.PrimaryKey != nil && slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == c }) {
+		c.Type.Null = false
+	}
+	d.c = c
+	if err = moveColumn(t, d); err != nil {
+		return err
+	}
+	return r.columnConstraints(t, d)
+}
+
+// moveColumn moves the column to the position given by FIRST or AFTER, in MySQL.
+func moveColumn(t *schema.Table, d *columnDef) error {
+	if !d.first && d.after == "" {
+		return nil
+	}
+	i := slices.Index(t.Columns, d.c)
+	t.Columns = slices.Delete(t.Columns, i, i+1)
+	at := 0
+	if d.after != "" {
+		after := column(t, d.after)
+		if after == nil {
+			return fmt.Errorf("column %q does not exist in table %q", d.after, t.Name)
+		}
+		at = slices.Index(t.Columns, after) + 1
+	}
+	t.Columns = slices.Insert(t.Columns, at, d.c)
+	return nil
+}
+
+// columnConstraints adds the inline constraints of a column definition to the table.
+func (r *replayer) columnConstraints(t *schema.Table, d *columnDef) error {
+	if d.primary {
+		if err := setPrimaryKey(t, "", []string{d.c.Name}); err != nil {
+			return err
+		}
+	}
+	if d.unique {
+		name := d.uniqueName
+		if name == "" {
+			name = t.Name + "_" + d.c.Name + "_key"
+		}
+		if findIndex(t, name) == nil {
+			t.Indexes = append(t.Indexes, &schema.Index{
+				Name:   name,
+				Unique: true,
+				Table:  t,
+				Parts:  []*schema.IndexPart{{C: d.c}},
+			})
+		}
+	}
+	if d.ref != nil {
+		return r.addForeignKey(t, []string{d.c.Name}, d.ref)
+	}
+	return nil
+}
+
+// columnDef reads a column definition: its name, its type and its inline constraints.
+func (r *replayer) columnDef(p *parser) (*columnDef, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	raw := typeRaw(p)
+	c := &schema.Column{
+		Name: name,
+		Type: &schema.ColumnType{Raw: raw, Type: r.columnType(raw), Null: true},
+	}
+	if _, ok := c.Type.Type.(*postgres.SerialType); ok {
+		c.Type.Null = false
+	}
+	d := &columnDef{c: c}
+	for !p.eof() {
+		var symbol string
+		if p.accept("CONSTRAINT") {
+			if symbol, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case p.accept("NOT", "NULL"):
+			c.Type.Null = false
+		case p.accept("NULL"):
+			c.Type.Null = true
+		case p.accept("PRIMARY", "KEY"):
+			d.primary = true
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			d.unique, d.uniqueName = true, symbol
+		case p.accept("DEFAULT"):
+			setDefault(c, p.until(isColumnKeyword))
+		case p.accept("AUTO_INCREMENT"):
+			c.AddAttrs(&mysql.AutoIncrement{})
+		case p.accept("AUTOINCREMENT"):
+			c.AddAttrs(&sqlite.AutoIncrement{})
+		case p.accept("COMMENT"):
+			c.SetComment(p.next().val)
+		case p.peek().is("REFERENCES"):
+			if d.ref, err = references(p); err != nil {
+				return nil, err
+			}
+			d.ref.symbol = symbol
+		case p.accept("CHECK"):
+			if _, err = p.group(); err != nil {
+				return nil, err
+			}
+		case p.accept("GENERATED"):
+			generation := "BY DEFAULT"
+			if p.accept("ALWAYS") {
+				generation = "ALWAYS"
+			} else {
+				p.accept("BY", "DEFAULT")
+			}
+			p.accept("ON", "NULL")
+			if err = p.expect("AS"); err != nil {
+				return nil, err
+			}
+			if p.accept("IDENTITY") {
+				c.AddAttrs(&postgres.Identity{Generation: generation})
+				c.Type.Null = false
+				if p.peek().punct("(") {
+					_, _ = p.group()
+				}
+				continue
+			}
+			fallthrough
+		case p.accept("AS"):
+			body, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			x := &schema.GeneratedExpr{Expr: render(body), Type: "VIRTUAL"}
+			if p.accept("STORED") {
+				x.Type = "STORED"
+			}
+			c.SetGeneratedExpr(x)
+		case p.accept("COLLATE"):
+			c.SetCollation(p.next().val)
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+			c.SetCharset(p.next().val)
+		case p.accept("ON", "UPDATE"):
+			c.AddAttrs(&mysql.OnUpdate{A: render(p.until(isColumnKeyword))})
+		case p.accept("FIRST"):
+			d.first = true
+		case p.accept("AFTER"):
+			if d.after, err = p.name(); err != nil {
+				return nil, err
+			}
+		default:
+			// VISIBLE, INVISIBLE, ASC, DESC and the options that do not change the schema.
+			p.next()
+		}
+	}
+	if d.primary {
+		c.Type.Null = false
+	}
+	return d, nil
+}
+
+// isColumnKeyword reports whether the parser is at a keyword that starts a column constraint, and ends
+// a column type or a default value.
+func isColumnKeyword(p *parser) bool {
+	t := p.peek()
+	if t.is("CHARACTER") {
+		return p.peekAt(1).is("SET")
+	}
+	return t.is("NOT", "NULL", "PRIMARY", "UNIQUE", "DEFAULT", "AUTO_INCREMENT", "AUTOINCREMENT", "COMMENT",
+		"REFERENCES", "CHECK", "CONSTRAINT", "GENERATED", "AS", "COLLATE", "CHARSET", "ON", "FIRST", "AFTER",
+		"VISIBLE", "INVISIBLE", "USING", "STORED", "VIRTUAL")
+}
+
+// typeRaw reads a column type, e.g. varchar(255), int unsigned or timestamp with time zone.
+func typeRaw(p *parser) string {
+	if p.eof() || isColumnKeyword(p) {
+		return ""
+	}
+	var b strings.Builder
+	for _, t := range p.until(isColumnKeyword) {
+		switch {
+		case t.kind == tokWord:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), ".") {
+				b.WriteByte(' ')
+			}
+			b.WriteString(strings.ToLower(t.raw))
+		case t.kind == tokQuoted:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), ".") {
+				b.WriteByte(' ')
+			}
+			b.WriteString(t.val)
+		default:
+			b.WriteString(t.raw)
+		}
+	}
+	return b.String()
+}
+
+// setDefault sets the default value of the column. Literals are kept quoted, as Atlas inspects them.
+func setDefault(c *schema.Column, expr []token) {
+	x := render(expr)
+	switch {
+	case len(expr) == 1 && expr[0].is("NULL"):
+		c.Default = nil
+	case len(expr) == 1 && (expr[0].kind == tokString || expr[0].kind == tokNumber || expr[0].is("TRUE", "FALSE")),
+		len(expr) == 2 && (expr[0].punct("-") || expr[0].punct("+")) && expr[1].kind == tokNumber:
+		c.Default = &schema.Literal{V: x}
+	case len(expr) == 3 && expr[0].punct("(") && expr[2].punct(")") && expr[1].kind != tokWord:
+		c.Default = &schema.Literal{V: expr[1].raw}
+	default:
+		c.Default = &schema.RawExpr{X: x}
+	}
+}
+
+// columnType returns the type of a column. Enum types created by CREATE TYPE are resolved to their
+// values, the other types are parsed by ParseType.
+func (r *replayer) columnType(raw string) schema.Type {
+	if e, ok := r.enums[strings.ToLower(raw)]; ok {
+		return e
+	}
+	return ParseType(raw)
+}
+
+// ParseType parses a column type of MySQL, PostgreSQL or SQLite. The first dialect that knows the type
+// wins, so that int unsigned is parsed by MySQL and timestamptz by PostgreSQL.
+func ParseType(raw string) schema.Type {
+	for _, parse := range []func(string) (schema.Type, error){mysql.ParseType, postgres.ParseType, sqlite.ParseType} {
+		typ, err := parse(raw)
+		if err != nil {
+			continue
+		}
+		switch typ.(type) {
+		case *schema.UnsupportedType, *postgres.UserDefinedType, *sqlite.UserDefinedType:
+			continue
+		}
+		return typ
+	}
+	return &schema.UnsupportedType{T: raw}
+}
+
+// tableOptions reads the options that follow a table definition, e.g. ENGINE=InnoDB in MySQL.
+func tableOptions(t *schema.Table, p *parser) {
+	for !p.eof() {
+		switch {
+		case p.accept("DEFAULT"):
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+			p.acceptPunct("=")
+			t.SetCharset(p.next().val)
+		case p.accept("COLLATE"):
+			p.acceptPunct("=")
+			t.SetCollation(p.next().val)
+		case p.accept("COMMENT"):
+			p.acceptPunct("=")
+			t.SetComment(p.next().val)
+		case p.peek().punct("("):
+			_, _ = p.group()
+		default:
+			p.next()
+		}
+	}
+}
+
+func (r *replayer) alterTable(p *parser) error {
+	ifExists := p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	p.acceptPunct("*")
+	t := r.table(name)
+	if t == nil {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("table %q does not exist", name)
+	}
+	for _, action := range split(p.rest()) {
+		if err = r.alterAction(t, &parser{toks: action}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterAction applies an action of ALTER TABLE. The actions that do not change the columns, the
+// indexes or the foreign keys, e.g. OWNER TO or ENGINE=InnoDB, are skipped.
+func (r *replayer) alterAction(t *schema.Table, p *parser) error {
+	switch {
+	case p.accept("ADD"):
+		switch {
+		case p.peek().punct("("):
+			// ADD (a int, b int), in MySQL.
+			body, err := p.group()
+			if err != nil {
+				return err
+			}
+			for _, elem := range split(body) {
+				if err = r.addColumn(t, &parser{toks: elem}, false); err != nil {
+					return err
+				}
+			}
+			return nil
+		case p.accept("COLUMN"):
+		case isConstraint(p):
+			return r.addConstraint(t, p)
+		}
+		return r.addColumn(t, p, p.accept("IF", "NOT", "EXISTS"))
+	case p.accept("DROP"):
+		return r.dropAction(t, p)
+	case p.accept("MODIFY"):
+		p.accept("COLUMN")
+		return r.modifyColumn(t, p.peek().val, p)
+	case p.accept("CHANGE"):
+		p.accept("COLUMN")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		return r.modifyColumn(t, name, p)
+	case p.accept("ALTER"):
+		if p.accept("CONSTRAINT") || p.accept("INDEX") || p.accept("CHECK") {
+			return nil
+		}
+		p.accept("COLUMN")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		c := column(t, name)
+		if c == nil {
+			return fmt.Errorf("column %q does not exist in table %q", name, t.Name)
+		}
+		return r.alterColumn(c, p)
+	case p.accept("RENAME"):
+		return r.renameAction(t, p)
+	case p.peek().is("COMMENT", "DEFAULT", "CHARACTER", "CHARSET", "COLLATE"):
+		tableOptions(t, p)
+	}
+	return nil
+}
+
+func (r *replayer) dropAction(t *schema.Table, p *parser) error {
+	switch {
+	case p.accept("CONSTRAINT"):
+		p.accept("IF", "EXISTS")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		dropConstraint(t, name)
+		return nil
+	case p.accept("FOREIGN", "KEY"):
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		if !dropForeignKey(t, name) {
+			return fmt.Errorf("foreign key %q does not exist on table %q", name, t.Name)
+		}
+		return nil
+	case p.accept("PRIMARY", "KEY"):
+		t.PrimaryKey = nil
+		return nil
+	case p.accept("INDEX"), p.accept("KEY"):
+		ifExists := p.accept("IF", "EXISTS")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		if idx := findIndex(t, name); idx != nil {
+			t.Indexes = slices.DeleteFunc(t.Indexes, func(i *schema.Index) bool { return i == idx })
+		} else if !ifExists {
+			return fmt.Errorf("index %q does not exist on table %q", name, t.Name)
+		}
+		return nil
+	case p.accept("CHECK"):
+		return nil
+	}
+	p.accept("COLUMN")
+	ifExists := p.accept("IF", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	c := column(t, name)
+	if c == nil {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("column %q does not exist in table %q", name, t.Name)
+	}
+	r.dropColumn(t, c)
+	return nil
+}
+
+// dropColumn removes the column from the table, and from the indexes and the foreign keys that use it.
+func (r *replayer) dropColumn(t *schema.Table, c *schema.Column) {
+	t.Columns = slices.DeleteFunc(t.Columns, func(x *schema.Column) bool { return x == c })
+	dropParts := func(idx *schema.Index) bool {
+		idx.Parts = slices.DeleteFunc(idx.Parts, func(p *schema.IndexPart) bool { return p.C == c })
+		return len(idx.Parts) == 0
+	}
+	if t.PrimaryKey != nil && dropParts(t.PrimaryKey) {
+		t.PrimaryKey = nil
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, dropParts)
+	t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *schema.ForeignKey) bool {
+		return slices.Contains(fk.Columns, c)
+	})
+	for _, other := range r.s.Tables {
+		other.ForeignKeys = slices.DeleteFunc(other.ForeignKeys, func(fk *schema.ForeignKey) bool {
+			return slices.Contains(fk.RefColumns, c)
+		})
+	}
+}
+
+// dropConstraint drops the primary key, the unique index or the foreign key of the given name.
+// Check constraints are not tracked, so a missing constraint is not an error.
+func dropConstraint(t *schema.Table, name string) {
+	if t.PrimaryKey != nil && strings.EqualFold(t.PrimaryKey.Name, name) {
+		t.PrimaryKey = nil
+		return
+	}
+	if dropForeignKey(t, name) {
+		return
+	}
+	if idx := findIndex(t, name); idx != nil {
+		t.Indexes = slices.DeleteFunc(t.Indexes, func(i *schema.Index) bool { return i == idx })
+	}
+}
+
+func dropForeignKey(t *schema.Table, symbol string) bool {
+	n := len(t.ForeignKeys)
+	t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *schema.ForeignKey) bool {
+		return strings.EqualFold(fk.Symbol, symbol)
+	})
+	return len(t.ForeignKeys) < n
+}
+
+// findIndex returns the index of the given name. As MySQL names the indexes after their first column
+// by default, an index on a single column of that name is found too.
+func findIndex(t *schema.Table, name string) *schema.Index {
+	for _, idx := range t.Indexes {
+		if strings.EqualFold(idx.Name, name) {
+			return idx
+		}
+	}
+	for _, idx := range t.Indexes {
+		if len(idx.Parts) == 1 && idx.Parts[0].C != nil && strings.EqualFold(idx.Parts[0].C.Name, name) {
+			return idx
+		}
+	}
+	return nil
+}
+
+// alterColumn applies ALTER COLUMN, in PostgreSQL and MySQL.
+func (r *replayer) alterColumn(c *schema.Column, p *parser) error {
+	switch {
+	case p.accept("TYPE"), p.accept("SET", "DATA", "TYPE"):
+		raw := typeRaw(p)
+		c.Type.Raw, c.Type.Type = raw, r.columnType(raw)
+	case p.accept("SET", "NOT", "NULL"):
+		c.Type.Null = false
+	case p.accept("DROP", "NOT", "NULL"):
+		c.Type.Null = true
+	case p.accept("SET", "DEFAULT"):
+		setDefault(c, p.rest())
+	case p.accept("DROP", "DEFAULT"):
+		c.Default = nil
+	case p.accept("ADD", "GENERATED"):
+		generation := "BY DEFAULT"
+		if p.accept("ALWAYS") {
+			generation = "ALWAYS"
+		}
+		c.AddAttrs(&postgres.Identity{Generation: generation})
+		c.Type.Null = false
+	case p.accept("DROP", "IDENTITY"):
+		c.Attrs = schema.RemoveAttr[*postgres.Identity](c.Attrs)
+	}
+	return nil
+}
+
+func (r *replayer) renameAction(t *schema.Table, p *parser) error {
+	switch {
+	case p.accept("TO"), p.accept("AS"):
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		return r.renameTable(t, name)
+	case p.accept("INDEX"), p.accept("KEY"), p.accept("CONSTRAINT"):
+		from, to, err := renamePair(p)
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.PrimaryKey != nil && strings.EqualFold(t.PrimaryKey.Name, from):
+			t.PrimaryKey.Name = to
+		case findIndex(t, from) != nil:
+			findIndex(t, from).Name = to
+		default:
+			for _, fk := range t.ForeignKeys {
+				if strings.EqualFold(fk.Symbol, from) {
+					fk.Symbol = to
+				}
+			}
+		}
+		return nil
+	}
+	p.accept("COLUMN")
+	from, to, err := renamePair(p)
+	if err != nil {
+		return err
+	}
+	c := column(t, from)
+	if c == nil {
+		return fmt.Errorf("column %q does not exist in table %q", from, t.Name)
+	}
+	if column(t, to) != nil {
+		return fmt.Errorf("column %q already exists in table %q", to, t.Name)
+	}
+	c.Name = to
+	return nil
+}
+
+// renamePair reads "a TO b".
+func renamePair(p *parser) (string, string, error) {
+	from, err := p.name()
+	if err != nil {
+		return "", "", err
+	}
+	if err = p.expect("TO"); err != nil {
+		return "", "", err
+	}
+	to, err := p.name()
+	if err != nil {
+		return "", "", err
+	}
+	return from, to, nil
+}
+
+func (r *replayer) renameTable(t *schema.Table, name string) error {
+	if other := r.table(name); other != nil && other != t {
+		return fmt.Errorf("table %q already exists", name)
+	}
+	t.Name = name
+	return nil
+}
+
+// renameTables applies RENAME TABLE a TO b, c TO d, in MySQL.
+func (r *replayer) renameTables(p *parser) error {
+	for {
+		from, to, err := renamePair(p)
+		if err != nil {
+			return err
+		}
+		t := r.table(from)
+		if t == nil {
+			return fmt.Errorf("table %q does not exist", from)
+		}
+		if err = r.renameTable(t, to); err != nil {
+			return err
+		}
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+// dropTables drops the tables and the foreign keys that reference them, as CASCADE does.
+func (r *replayer) dropTables(p *parser) error {
+	ifExists := p.accept("IF", "EXISTS")
+	names, err := p.names()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		t := r.table(name)
+		if t == nil {
+			if ifExists {
+				continue
+			}
+			return fmt.Errorf("table %q does not exist", name)
+		}
+		r.s.Tables = slices.DeleteFunc(r.s.Tables, func(x *schema.Table) bool { return x == t })
+		for _, other := range r.s.Tables {
+			other.ForeignKeys = slices.DeleteFunc(other.ForeignKeys, func(fk *schema.ForeignKey) bool {
+				return fk.RefTable == t
+			})
+		}
+	}
+	return nil
+}
+
+// createIndex applies CREATE INDEX.
+func (r *replayer) createIndex(p *parser, unique bool) error {
+	p.accept("CONCURRENTLY")
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	var name string
+	if !p.peek().is("ON") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+	skipIndexType(p)
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	p.accept("ONLY")
+	table, err := p.name()
+	if err != nil {
+		return err
+	}
+	t := r.table(table)
+	if t == nil {
+		return fmt.Errorf("table %q does not exist", table)
+	}
+	if name != "" && ifNotExists && findIndex(t, name) != nil {
+		return nil
+	}
+	return addIndex(t, name, unique, p)
+}
+
+// dropIndexes applies DROP INDEX, with the table given by ON in MySQL.
+func (r *replayer) dropIndexes(p *parser) error {
+	p.accept("CONCURRENTLY")
+	ifExists := p.accept("IF", "EXISTS")
+	names, err := p.names()
+	if err != nil {
+		return err
+	}
+	tables := r.s.Tables
+	if p.accept("ON") {
+		table, err := p.name()
+		if err != nil {
+			return err
+		}
+		t := r.table(table)
+		if t == nil {
+			return fmt.Errorf("table %q does not exist", table)
+		}
+		tables = []*schema.Table{t}
+	}
+	for _, name := range names {
+		dropped := false
+		for _, t := range tables {
+			if idx := findIndex(t, name); idx != nil {
+				t.Indexes = slices.DeleteFunc(t.Indexes, func(i *schema.Index) bool { return i == idx })
+				dropped = true
+				break
+			}
+		}
+		if !dropped && !ifExists {
+			return fmt.Errorf("index %q does not exist", name)
+		}
+	}
+	return nil
+}
+
+// createType applies CREATE TYPE ... AS ENUM, in PostgreSQL. The other types are skipped.
+func (r *replayer) createType(p *parser) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if !p.accept("AS", "ENUM") {
+		return nil
+	}
+	body, err := p.group()
+	if err != nil {
+		return err
+	}
+	e := &schema.EnumType{T: name}
+	for _, part := range split(body) {
+		if len(part) > 0 {
+			e.Values = append(e.Values, part[0].val)
+		}
+	}
+	r.enums[strings.ToLower(name)] = e
+	return nil
+}
+
+// alterType applies ALTER TYPE ... ADD VALUE and RENAME VALUE to an enum type.
+func (r *replayer) alterType(p *parser) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	e, ok := r.enums[strings.ToLower(name)]
+	if !ok {
+		return nil
+	}
+	switch {
+	case p.accept("ADD", "VALUE"):
+		p.accept("IF", "NOT", "EXISTS")
+		v := p.next().val
+		if slices.Contains(e.Values, v) {
+			return nil
+		}
+		i := len(e.Values)
+		switch {
+		case p.accept("BEFORE"):
+			if j := slices.Index(e.Values, p.next().val); j >= 0 {
+				i = j
+			}
+		case p.accept("AFTER"):
+			if j := slices.Index(e.Values, p.next().val); j >= 0 {
+				i = j + 1
+			}
+		}
+		e.Values = slices.Insert(e.Values, i, v)
+	case p.accept("RENAME", "VALUE"):
+		from := p.next().val
+		if err = p.expect("TO"); err != nil {
+			return err
+		}
+		if i := slices.Index(e.Values, from); i >= 0 {
+			e.Values[i] = p.next().val
+		}
+	case p.accept("RENAME", "TO"):
+		to, err := p.name()
+		if err != nil {
+			return err
+		}
+		delete(r.enums, strings.ToLower(name))
+		e.T = to
+		r.enums[strings.ToLower(to)] = e
+	}
+	return nil
+}
+
+// comment applies COMMENT ON TABLE and COMMENT ON COLUMN, in PostgreSQL.
+func (r *replayer) comment(p *parser) error {
+	var (
+		onColumn = p.accept("COLUMN")
+		onTable  = !onColumn && p.accept("TABLE")
+	)
+	if !onColumn && !onTable {
+		return nil
+	}
+	parts, err := p.qualified()
+	if err != nil {
+		return err
+	}
+	if err = p.expect("IS"); err != nil {
+		return err
+	}
+	text := p.next()
+	table := parts[len(parts)-1]
+	if onColumn {
+		if len(parts) < 2 {
+			return fmt.Errorf("expected a column qualified by its table, got %q", table)
+		}
+		table = parts[len(parts)-2]
+	}
+	t := r.table(table)
+	if t == nil {
+		return fmt.Errorf("table %q does not exist", table)
+	}
+	attrs := &t.Attrs
+	if onColumn {
+		c := column(t, parts[len(parts)-1])
+		if c == nil {
+			return fmt.Errorf("column %q does not exist in table %q", parts[len(parts)-1], t.Name)
+		}
+		attrs = &c.Attrs
+	}
+	if text.is("NULL") {
+		*attrs = schema.RemoveAttr[*schema.Comment](*attrs)
+		return nil
+	}
+	schema.ReplaceOrAppend(attrs, &schema.Comment{Text: text.val})
+	return nil
+}
+
+// link resolves the foreign keys and sets the indexes and the foreign keys of the columns, as Atlas
+// does when it inspects a database.
+func (r *replayer) link() (*schema.Schema, error) {
+	if err := r.resolve(true); err != nil {
+		return nil, err
+	}
+	for _, t := range r.s.Tables {
+		for _, c := range t.Columns {
+			c.Indexes, c.ForeignKeys = nil, nil
+		}
+		for _, idx := range t.Indexes {
+			for _, part := range idx.Parts {
+				if part.C != nil {
+					part.C.Indexes = append(part.C.Indexes, idx)
+				}
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			for _, c := range fk.Columns {
+				c.ForeignKeys = append(c.ForeignKeys, fk)
+			}
+		}
+	}
+	return r.s, nil
+}
@@ -0,0 +1,154 @@
+// Package naming derives the names of the generated code from the table names, so that the ent
+// schemas, the protobuf messages and the repositories generated for a table agree on its name.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"github.com/tx7do/go-utils/stringcase"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the naming rules. The zero value applies the English inflection to the table names.
+type Config struct {
+	// TablePrefixes are stripped from the table names, e.g. "sys_". The first matching prefix is removed.
+	TablePrefixes []string `yaml:"table_prefixes" json:"table_prefixes"`
+	// Irregulars maps singular words to their plural, e.g. "data: data" or "person: people".
+	Irregulars map[string]string `yaml:"irregulars" json:"irregulars"`
+	// NoInflection keeps the table names as they are instead of singularizing them,
+	// e.g. for the tables named in pinyin.
+	NoInflection bool `yaml:"no_inflection" json:"no_inflection"`
+	// TypeNames maps table names to explicit type names, e.g. "sys_users: Admin". The keys are the table
+	// names as they are in the database, before the prefixes are stripped and the names singularized,
+	// and may be qualified with their schema, e.g. "billing.users: Customer".
+	TypeNames map[string]string `yaml:"type_names" json:"type_names"`
+}
+
+// Strategy names the code generated for the tables.
+type Strategy struct {
+	config    Config
+	singulars map[string]string
+	plurals   map[string]string
+}
+
+// Default is the strategy applying the English inflection only.
+var Default = New(Config{})
+
+// New returns the Strategy of the given rules.
+func New(config Config) *Strategy {
+	s := &Strategy{
+		config:    config,
+		singulars: make(map[string]string),
+		plurals:   make(map[string]string),
+	}
+	for singular, plural := range config.Irregulars {
+		singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+		s.singulars[plural] = singular
+		s.plurals[singular] = plural
+	}
+	return s
+}
+
+// Load reads the naming rules from a YAML file. An empty path returns the Default strategy.
+func Load(path string) (*Strategy, error) {
+	if path == "" {
+		return Default, nil
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("naming: read %s: %w", path, err)
+	}
+	var config Config
+	if err = yaml.Unmarshal(buf, &config); err != nil {
+		return nil, fmt.Errorf("naming: parse %s: %w", path, err)
+	}
+	for table, name := range config.TypeNames {
+		if table == "" || name == "" {
+			return nil, errors.New("naming: type names must map a table to a name")
+		}
+	}
+	return New(config), nil
+}
+
+// Name returns the snake_case name of the type generated for a table, e.g. "user" for "sys_users".
+// A table qualified with its schema, e.g. "auth.users", is named after the table, unless the
+// qualified name has an explicit type name.
+func (s *Strategy) Name(table string) string {
+	if s == nil {
+		s = Default
+	}
+	if name, ok := s.config.TypeNames[table]; ok {
+		return stringcase.SnakeCase(name)
+	}
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		return s.Name(table[i+1:])
+	}
+	return s.Singular(s.trimPrefix(table))
+}
+
+// TypeName returns the PascalCase name of the type generated for a table, e.g. "User" for "sys_users".
+func (s *Strategy) TypeName(table string) string {
+	return stringcase.ToPascalCase(s.Name(table))
+}
+
+// Singular returns the singular of a snake_case name, e.g. "user" for "users".
+func (s *Strategy) Singular(name string) string {
+	if s == nil {
+		s = Default
+	}
+	if s.config.NoInflection {
+		return name
+	}
+	return s.inflect(name, s.singulars, inflection.Singular)
+}
+
+// Plural returns the plural of a snake_case name, e.g. "users" for "user".
+func (s *Strategy) Plural(name string) string {
+	if s == nil {
+		s = Default
+	}
+	if s.config.NoInflection {
+		return name
+	}
+	return s.inflect(name, s.plurals, inflection.Plural)
+}
+
+// PluralTypeName returns the plural of a type name, e.g. "OrderItems" for "OrderItem".
+func (s *Strategy) PluralTypeName(typeName string) string {
+	return stringcase.ToPascalCase(s.Plural(stringcase.SnakeCase(typeName)))
+}
+
+// trimPrefix strips the first matching table prefix, unless nothing would be left of the name.
+func (s *Strategy) trimPrefix(table string) string {
+	for _, prefix := range s.config.TablePrefixes {
+		if name := strings.TrimPrefix(table, prefix); name != table && name != "" {
+			return name
+		}
+	}
+	return table
+}
+
+// inflect applies the irregular words to the last word of a snake_case name and falls back to the
+// English inflection rules.
+func (s *Strategy) inflect(name string, irregulars map[string]string, fallback func(string) string) string {
+	head, last := "", name
+	if i := strings.LastIndex(name, "_"); i >= 0 {
+		head, last = name[:i+1], name[i+1:]
+	}
+	word := strings.ToLower(last)
+	if inflected, ok := irregulars[word]; ok {
+		return head + inflected
+	}
+	// A word that is already inflected, e.g. "person" when singularizing, is kept as is.
+	for _, inflected := range irregulars {
+		if inflected == word {
+			return name
+		}
+	}
+	return fallback(name)
+}
@@ -0,0 +1,150 @@
+package naming
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tx7do/go-utils/stringcase"
+)
+
+func TestStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		table    string
+		typeName string
+		snake    string
+		plural   string
+	}{
+		{
+			name:     "default",
+			table:    "users",
+			typeName: "User",
+			snake:    "user",
+			plural:   "users",
+		},
+		{
+			name:     "default keeps the prefix",
+			table:    "sys_users",
+			typeName: "SysUser",
+			snake:    "sys_user",
+			plural:   "sys_users",
+		},
+		{
+			name:     "table prefix",
+			config:   Config{TablePrefixes: []string{"t_", "sys_"}},
+			table:    "sys_users",
+			typeName: "User",
+			snake:    "user",
+			plural:   "users",
+		},
+		{
+			name:     "irregular word",
+			config:   Config{Irregulars: map[string]string{"data": "data"}},
+			table:    "user_data",
+			typeName: "UserData",
+			snake:    "user_data",
+			plural:   "user_data",
+		},
+		{
+			name:     "irregular plural",
+			config:   Config{Irregulars: map[string]string{"staff": "staves"}},
+			table:    "staves",
+			typeName: "Staff",
+			snake:    "staff",
+			plural:   "staves",
+		},
+		{
+			name:     "no inflection",
+			config:   Config{NoInflection: true, TablePrefixes: []string{"t_"}},
+			table:    "t_yonghu_xinxi",
+			typeName: "YonghuXinxi",
+			snake:    "yonghu_xinxi",
+			plural:   "yonghu_xinxi",
+		},
+		{
+			name:     "explicit type name",
+			config:   Config{TypeNames: map[string]string{"sys_user": "Admin"}},
+			table:    "sys_user",
+			typeName: "Admin",
+			snake:    "admin",
+			plural:   "admins",
+		},
+		{
+			name:     "explicit type name of a prefixed table",
+			config:   Config{TablePrefixes: []string{"sys_"}, TypeNames: map[string]string{"sys_users": "Admin", "user": "Member"}},
+			table:    "sys_users",
+			typeName: "Admin",
+			snake:    "admin",
+			plural:   "admins",
+		},
+		{
+			name:     "schema qualified table",
+			config:   Config{TablePrefixes: []string{"t_"}},
+			table:    "auth.t_users",
+			typeName: "User",
+			snake:    "user",
+			plural:   "users",
+		},
+		{
+			name:     "explicit type name of a schema qualified table",
+			config:   Config{TypeNames: map[string]string{"billing.users": "Customer", "users": "Member"}},
+			table:    "billing.users",
+			typeName: "Customer",
+			snake:    "customer",
+			plural:   "customers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.config)
+			if got := s.Name(tt.table); got != tt.snake {
+				t.Errorf("Name(%q) = %q, want %q", tt.table, got, tt.snake)
+			}
+			if got := s.TypeName(tt.table); got != tt.typeName {
+				t.Errorf("TypeName(%q) = %q, want %q", tt.table, got, tt.typeName)
+			}
+			if got := s.Plural(tt.snake); got != tt.plural {
+				t.Errorf("Plural(%q) = %q, want %q", tt.snake, got, tt.plural)
+			}
+			if got, want := s.PluralTypeName(s.TypeName(tt.table)), stringcase.ToPascalCase(tt.plural); got != want {
+				t.Errorf("PluralTypeName(%q) = %q, want %q", s.TypeName(tt.table), got, want)
+			}
+			if got := s.Singular(tt.plural); got != tt.snake && tt.config.TypeNames == nil {
+				t.Errorf("Singular(%q) = %q, want %q", tt.plural, got, tt.snake)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	s, err := Load("")
+	if err != nil || s != Default {
+		t.Fatalf("Load(\"\") = %v, %v, want the default strategy", s, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "naming.yaml")
+	content := `table_prefixes: [sys_]
+irregulars:
+  data: data
+no_inflection: false
+type_names:
+  sys_user: Admin
+`
+	if err = os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	for table, want := range map[string]string{"sys_user": "Admin", "sys_roles": "Role", "sys_data": "Data"} {
+		if got := s.TypeName(table); got != want {
+			t.Errorf("TypeName(%q) = %q, want %q", table, got, want)
+		}
+	}
+
+	if _, err = Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
@@ -0,0 +1,98 @@
+package prisma
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // a keyword, an identifier or a type
+	tokString                  // a string: "text"
+	tokNumber                  // a numeric literal
+	tokDoc                     // a documentation comment: /// text
+	tokPunct                   // a single punctuation character
+)
+
+type token struct {
+	kind tokenKind
+	// val is the text of the token, without its quotes or its slashes.
+	val string
+	// line is the line of the token, to locate the errors.
+	line int
+}
+
+func (t token) is(word string) bool {
+	return t.kind == tokWord && t.val == word
+}
+
+func (t token) punct(c string) bool {
+	return t.kind == tokPunct && t.val == c
+}
+
+// tokenize splits a Prisma schema into tokens. The comments are dropped, except the documentation
+// comments, which document the next model, enum or field.
+func tokenize(src string) ([]token, error) {
+	var (
+		tokens []token
+		s      = []rune(src)
+		line   = 1
+	)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(s) && s[i+1] == '/':
+			j := i
+			for j < len(s) && s[j] != '\n' {
+				j++
+			}
+			if text, ok := strings.CutPrefix(string(s[i:j]), "///"); ok {
+				tokens = append(tokens, token{kind: tokDoc, val: strings.TrimSpace(text), line: line})
+			}
+			i = j
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != '"' && s[j] != '\n'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+					if e, ok := map[rune]rune{'n': '\n', 't': '\t'}[s[j]]; ok {
+						b.WriteRune(e)
+						continue
+					}
+				}
+				b.WriteRune(s[j])
+			}
+			if j >= len(s) || s[j] != '"' {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, token{kind: tokString, val: b.String(), line: line})
+			i = j + 1
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(s) && (unicode.IsDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, val: string(s[i:j]), line: line})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(s[j]) || unicode.IsDigit(s[j]) || s[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, val: string(s[i:j]), line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokPunct, val: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
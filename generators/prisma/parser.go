@@ -0,0 +1,462 @@
+package prisma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// document is a parsed Prisma schema, before its names are resolved. A schema may be split into
+// several files, which are parsed into the same document.
+type document struct {
+	// provider is the provider of the datasource, e.g. "postgresql".
+	provider string
+	models   []*model
+	enums    []*enum
+}
+
+type model struct {
+	name   string
+	doc    string
+	fields []*field
+	attrs  []*attribute
+	line   int
+}
+
+type field struct {
+	name string
+	// typ is the type of the field: a scalar type, an enum, a model or Unsupported.
+	typ string
+	// unsupported is the database type of an Unsupported("type") field.
+	unsupported string
+	optional    bool
+	list        bool
+	doc         string
+	attrs       []*attribute
+	line        int
+}
+
+type enum struct {
+	name   string
+	values []*enumValue
+	attrs  []*attribute
+}
+
+type enumValue struct {
+	name  string
+	attrs []*attribute
+}
+
+// attribute is a field attribute, e.g. @default(now()), or a block attribute, e.g. @@index([a, b]).
+type attribute struct {
+	// name is the name of the attribute, without its @, e.g. "default" or "db.VarChar".
+	name string
+	args []*arg
+	line int
+}
+
+// arg is an argument of an attribute or of a function, e.g. references: [id].
+type arg struct {
+	// name is empty for the positional arguments.
+	name  string
+	value []token
+}
+
+// attr returns the attribute of the given name, or nil.
+func attr(attrs []*attribute, name string) *attribute {
+	for _, a := range attrs {
+		if a.name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// arg returns the argument of the given name, or the positional argument at pos when pos is not -1.
+func (a *attribute) arg(name string, pos int) []token {
+	if a == nil {
+		return nil
+	}
+	for i, g := range a.args {
+		if g.name == name || g.name == "" && i == pos {
+			return g.value
+		}
+	}
+	return nil
+}
+
+// str returns the string argument of the given name, or the positional one at pos.
+func (a *attribute) str(name string, pos int) string {
+	if v := a.arg(name, pos); len(v) == 1 && v[0].kind == tokString {
+		return v[0].val
+	}
+	return ""
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return token{kind: tokPunct}
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+	return t
+}
+
+// line returns the line of the next token, or of the last one at the end of the schema.
+func (p *parser) line() int {
+	if p.eof() {
+		if len(p.toks) == 0 {
+			return 1
+		}
+		return p.toks[len(p.toks)-1].line
+	}
+	return p.peek().line
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line(), fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expect(c string) error {
+	if !p.peek().punct(c) {
+		if p.eof() {
+			return p.errorf("expected %q, got the end of the schema", c)
+		}
+		return p.errorf("expected %q, got %q", c, p.peek().val)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) word() (string, error) {
+	if p.peek().kind != tokWord {
+		return "", p.errorf("expected a name, got %q", p.peek().val)
+	}
+	return p.next().val, nil
+}
+
+// docs reads the documentation comments before a definition.
+func (p *parser) docs() string {
+	var lines []string
+	for p.peek().kind == tokDoc {
+		lines = append(lines, p.next().val)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// skipBlock skips a block and the tokens before it.
+func (p *parser) skipBlock() error {
+	for !p.eof() && !p.peek().punct("{") {
+		p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		if p.eof() {
+			return p.errorf("unterminated block")
+		}
+		switch t := p.next(); {
+		case t.punct("{"):
+			depth++
+		case t.punct("}"):
+			depth--
+		}
+	}
+	return nil
+}
+
+// parse parses a file of the schema into the document.
+func (d *document) parse(src string) error {
+	toks, err := tokenize(src)
+	if err != nil {
+		return err
+	}
+	p := &parser{toks: toks}
+	for !p.eof() {
+		doc := p.docs()
+		if p.eof() {
+			break
+		}
+		switch t := p.next(); {
+		case t.is("datasource"):
+			err = p.datasource(d)
+		case t.is("model"):
+			err = p.model(d, doc)
+		case t.is("enum"):
+			err = p.enum(d)
+		case t.is("generator"), t.is("view"), t.is("type"):
+			// The views and the composite types of MongoDB have no table.
+			err = p.skipBlock()
+		default:
+			return fmt.Errorf("line %d: unexpected %q", t.line, t.val)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) datasource(d *document) error {
+	if _, err := p.word(); err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated datasource")
+		}
+		if t := p.next(); t.is("provider") && p.peek().punct("=") && p.peekAt(1).kind == tokString {
+			p.next()
+			d.provider = p.next().val
+		}
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) model(d *document, doc string) error {
+	m := &model{doc: doc, line: p.line()}
+	var err error
+	if m.name, err = p.word(); err != nil {
+		return err
+	}
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated model %q", m.name)
+		}
+		doc := p.docs()
+		switch {
+		case p.peek().punct("}"):
+		case p.peek().punct("@") && p.peekAt(1).punct("@"):
+			p.pos += 2
+			a, err := p.attribute()
+			if err != nil {
+				return err
+			}
+			m.attrs = append(m.attrs, a)
+		default:
+			f, err := p.field()
+			if err != nil {
+				return err
+			}
+			f.doc = doc
+			m.fields = append(m.fields, f)
+		}
+	}
+	p.next()
+	d.models = append(d.models, m)
+	return nil
+}
+
+// field reads a field, e.g. email String? @unique @db.VarChar(255).
+func (p *parser) field() (*field, error) {
+	f := &field{line: p.line()}
+	var err error
+	if f.name, err = p.word(); err != nil {
+		return nil, err
+	}
+	if f.typ, err = p.word(); err != nil {
+		return nil, err
+	}
+	if p.peek().punct("(") {
+		// Unsupported("circle").
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 1 && len(args[0].value) == 1 {
+			f.unsupported = args[0].value[0].val
+		}
+	}
+	switch {
+	case p.peek().punct("[") && p.peekAt(1).punct("]"):
+		p.pos += 2
+		f.list = true
+	case p.peek().punct("?"):
+		p.next()
+		f.optional = true
+	}
+	for p.peek().punct("@") && !p.peekAt(1).punct("@") {
+		p.next()
+		a, err := p.attribute()
+		if err != nil {
+			return nil, err
+		}
+		f.attrs = append(f.attrs, a)
+	}
+	return f, nil
+}
+
+// attribute reads an attribute after its @ or @@, e.g. db.VarChar(255) or relation(fields: [a]).
+func (p *parser) attribute() (*attribute, error) {
+	a := &attribute{line: p.line()}
+	var err error
+	if a.name, err = p.word(); err != nil {
+		return nil, err
+	}
+	if p.peek().punct(".") {
+		p.next()
+		name, err := p.word()
+		if err != nil {
+			return nil, err
+		}
+		a.name += "." + name
+	}
+	if p.peek().punct("(") {
+		if a.args, err = p.args(); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// args reads the arguments between parentheses, splitting them on the commas outside of the nested
+// lists and calls.
+func (p *parser) args() ([]*arg, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var (
+		args  []*arg
+		cur   = &arg{}
+		depth int
+	)
+	for {
+		if p.eof() {
+			return nil, p.errorf("unterminated arguments")
+		}
+		t := p.next()
+		switch {
+		case depth == 0 && t.punct(")"):
+			if len(cur.value) > 0 {
+				args = append(args, cur)
+			}
+			return args, nil
+		case depth == 0 && t.punct(","):
+			args, cur = append(args, cur), &arg{}
+		case depth == 0 && t.punct(":") && cur.name == "" && len(cur.value) == 1 && cur.value[0].kind == tokWord:
+			cur.name, cur.value = cur.value[0].val, nil
+		default:
+			if t.punct("(") || t.punct("[") {
+				depth++
+			} else if t.punct(")") || t.punct("]") {
+				depth--
+			}
+			cur.value = append(cur.value, t)
+		}
+	}
+}
+
+func (p *parser) enum(d *document) error {
+	name, err := p.word()
+	if err != nil {
+		return err
+	}
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	e := &enum{name: name}
+	for !p.peek().punct("}") {
+		if p.eof() {
+			return p.errorf("unterminated enum %q", name)
+		}
+		p.docs()
+		switch {
+		case p.peek().punct("}"):
+		case p.peek().punct("@") && p.peekAt(1).punct("@"):
+			p.pos += 2
+			a, err := p.attribute()
+			if err != nil {
+				return err
+			}
+			e.attrs = append(e.attrs, a)
+		default:
+			v := &enumValue{}
+			if v.name, err = p.word(); err != nil {
+				return err
+			}
+			for p.peek().punct("@") && !p.peekAt(1).punct("@") {
+				p.next()
+				a, err := p.attribute()
+				if err != nil {
+					return err
+				}
+				v.attrs = append(v.attrs, a)
+			}
+			e.values = append(e.values, v)
+		}
+	}
+	p.next()
+	d.enums = append(d.enums, e)
+	return nil
+}
+
+// fieldRef is a field of a list argument, e.g. title(sort: Desc) in @@index([title(sort: Desc)]).
+type fieldRef struct {
+	name string
+	desc bool
+}
+
+// fieldList reads a list of fields, e.g. [a, b(sort: Desc)].
+func fieldList(value []token) ([]fieldRef, error) {
+	if len(value) < 2 || !value[0].punct("[") || !value[len(value)-1].punct("]") {
+		return nil, fmt.Errorf("expected a list of fields, got %q", render(value))
+	}
+	var refs []fieldRef
+	for i := 1; i < len(value)-1; i++ {
+		t := value[i]
+		switch {
+		case t.punct(","):
+		case t.kind == tokWord:
+			refs = append(refs, fieldRef{name: t.val})
+		case t.punct("("):
+			// The arguments of the previous field.
+			for ; i < len(value)-1 && !value[i].punct(")"); i++ {
+				if value[i].is("Desc") && len(refs) > 0 {
+					refs[len(refs)-1].desc = true
+				}
+			}
+		default:
+			return nil, fmt.Errorf("expected a list of fields, got %q", render(value))
+		}
+	}
+	return refs, nil
+}
+
+// render returns the text of the tokens, e.g. to quote them in an error.
+func render(value []token) string {
+	var b strings.Builder
+	for _, t := range value {
+		if t.kind == tokString {
+			b.WriteString(`"` + t.val + `"`)
+		} else {
+			b.WriteString(t.val)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,606 @@
+// Package prisma builds a database schema from a Prisma schema (schema.prisma). It lets the importers
+// generate code from the data model of a project that manages its database with Prisma, without a
+// database.
+//
+// The models become tables and their scalar and enum fields become columns, named by their @map and
+// @@map attributes. The column types follow the provider of the datasource and the native type
+// attributes, e.g. @db.VarChar(255). The @id, @unique, @@id, @@unique and @@index attributes become
+// the keys and indexes, the @relation attributes become foreign keys, the implicit many-to-many
+// relations become join tables, e.g. _CategoryToPost(A, B), and the documentation comments (///)
+// become the comments of the tables and columns.
+package prisma
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
+)
+
+// Scheme is the DSN scheme of a Prisma schema, e.g. "prisma://prisma/schema.prisma". The path may
+// also be a folder of .prisma files.
+const Scheme = "prisma"
+
+// Load reads the Prisma schema file, or the .prisma files of a folder, and returns its schema.
+func Load(path string) (*schema.Schema, error) {
+	s, _, err := LoadDialect(path)
+	return s, err
+}
+
+// LoadDialect is like Load, but also returns the name of the dialect of the column types: "mysql",
+// "postgres" or "sqlite".
+func LoadDialect(path string) (*schema.Schema, string, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, "", fmt.Errorf("prisma: %w", err)
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.prisma")); err != nil {
+			return nil, "", fmt.Errorf("prisma: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, "", fmt.Errorf("prisma: no .prisma files in %s", path)
+		}
+	}
+	d := &document{}
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			return nil, "", fmt.Errorf("prisma: %w", err)
+		}
+		if err = d.parse(string(content)); err != nil {
+			return nil, "", fmt.Errorf("prisma: %s: %w", f, err)
+		}
+	}
+	s, err := d.build()
+	if err != nil {
+		return nil, "", fmt.Errorf("prisma: %w", err)
+	}
+	// The build has checked the provider.
+	dl, _ := d.dialect()
+	return s, dialectNames[dl], nil
+}
+
+// Parse parses a Prisma schema and returns its schema.
+func Parse(src string) (*schema.Schema, error) {
+	d := &document{}
+	if err := d.parse(src); err != nil {
+		return nil, fmt.Errorf("prisma: %w", err)
+	}
+	s, err := d.build()
+	if err != nil {
+		return nil, fmt.Errorf("prisma: %w", err)
+	}
+	return s, nil
+}
+
+// dialect is the database of the datasource, which decides the column types.
+type dialect int
+
+const (
+	dialectPostgres dialect = iota
+	dialectMySQL
+	dialectSQLite
+)
+
+// dialectNames holds the names of the dialects.
+var dialectNames = [...]string{dialectPostgres: "postgres", dialectMySQL: "mysql", dialectSQLite: "sqlite"}
+
+// scalars holds the column types of the scalar types of Prisma, by dialect.
+var scalars = map[string][3]string{
+	"String":   {dialectPostgres: "text", dialectMySQL: "varchar(191)", dialectSQLite: "text"},
+	"Boolean":  {dialectPostgres: "boolean", dialectMySQL: "tinyint(1)", dialectSQLite: "boolean"},
+	"Int":      {dialectPostgres: "integer", dialectMySQL: "int", dialectSQLite: "integer"},
+	"BigInt":   {dialectPostgres: "bigint", dialectMySQL: "bigint", dialectSQLite: "bigint"},
+	"Float":    {dialectPostgres: "double precision", dialectMySQL: "double", dialectSQLite: "real"},
+	"Decimal":  {dialectPostgres: "decimal(65,30)", dialectMySQL: "decimal(65,30)", dialectSQLite: "decimal"},
+	"DateTime": {dialectPostgres: "timestamp(3)", dialectMySQL: "datetime(3)", dialectSQLite: "datetime"},
+	"Json":     {dialectPostgres: "jsonb", dialectMySQL: "json", dialectSQLite: "json"},
+	"Bytes":    {dialectPostgres: "bytea", dialectMySQL: "longblob", dialectSQLite: "blob"},
+}
+
+// nativeTypes holds the native type attributes whose column type is not their lower-cased name.
+var nativeTypes = map[string]string{
+	"DoublePrecision": "double precision",
+	"JsonB":           "jsonb",
+	"ByteA":           "bytea",
+	"VarBit":          "bit varying",
+}
+
+// serials holds the PostgreSQL serial type of an integer column with @default(autoincrement()).
+var serials = map[string]string{
+	"smallint": "smallserial",
+	"integer":  "serial",
+	"int":      "serial",
+	"bigint":   "bigserial",
+}
+
+// serialIntegers holds the integer type of the columns that reference a PostgreSQL serial column.
+var serialIntegers = map[string]string{
+	"smallserial": "smallint",
+	"serial":      "integer",
+	"bigserial":   "bigint",
+}
+
+var referenceOptions = map[string]schema.ReferenceOption{
+	"Cascade":    schema.Cascade,
+	"Restrict":   schema.Restrict,
+	"NoAction":   schema.NoAction,
+	"SetNull":    schema.SetNull,
+	"SetDefault": schema.SetDefault,
+}
+
+// builder converts a document to an Atlas schema.
+type builder struct {
+	d       *document
+	dialect dialect
+	s       *schema.Schema
+	models  map[string]*model
+	tables  map[string]*schema.Table
+	enums   map[string]*enum
+	types   map[string]*schema.EnumType
+	// columns holds the column of each scalar field, by model and field name.
+	columns map[string]map[string]*schema.Column
+}
+
+func (d *document) build() (*schema.Schema, error) {
+	b := &builder{
+		d:       d,
+		s:       &schema.Schema{},
+		models:  make(map[string]*model),
+		tables:  make(map[string]*schema.Table),
+		enums:   make(map[string]*enum),
+		types:   make(map[string]*schema.EnumType),
+		columns: make(map[string]map[string]*schema.Column),
+	}
+	var err error
+	if b.dialect, err = d.dialect(); err != nil {
+		return nil, err
+	}
+	for _, e := range d.enums {
+		t := &schema.EnumType{T: e.name}
+		if name := attr(e.attrs, "map").str("name", 0); name != "" {
+			t.T = name
+		}
+		for _, v := range e.values {
+			t.Values = append(t.Values, enumValueName(v))
+		}
+		b.enums[e.name], b.types[e.name] = e, t
+	}
+	for _, m := range d.models {
+		if _, ok := b.models[m.name]; ok {
+			return nil, fmt.Errorf("line %d: model %q is defined twice", m.line, m.name)
+		}
+		b.models[m.name] = m
+	}
+	for _, m := range d.models {
+		if attr(m.attrs, "ignore") != nil {
+			continue
+		}
+		if err := b.table(m); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range d.models {
+		if t, ok := b.tables[m.name]; ok {
+			if err := b.keys(m, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// The join tables reference the primary keys, so they are added once all the keys are.
+	for _, m := range d.models {
+		if _, ok := b.tables[m.name]; ok {
+			if err := b.joinTables(m); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, t := range b.s.Tables {
+		for _, idx := range t.Indexes {
+			for _, part := range idx.Parts {
+				part.C.Indexes = append(part.C.Indexes, idx)
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			for _, c := range fk.Columns {
+				c.ForeignKeys = append(c.ForeignKeys, fk)
+			}
+		}
+	}
+	return b.s, nil
+}
+
+// enumValueName returns the database value of an enum value, named by its @map attribute.
+func enumValueName(v *enumValue) string {
+	if name := attr(v.attrs, "map").str("name", 0); name != "" {
+		return name
+	}
+	return v.name
+}
+
+// table adds the table of a model with its columns.
+func (b *builder) table(m *model) error {
+	name := m.name
+	if n := attr(m.attrs, "map").str("name", 0); n != "" {
+		name = n
+	}
+	t := schema.NewTable(name)
+	if m.doc != "" {
+		t.SetComment(m.doc)
+	}
+	b.columns[m.name] = make(map[string]*schema.Column)
+	for _, f := range m.fields {
+		if _, ok := b.models[f.typ]; ok || attr(f.attrs, "ignore") != nil {
+			// The relation fields have no column.
+			continue
+		}
+		c, err := b.column(f)
+		if err != nil {
+			return fmt.Errorf("line %d: %s.%s: %w", f.line, m.name, f.name, err)
+		}
+		if _, ok := t.Column(c.Name); ok {
+			return fmt.Errorf("line %d: column %q is defined twice in model %q", f.line, c.Name, m.name)
+		}
+		t.AddColumns(c)
+		b.columns[m.name][f.name] = c
+	}
+	b.tables[m.name] = t
+	b.s.AddTables(t)
+	return nil
+}
+
+// dialect returns the dialect of the provider of the datasource. The providers that have no dialect of
+// their own, e.g. sqlserver, and the schemas without a datasource take the PostgreSQL types.
+func (d *document) dialect() (dialect, error) {
+	switch d.provider {
+	case "", "postgresql", "postgres", "cockroachdb", "sqlserver":
+		return dialectPostgres, nil
+	case "mysql":
+		return dialectMySQL, nil
+	case "sqlite":
+		return dialectSQLite, nil
+	}
+	return 0, fmt.Errorf("the %s provider is not supported", d.provider)
+}
+
+// column returns the column of a scalar or enum field.
+func (b *builder) column(f *field) (*schema.Column, error) {
+	name := f.name
+	if n := attr(f.attrs, "map").str("name", 0); n != "" {
+		name = n
+	}
+	c := schema.NewColumn(name)
+	c.Type = &schema.ColumnType{Null: f.optional}
+	switch native := nativeType(f.attrs); {
+	case f.typ == "Unsupported":
+		c.Type.Raw = f.unsupported
+	case b.types[f.typ] != nil:
+		c.Type.Raw, c.Type.Type = b.types[f.typ].T, b.types[f.typ]
+	case native != "":
+		c.Type.Raw = native
+	case scalars[f.typ] != [3]string{}:
+		c.Type.Raw = scalars[f.typ][b.dialect]
+	default:
+		return nil, fmt.Errorf("unknown type %q", f.typ)
+	}
+	if def := attr(f.attrs, "default"); def != nil {
+		if err := b.setDefault(c, f, def.arg("value", 0)); err != nil {
+			return nil, err
+		}
+	}
+	if f.list {
+		c.Type.Raw += "[]"
+	}
+	if c.Type.Type == nil {
+		c.Type.Type = b.parseType(c.Type.Raw)
+	}
+	if attr(f.attrs, "updatedAt") != nil {
+		c.AddAttrs(&mysql.OnUpdate{A: "CURRENT_TIMESTAMP"})
+	}
+	if f.doc != "" {
+		c.SetComment(f.doc)
+	}
+	return c, nil
+}
+
+// nativeType returns the column type of a native type attribute, e.g. varchar(255) for
+// @db.VarChar(255) or int unsigned for @db.UnsignedInt.
+func nativeType(attrs []*attribute) string {
+	for _, a := range attrs {
+		name, ok := strings.CutPrefix(a.name, "db.")
+		if !ok {
+			continue
+		}
+		var args []string
+		for _, g := range a.args {
+			args = append(args, render(g.value))
+		}
+		suffix := ""
+		if len(args) > 0 {
+			suffix = "(" + strings.Join(args, ",") + ")"
+		}
+		if base, ok := strings.CutPrefix(name, "Unsigned"); ok {
+			return strings.ToLower(base) + suffix + " unsigned"
+		}
+		if typ, ok := nativeTypes[name]; ok {
+			return typ + suffix
+		}
+		return strings.ToLower(name) + suffix
+	}
+	return ""
+}
+
+// parseType parses a column type as a type of the dialect, and then of any dialect.
+func (b *builder) parseType(raw string) schema.Type {
+	parse := [...]func(string) (schema.Type, error){
+		dialectPostgres: postgres.ParseType,
+		dialectMySQL:    mysql.ParseType,
+		dialectSQLite:   sqlite.ParseType,
+	}[b.dialect]
+	typ, err := parse(raw)
+	if err != nil {
+		return migrations.ParseType(raw)
+	}
+	switch typ.(type) {
+	case *schema.UnsupportedType, *postgres.UserDefinedType, *sqlite.UserDefinedType:
+		return migrations.ParseType(raw)
+	}
+	return typ
+}
+
+// setDefault sets the default value of a column from its @default attribute. The values that Prisma
+// generates itself, e.g. uuid() or cuid(), have no default in the database.
+func (b *builder) setDefault(c *schema.Column, f *field, value []token) error {
+	switch {
+	case len(value) == 1 && value[0].kind == tokString:
+		c.Default = &schema.Literal{V: "'" + strings.ReplaceAll(value[0].val, "'", "''") + "'"}
+	case len(value) == 1 && (value[0].kind == tokNumber || value[0].is("true") || value[0].is("false")):
+		c.Default = &schema.Literal{V: value[0].val}
+	case len(value) == 2 && value[0].punct("-") && value[1].kind == tokNumber:
+		c.Default = &schema.Literal{V: "-" + value[1].val}
+	case len(value) == 1 && value[0].kind == tokWord:
+		e, ok := b.enums[f.typ]
+		if !ok {
+			return fmt.Errorf("the default %q is not a value of an enum", value[0].val)
+		}
+		i := slices.IndexFunc(e.values, func(v *enumValue) bool { return v.name == value[0].val })
+		if i < 0 {
+			return fmt.Errorf("%q is not a value of the enum %q", value[0].val, e.name)
+		}
+		c.Default = &schema.Literal{V: "'" + enumValueName(e.values[i]) + "'"}
+	case len(value) >= 3 && value[0].kind == tokWord && value[1].punct("("):
+		switch value[0].val {
+		case "autoincrement", "sequence":
+			b.autoIncrement(c)
+		case "now":
+			c.Default = &schema.RawExpr{X: "CURRENT_TIMESTAMP"}
+		case "dbgenerated":
+			if value[2].kind == tokString {
+				c.Default = &schema.RawExpr{X: value[2].val}
+			}
+		}
+	}
+	return nil
+}
+
+// autoIncrement makes the column an auto-increment column of the dialect.
+func (b *builder) autoIncrement(c *schema.Column) {
+	switch b.dialect {
+	case dialectPostgres:
+		if serial, ok := serials[c.Type.Raw]; ok {
+			c.Type.Raw = serial
+		}
+	case dialectMySQL:
+		c.AddAttrs(&mysql.AutoIncrement{})
+	case dialectSQLite:
+		c.AddAttrs(&sqlite.AutoIncrement{})
+	}
+}
+
+// keys adds the primary key, the indexes and the foreign keys of a model to its table.
+func (b *builder) keys(m *model, t *schema.Table) error {
+	for _, f := range m.fields {
+		c, ok := b.columns[m.name][f.name]
+		if !ok {
+			if rel := attr(f.attrs, "relation"); rel != nil && rel.arg("fields", -1) != nil {
+				if err := b.foreignKey(m, t, f, rel); err != nil {
+					return fmt.Errorf("line %d: %s.%s: %w", f.line, m.name, f.name, err)
+				}
+			}
+			continue
+		}
+		if id := attr(f.attrs, "id"); id != nil {
+			b.primaryKey(t, id.str("map", -1), []*schema.Column{c})
+		}
+		if u := attr(f.attrs, "unique"); u != nil {
+			b.index(t, u.str("map", -1), true, []*schema.Column{c}, nil)
+		}
+	}
+	for _, a := range m.attrs {
+		if a.name != "id" && a.name != "unique" && a.name != "index" && a.name != "fulltext" {
+			continue
+		}
+		refs, err := fieldList(a.arg("fields", 0))
+		if err != nil {
+			return fmt.Errorf("line %d: %s: @@%s: %w", a.line, m.name, a.name, err)
+		}
+		var (
+			cs   []*schema.Column
+			desc []bool
+		)
+		for _, r := range refs {
+			c, ok := b.columns[m.name][r.name]
+			if !ok {
+				return fmt.Errorf("line %d: %s: @@%s: field %q does not exist", a.line, m.name, a.name, r.name)
+			}
+			cs, desc = append(cs, c), append(desc, r.desc)
+		}
+		if a.name == "id" {
+			b.primaryKey(t, a.str("map", -1), cs)
+		} else {
+			b.index(t, a.str("map", -1), a.name == "unique", cs, desc)
+		}
+	}
+	return nil
+}
+
+func (b *builder) primaryKey(t *schema.Table, name string, cs []*schema.Column) {
+	if name == "" {
+		name = t.Name + "_pkey"
+	}
+	pk := &schema.Index{Name: name, Unique: true, Table: t}
+	for i, c := range cs {
+		c.Type.Null = false
+		pk.Parts = append(pk.Parts, &schema.IndexPart{SeqNo: i, C: c})
+	}
+	t.SetPrimaryKey(pk)
+}
+
+// index adds an index, named as Prisma names it when it has no map argument.
+func (b *builder) index(t *schema.Table, name string, unique bool, cs []*schema.Column, desc []bool) {
+	if name == "" {
+		var names []string
+		for _, c := range cs {
+			names = append(names, c.Name)
+		}
+		suffix := "idx"
+		if unique {
+			suffix = "key"
+		}
+		name = t.Name + "_" + strings.Join(names, "_") + "_" + suffix
+	}
+	idx := &schema.Index{Name: name, Unique: unique, Table: t}
+	for i, c := range cs {
+		idx.Parts = append(idx.Parts, &schema.IndexPart{SeqNo: i, C: c, Desc: i < len(desc) && desc[i]})
+	}
+	t.AddIndexes(idx)
+}
+
+// foreignKey adds the foreign key of a relation field, e.g.
+// author User @relation(fields: [authorId], references: [id], onDelete: Cascade).
+func (b *builder) foreignKey(m *model, t *schema.Table, f *field, rel *attribute) error {
+	refTable, ok := b.tables[f.typ]
+	if !ok {
+		return fmt.Errorf("model %q is ignored", f.typ)
+	}
+	fields, err := fieldList(rel.arg("fields", -1))
+	if err != nil {
+		return err
+	}
+	references, err := fieldList(rel.arg("references", -1))
+	if err != nil {
+		return err
+	}
+	if len(fields) != len(references) {
+		return fmt.Errorf("the relation has %d fields and %d references", len(fields), len(references))
+	}
+	fk := &schema.ForeignKey{Symbol: rel.str("map", -1), Table: t, RefTable: refTable}
+	var names []string
+	for i := range fields {
+		c, ok := b.columns[m.name][fields[i].name]
+		if !ok {
+			return fmt.Errorf("field %q does not exist", fields[i].name)
+		}
+		rc, ok := b.columns[f.typ][references[i].name]
+		if !ok {
+			return fmt.Errorf("field %q does not exist in model %q", references[i].name, f.typ)
+		}
+		fk.Columns, fk.RefColumns = append(fk.Columns, c), append(fk.RefColumns, rc)
+		names = append(names, c.Name)
+	}
+	for name, opt := range map[string]*schema.ReferenceOption{"onDelete": &fk.OnDelete, "onUpdate": &fk.OnUpdate} {
+		if v := rel.arg(name, -1); len(v) == 1 {
+			if *opt, ok = referenceOptions[v[0].val]; !ok {
+				return fmt.Errorf("unknown referential action %q", v[0].val)
+			}
+		}
+	}
+	if fk.Symbol == "" {
+		fk.Symbol = t.Name + "_" + strings.Join(names, "_") + "_fkey"
+	}
+	t.ForeignKeys = append(t.ForeignKeys, fk)
+	return nil
+}
+
+// joinTables adds the join tables of the implicit many-to-many relations of a model, i.e. the list
+// relation fields whose opposite field is a list too, e.g. posts Post[] and categories Category[].
+// A join table is named as Prisma names it, e.g. _CategoryToPost or _name for @relation("name"). Its
+// column A references the model whose name comes first and its column B the other one.
+func (b *builder) joinTables(m *model) error {
+	for _, f := range m.fields {
+		if !f.list || b.models[f.typ] == nil || attr(f.attrs, "ignore") != nil {
+			continue
+		}
+		rel := attr(f.attrs, "relation")
+		name := rel.str("name", 0)
+		var opposite *field
+		for _, o := range b.models[f.typ].fields {
+			if o != f && o.typ == m.name && attr(o.attrs, "relation").str("name", 0) == name {
+				opposite = o
+				break
+			}
+		}
+		if opposite == nil || !opposite.list {
+			// The foreign key of a one-to-many relation is defined by the opposite field.
+			continue
+		}
+		models := []string{m.name, f.typ}
+		slices.Sort(models)
+		if name == "" {
+			name = models[0] + "To" + models[1]
+		}
+		name = "_" + name
+		if _, ok := b.s.Table(name); ok {
+			continue
+		}
+		t, err := b.joinTable(name, models)
+		if err != nil {
+			return fmt.Errorf("line %d: %s.%s: %w", f.line, m.name, f.name, err)
+		}
+		b.s.AddTables(t)
+	}
+	return nil
+}
+
+// joinTable returns the join table of an implicit many-to-many relation between two models.
+func (b *builder) joinTable(name string, models []string) (*schema.Table, error) {
+	t := schema.NewTable(name)
+	var cs []*schema.Column
+	for i, col := range []string{"A", "B"} {
+		refTable, ok := b.tables[models[i]]
+		if !ok {
+			return nil, fmt.Errorf("model %q is ignored", models[i])
+		}
+		if refTable.PrimaryKey == nil || len(refTable.PrimaryKey.Parts) != 1 {
+			return nil, fmt.Errorf("model %q has no single-field id for the many-to-many relation", models[i])
+		}
+		rc := refTable.PrimaryKey.Parts[0].C
+		c := schema.NewColumn(col)
+		c.Type = &schema.ColumnType{Raw: rc.Type.Raw, Type: rc.Type.Type}
+		if typ, ok := serialIntegers[rc.Type.Raw]; ok {
+			c.Type.Raw, c.Type.Type = typ, b.parseType(typ)
+		}
+		t.AddColumns(c)
+		t.ForeignKeys = append(t.ForeignKeys, &schema.ForeignKey{
+			Symbol:     name + "_" + col + "_fkey",
+			Table:      t,
+			Columns:    []*schema.Column{c},
+			RefTable:   refTable,
+			RefColumns: []*schema.Column{rc},
+			OnUpdate:   schema.Cascade,
+			OnDelete:   schema.Cascade,
+		})
+		cs = append(cs, c)
+	}
+	b.primaryKey(t, name+"_AB_pkey", cs)
+	b.index(t, name+"_B_index", false, cs[1:], nil)
+	return t, nil
+}
@@ -0,0 +1,302 @@
+package prisma
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+)
+
+const blog = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+generator client {
+  provider = "prisma-client-js"
+}
+
+/// The users of the blog.
+model User {
+  id        Int      @id @default(autoincrement())
+  /// The login email.
+  email     String   @unique @db.VarChar(255)
+  name      String?
+  role      Role     @default(USER)
+  tags      String[]
+  createdAt DateTime @default(now()) @map("created_at")
+  updatedAt DateTime @updatedAt @map("updated_at")
+  posts     Post[]
+
+  @@map("users")
+}
+
+model Post {
+  id       String  @id @default(uuid()) @db.Uuid
+  title    String
+  score    Decimal @default(-1.5) @db.Decimal(10, 2)
+  author   User    @relation(fields: [authorId], references: [id], onDelete: Cascade)
+  authorId Int     @map("author_id")
+
+  @@unique([authorId, title])
+  @@index([title(sort: Desc)], map: "posts_title")
+  @@map("posts")
+}
+
+// A join table with a composite primary key.
+model PostTag {
+  postId String
+  tag    String
+  post   Post   @relation(fields: [postId], references: [id])
+
+  @@id([postId, tag])
+}
+
+enum Role {
+  USER
+  ADMIN @map("admin")
+
+  @@map("user_role")
+}
+`
+
+func schemaAttr[T schema.Attr](attrs []schema.Attr) (T, bool) {
+	for _, a := range attrs {
+		if t, ok := a.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse(blog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tbl := range s.Tables {
+		names = append(names, tbl.Name)
+	}
+	if want := []string{"users", "posts", "PostTag"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected the tables %v, got %v", want, names)
+	}
+
+	users, _ := s.Table("users")
+	if c, _ := schemaAttr[*schema.Comment](users.Attrs); c == nil || c.Text != "The users of the blog." {
+		t.Errorf("expected the documentation of User to be the comment, got %+v", c)
+	}
+	var columns []string
+	for _, c := range users.Columns {
+		columns = append(columns, c.Name)
+	}
+	if want := []string{"id", "email", "name", "role", "tags", "created_at", "updated_at"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("expected the columns %v, got %v", want, columns)
+	}
+	id, _ := users.Column("id")
+	if _, ok := id.Type.Type.(*postgres.SerialType); !ok || users.PrimaryKey == nil || users.PrimaryKey.Parts[0].C != id {
+		t.Errorf("expected id to be the serial primary key, got %#v", id.Type)
+	}
+	email, _ := users.Column("email")
+	if st, ok := email.Type.Type.(*schema.StringType); !ok || st.Size != 255 || email.Type.Null {
+		t.Errorf("expected email to be a not null varchar(255), got %#v", email.Type)
+	}
+	if c, _ := schemaAttr[*schema.Comment](email.Attrs); c == nil || c.Text != "The login email." {
+		t.Errorf("expected the documentation of email to be its comment, got %+v", email.Attrs)
+	}
+	if len(email.Indexes) != 1 || !email.Indexes[0].Unique || email.Indexes[0].Name != "users_email_key" {
+		t.Errorf("expected email to be unique, got %+v", email.Indexes)
+	}
+	if name, _ := users.Column("name"); !name.Type.Null {
+		t.Errorf("expected name to be nullable")
+	}
+	role, _ := users.Column("role")
+	if e, ok := role.Type.Type.(*schema.EnumType); !ok || e.T != "user_role" || !reflect.DeepEqual(e.Values, []string{"USER", "admin"}) {
+		t.Errorf("expected role to be the user_role enum, got %#v", role.Type.Type)
+	}
+	if d, ok := role.Default.(*schema.Literal); !ok || d.V != "'USER'" {
+		t.Errorf("expected the default of role to be 'USER', got %#v", role.Default)
+	}
+	if tags, _ := users.Column("tags"); tags.Type.Raw != "text[]" {
+		t.Errorf("expected tags to be a text array, got %q", tags.Type.Raw)
+	}
+	if c, _ := users.Column("created_at"); c.Default == nil || c.Default.(*schema.RawExpr).X != "CURRENT_TIMESTAMP" {
+		t.Errorf("expected the default of created_at to be CURRENT_TIMESTAMP, got %#v", c.Default)
+	}
+	if c, _ := users.Column("updated_at"); !hasOnUpdate(c) {
+		t.Errorf("expected updated_at to be updated on update, got %+v", c.Attrs)
+	}
+
+	posts, _ := s.Table("posts")
+	pid, _ := posts.Column("id")
+	if _, ok := pid.Type.Type.(*schema.UUIDType); !ok || pid.Default != nil {
+		t.Errorf("expected id to be a uuid without a database default, got %#v", pid)
+	}
+	score, _ := posts.Column("score")
+	if d, ok := score.Type.Type.(*schema.DecimalType); !ok || d.Precision != 10 || d.Scale != 2 {
+		t.Errorf("expected score to be a decimal(10,2), got %#v", score.Type.Type)
+	}
+	if len(posts.ForeignKeys) != 1 {
+		t.Fatalf("expected the foreign key of author, got %+v", posts.ForeignKeys)
+	}
+	fk := posts.ForeignKeys[0]
+	if fk.Symbol != "posts_author_id_fkey" || fk.RefTable != users || fk.RefColumns[0] != id || fk.OnDelete != schema.Cascade {
+		t.Errorf("expected author_id to reference users.id on delete cascade, got %+v", fk)
+	}
+	var indexes []string
+	for _, idx := range posts.Indexes {
+		indexes = append(indexes, idx.Name)
+	}
+	if want := []string{"posts_author_id_title_key", "posts_title"}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("expected the indexes %v, got %v", want, indexes)
+	}
+	if !posts.Indexes[1].Parts[0].Desc {
+		t.Errorf("expected the title index to be descending")
+	}
+
+	postTag, _ := s.Table("PostTag")
+	if pk := postTag.PrimaryKey; pk == nil || len(pk.Parts) != 2 || pk.Name != "PostTag_pkey" {
+		t.Errorf("expected the composite primary key of PostTag, got %+v", pk)
+	}
+}
+
+func hasOnUpdate(c *schema.Column) bool {
+	a, ok := schemaAttr[*mysql.OnUpdate](c.Attrs)
+	return ok && a.A == "CURRENT_TIMESTAMP"
+}
+
+func TestParseMySQL(t *testing.T) {
+	s, err := Parse(`
+datasource db {
+  provider = "mysql"
+  url      = env("DATABASE_URL")
+}
+
+model Pet {
+  id    BigInt  @id @default(autoincrement()) @db.UnsignedBigInt
+  name  String
+  alive Boolean @default(true)
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pets, _ := s.Table("Pet")
+	id, _ := pets.Column("id")
+	if it, ok := id.Type.Type.(*schema.IntegerType); !ok || !it.Unsigned {
+		t.Errorf("expected id to be an unsigned bigint, got %#v", id.Type.Type)
+	}
+	if _, ok := schemaAttr[*mysql.AutoIncrement](id.Attrs); !ok {
+		t.Errorf("expected id to be auto-increment, got %+v", id.Attrs)
+	}
+	if name, _ := pets.Column("name"); name.Type.Raw != "varchar(191)" {
+		t.Errorf("expected name to be a varchar(191), got %q", name.Type.Raw)
+	}
+	if alive, _ := pets.Column("alive"); alive.Default.(*schema.Literal).V != "true" {
+		t.Errorf("expected the default of alive to be true, got %#v", alive.Default)
+	}
+}
+
+func TestParseManyToMany(t *testing.T) {
+	s, err := Parse(`
+model Post {
+  id         Int        @id @default(autoincrement())
+  categories Category[]
+}
+
+model Category {
+  id    String @id @db.Uuid
+  posts Post[]
+}
+
+model User {
+  id        BigInt @id
+  followers User[] @relation("follows")
+  following User[] @relation("follows")
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		table, a, b string
+		types       [2]string
+	}{
+		{table: "_CategoryToPost", a: "Category", b: "Post", types: [2]string{"uuid", "integer"}},
+		{table: "_follows", a: "User", b: "User", types: [2]string{"bigint", "bigint"}},
+	}
+	for _, tt := range tests {
+		join, ok := s.Table(tt.table)
+		if !ok {
+			t.Fatalf("expected the join table %s, got %d tables", tt.table, len(s.Tables))
+		}
+		if len(join.Columns) != 2 || join.PrimaryKey == nil || join.PrimaryKey.Name != tt.table+"_AB_pkey" || len(join.PrimaryKey.Parts) != 2 {
+			t.Fatalf("expected %s to have the primary key (A, B), got %+v", tt.table, join)
+		}
+		for i, ref := range []string{tt.a, tt.b} {
+			c, fk := join.Columns[i], join.ForeignKeys[i]
+			if c.Type.Raw != tt.types[i] || len(c.ForeignKeys) != 1 {
+				t.Errorf("expected %s.%s to be a %s foreign key column, got %q", tt.table, c.Name, tt.types[i], c.Type.Raw)
+			}
+			if fk.Columns[0] != c || fk.RefTable.Name != ref || fk.RefColumns[0].Name != "id" || fk.OnDelete != schema.Cascade {
+				t.Errorf("expected %s.%s to reference %s.id, got %+v", tt.table, c.Name, ref, fk)
+			}
+		}
+		if len(join.Indexes) != 1 || join.Indexes[0].Name != tt.table+"_B_index" || join.Indexes[0].Parts[0].C.Name != "B" {
+			t.Errorf("expected %s to have an index on B, got %+v", tt.table, join.Indexes)
+		}
+	}
+	if len(s.Tables) != 5 {
+		t.Errorf("expected 3 tables and 2 join tables, got %d tables", len(s.Tables))
+	}
+}
+
+func TestLoadFolder(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"schema.prisma": "datasource db {\n  provider = \"sqlite\"\n  url = \"file:dev.db\"\n}",
+		"user.prisma":   "model User {\n  id Int @id\n  pets Pet[]\n}",
+		"pet.prisma":    "model Pet {\n  id Int @id\n  owner User @relation(fields: [ownerId], references: [id])\n  ownerId Int\n}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pets, ok := s.Table("Pet")
+	if !ok || len(pets.ForeignKeys) != 1 || pets.ForeignKeys[0].RefTable.Name != "User" {
+		t.Fatalf("expected Pet to reference User, got %+v", pets)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "model User {\n  id Int @id\n", want: "unterminated model"},
+		{src: "model User {\n  id Int @id\n  data Blob\n}", want: "line 3: User.data: unknown type \"Blob\""},
+		{src: "model User {\n  id Int @id\n  @@index([name])\n}", want: "line 3: User: @@index: field \"name\" does not exist"},
+		{src: "datasource db {\n  provider = \"mongodb\"\n}", want: "the mongodb provider is not supported"},
+		{src: "table users {}", want: "line 1: unexpected \"table\""},
+		{
+			src:  "model A {\n  x Int\n  y Int\n  bs B[]\n  @@id([x, y])\n}\nmodel B {\n  id Int @id\n  as A[]\n}",
+			want: "line 4: A.bs: model \"A\" has no single-field id for the many-to-many relation",
+		},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), "prisma: ") {
+			t.Errorf("Parse(%q): expected an error containing %q, got %v", tt.src, tt.want, err)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package generators
 
 import (
 	"context"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/tx7do/go-utils/code_generator"
+
+	"github.com/tx7do/go-wind-toolkit/generators/annotation"
 )
 
 func TestProtoGenerator_Template_GrpcServiceProto(t *testing.T) {
@@ -48,3 +52,99 @@ func TestProtoGenerator_Template_RestServiceProto(t *testing.T) {
 		t.Fatalf("Generate rest_proto.go failed: %v", err)
 	}
 }
+
+func TestProtoGenerator_Template_ReadOnlyServiceProto(t *testing.T) {
+	g := NewProtoGenerator()
+
+	grpcOpts := code_generator.Options{
+		OutDir: "./output",
+		Vars: map[string]any{
+			"Package":   "report.service.v1",
+			"Model":     "user_score",
+			"ModelName": "用户积分",
+			"ReadOnly":  true,
+			"Fields": []ProtoField{
+				{Name: "id", Type: "int64", Comment: "用户ID", Number: 1},
+				{Name: "score", Type: "int64", Comment: "积分", Number: 2},
+			},
+		},
+	}
+	restOpts := code_generator.Options{
+		OutDir: "./output",
+		Vars: map[string]any{
+			"TargetPackage": "admin.service.v1",
+			"SourcePackage": "report.service.v1",
+			"SourceProto":   "report/service/v1/user_score.proto",
+			"Model":         "user_score",
+			"Path":          "/admin/v1/user-scores",
+			"ModelName":     "用户积分",
+			"ReadOnly":      true,
+		},
+	}
+
+	for name, generate := range map[string]func() (string, error){
+		"grpc": func() (string, error) { return g.GenerateGrpcServiceProto(context.Background(), grpcOpts) },
+		"rest": func() (string, error) { return g.GenerateRestServiceProto(context.Background(), restOpts) },
+	} {
+		path, err := generate()
+		if err != nil {
+			t.Fatalf("Generate %s proto failed: %v", name, err)
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(buf)
+		for _, rpc := range []string{"rpc Create", "rpc Update", "rpc Delete", "rpc BatchCreate", "google/protobuf/empty.proto"} {
+			if strings.Contains(content, rpc) {
+				t.Errorf("%s proto of a read-only model contains %q:\n%s", name, rpc, content)
+			}
+		}
+		if !strings.Contains(content, "rpc List") || !strings.Contains(content, "rpc Get") {
+			t.Errorf("%s proto of a read-only model misses List or Get:\n%s", name, content)
+		}
+	}
+}
+
+func TestProtoGenerator_Template_AnnotatedFields(t *testing.T) {
+	g := NewProtoGenerator()
+
+	opts := code_generator.Options{
+		OutDir: "./output",
+		Vars: map[string]any{
+			"Package":   "account.service.v1",
+			"Model":     "account",
+			"ModelName": "账号",
+			"Fields": []ProtoField{
+				{Name: "id", Type: "int64", Comment: "账号ID", Number: 1},
+				{
+					Name: "status", Type: "int32", Comment: "账号状态", Number: 2,
+					Annotations: annotation.Parse("账号状态 @enum(1:ACTIVE,2:DISABLED) @json(status_code)"),
+				},
+				{
+					Name: "password", Type: "string", Comment: "密码", Number: 3,
+					Annotations: annotation.Parse("密码 @sensitive @minlen(8) @pattern(^\\S+$)"),
+				},
+			},
+		},
+	}
+
+	path, err := g.GenerateGrpcServiceProto(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Generate grpc_proto.go failed: %v", err)
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(buf)
+	for _, want := range []string{
+		"  // 账号状态\n  enum Status {\n    STATUS_UNSPECIFIED = 0;\n    STATUS_ACTIVE = 1;\n    STATUS_DISABLED = 2;\n  }\n",
+		"optional Status status = 2 [\n    json_name = \"status_code\",",
+		`(gnostic.openapi.v3.property) = {description: "密码", write_only: true, min_length: 8, pattern: "^\\S+$"}`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("proto of the annotated fields misses %q:\n%s", want, content)
+		}
+	}
+}
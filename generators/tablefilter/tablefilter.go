@@ -0,0 +1,135 @@
+// Package tablefilter selects the tables to import by their name, so that the ent, gorm and protobuf
+// importers select the same tables for the same include and exclude lists.
+package tablefilter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Filter selects the tables given by include and exclude patterns. A pattern is either:
+//
+//   - a table name, e.g. "users", or a table name qualified with its schema, e.g. "auth.users",
+//   - a glob, e.g. "sys_*" or "auth.*",
+//   - a regular expression between slashes, e.g. "/^log_[0-9]+$/".
+//
+// A pattern prefixed by "!" negates it: "!*_bak" in the includes excludes the backup tables, and
+// "!sys_user" in the excludes keeps sys_user when "sys_*" is excluded.
+//
+// A nil Filter selects all the tables.
+type Filter struct {
+	includes    []pattern
+	notIncludes []pattern
+	excludes    []pattern
+	notExcludes []pattern
+}
+
+// New returns the Filter of the given include and exclude patterns. A table is selected if it matches
+// one of the includes, or if there are no includes, and does not match one of the excludes.
+func New(includes, excludes []string) (*Filter, error) {
+	f := &Filter{}
+	var err error
+	if f.includes, f.notIncludes, err = compile(includes); err != nil {
+		return nil, err
+	}
+	if f.excludes, f.notExcludes, err = compile(excludes); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Match reports if the table of the given schema is selected. The patterns are matched against the
+// name of the table, and against its name qualified with the schema if the schema is not empty.
+func (f *Filter) Match(schema, table string) bool {
+	if f == nil {
+		return true
+	}
+	names := []string{table}
+	if schema != "" {
+		names = append(names, schema+"."+table)
+	}
+	if len(f.includes) > 0 && !matchAny(f.includes, names) {
+		return false
+	}
+	if matchAny(f.notIncludes, names) {
+		return false
+	}
+	return !matchAny(f.excludes, names) || matchAny(f.notExcludes, names)
+}
+
+// Names returns the table names of the includes if they are all plain names, so that the importers can
+// restrict the inspection of the database to them. It returns nil if all the tables must be inspected.
+func (f *Filter) Names() []string {
+	if f == nil || len(f.notIncludes) > 0 {
+		return nil
+	}
+	var names []string
+	for _, p := range f.includes {
+		if p.re != nil || p.glob || strings.Contains(p.text, ".") {
+			return nil
+		}
+		names = append(names, p.text)
+	}
+	return names
+}
+
+type pattern struct {
+	text string
+	glob bool
+	re   *regexp.Regexp
+}
+
+func (p pattern) match(name string) bool {
+	switch {
+	case p.re != nil:
+		return p.re.MatchString(name)
+	case p.glob:
+		ok, _ := path.Match(p.text, name)
+		return ok
+	default:
+		return p.text == name
+	}
+}
+
+// compile parses the patterns, split between the plain and the negated ones.
+func compile(texts []string) (patterns, negated []pattern, err error) {
+	for _, text := range texts {
+		text = strings.TrimSpace(text)
+		not := strings.HasPrefix(text, "!")
+		text = strings.TrimPrefix(text, "!")
+		if text == "" {
+			continue
+		}
+		p := pattern{text: text}
+		switch {
+		case len(text) > 1 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/"):
+			if p.re, err = regexp.Compile(text[1 : len(text)-1]); err != nil {
+				return nil, nil, fmt.Errorf("tablefilter: invalid pattern %q: %w", text, err)
+			}
+		case strings.ContainsAny(text, "*?["):
+			if _, err = path.Match(text, ""); err != nil {
+				return nil, nil, fmt.Errorf("tablefilter: invalid pattern %q: %w", text, err)
+			}
+			p.glob = true
+		}
+		if not {
+			negated = append(negated, p)
+		} else {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns, negated, nil
+}
+
+func matchAny(patterns []pattern, names []string) bool {
+	for _, p := range patterns {
+		for _, name := range names {
+			if p.match(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
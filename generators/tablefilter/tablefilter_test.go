@@ -0,0 +1,118 @@
+package tablefilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		includes []string
+		excludes []string
+		selected []string
+		skipped  []string
+	}{
+		{
+			name:     "no patterns",
+			selected: []string{"users", "sys_users", "auth.users"},
+		},
+		{
+			name:     "names",
+			includes: []string{"users", "auth.roles"},
+			excludes: []string{"users"},
+			selected: []string{"auth.roles"},
+			skipped:  []string{"users", "roles", "billing.roles"},
+		},
+		{
+			name:     "glob",
+			includes: []string{"sys_*"},
+			selected: []string{"sys_users", "auth.sys_roles"},
+			skipped:  []string{"users", "sys"},
+		},
+		{
+			name:     "negated include",
+			includes: []string{"!*_bak"},
+			selected: []string{"users", "bak"},
+			skipped:  []string{"users_bak", "auth.roles_bak"},
+		},
+		{
+			name:     "negated exclude",
+			excludes: []string{"sys_*", "!sys_user"},
+			selected: []string{"users", "sys_user"},
+			skipped:  []string{"sys_role", "auth.sys_menu"},
+		},
+		{
+			name:     "schema glob",
+			includes: []string{"auth.*"},
+			selected: []string{"auth.users", "auth.roles"},
+			skipped:  []string{"users", "billing.users"},
+		},
+		{
+			name:     "regexp",
+			includes: []string{"/^log_[0-9]+$/"},
+			selected: []string{"log_2024", "audit.log_1"},
+			skipped:  []string{"log_", "logs", "log_2024_bak"},
+		},
+	}
+	split := func(name string) (string, string) {
+		for i := len(name) - 1; i >= 0; i-- {
+			if name[i] == '.' {
+				return name[:i], name[i+1:]
+			}
+		}
+		return "", name
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.includes, tt.excludes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.selected {
+				if !f.Match(split(name)) {
+					t.Errorf("Match(%q) = false, want true", name)
+				}
+			}
+			for _, name := range tt.skipped {
+				if f.Match(split(name)) {
+					t.Errorf("Match(%q) = true, want false", name)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterNames(t *testing.T) {
+	tests := []struct {
+		includes []string
+		names    []string
+	}{
+		{includes: nil, names: nil},
+		{includes: []string{"users", "roles"}, names: []string{"users", "roles"}},
+		{includes: []string{"users", "sys_*"}, names: nil},
+		{includes: []string{"users", "!roles"}, names: nil},
+		{includes: []string{"auth.users"}, names: nil},
+	}
+	for _, tt := range tests {
+		f, err := New(tt.includes, []string{"logs"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names := f.Names(); !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("Names() of %q = %q, want %q", tt.includes, names, tt.names)
+		}
+	}
+	if names := (*Filter)(nil).Names(); names != nil {
+		t.Errorf("Names() of a nil filter = %q, want nil", names)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New([]string{"/(/"}, nil); err == nil {
+		t.Error("New accepted an invalid regular expression")
+	}
+	if _, err := New(nil, []string{"sys_[a"}); err == nil {
+		t.Error("New accepted an invalid glob")
+	}
+}
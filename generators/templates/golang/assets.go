@@ -11,9 +11,12 @@ var TemplateMap = map[string][]byte{
 	"ent_client.tpl":   EntClientTemplate,
 	"gorm_client.tpl":  GormClientTemplate,
 	"gorm_init.tpl":    GormInitTemplate,
+	"bun_client.tpl":   BunClientTemplate,
 	"redis_client.tpl": RedisClientTemplate,
 	"ent_repo.tpl":     EntRepoTemplate,
+	"ent_view_repo.tpl": EntViewRepoTemplate,
 	"gorm_repo.tpl":    GormRepoTemplate,
+	"bun_repo.tpl":     BunRepoTemplate,
 	"service.tpl":      ServiceTemplate,
 	"assets.tpl":       AssetsTemplate,
 }
@@ -42,15 +45,24 @@ var GormClientTemplate []byte
 //go:embed gorm_init.tpl
 var GormInitTemplate []byte
 
+//go:embed bun_client.tpl
+var BunClientTemplate []byte
+
 //go:embed redis_client.tpl
 var RedisClientTemplate []byte
 
 //go:embed ent_repo.tpl
 var EntRepoTemplate []byte
 
+//go:embed ent_view_repo.tpl
+var EntViewRepoTemplate []byte
+
 //go:embed gorm_repo.tpl
 var GormRepoTemplate []byte
 
+//go:embed bun_repo.tpl
+var BunRepoTemplate []byte
+
 //go:embed service.tpl
 var ServiceTemplate []byte
 
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"database/sql"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/schema"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/tx7do/kratos-bootstrap/bootstrap"
+
+	"{{.Module}}/app/{{lower .Service}}/service/internal/data/bun/models"
+)
+
+// NewBunClient 创建Bun ORM数据库客户端
+func NewBunClient(ctx *bootstrap.Context) (*bun.DB, func(), error) {
+	l := ctx.NewLoggerHelper("bun/data/{{lower .Service}}-service")
+
+	cfg := ctx.GetConfig()
+	if cfg == nil || cfg.Data == nil || cfg.Data.Database == nil {
+		l.Fatalf("[BUN] failed getting config")
+		return nil, func() {}, nil
+	}
+
+	driver := cfg.Data.Database.GetDriver()
+	var dialect schema.Dialect
+	switch driver {
+	case "postgres", "postgresql", "pgx":
+		driver, dialect = "pgx", pgdialect.New()
+	case "sqlite", "sqlite3":
+		driver, dialect = "sqlite3", sqlitedialect.New()
+	default:
+		dialect = mysqldialect.New()
+	}
+
+	sqlDB, err := sql.Open(driver, cfg.Data.Database.GetSource())
+	if err != nil {
+		l.Errorf("[BUN] failed opening connection to database: %v", err)
+		return nil, func() {}, err
+	}
+
+	db := bun.NewDB(sqlDB, dialect)
+
+	// 多对多关系的中间表模型需要先注册
+	db.RegisterModel(models.JoinModels()...)
+
+	return db, func() {
+		if err := db.Close(); err != nil {
+			l.Errorf("[BUN] failed closing database: %v", err)
+		}
+	}, nil
+}
@@ -0,0 +1,153 @@
+package data
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/tx7do/kratos-bootstrap/bootstrap"
+	"github.com/uptrace/bun"
+
+	"github.com/tx7do/go-utils/copierutil"
+	"github.com/tx7do/go-utils/mapper"
+
+	pagination "github.com/tx7do/go-crud/api/gen/go/pagination/v1"
+
+	"{{.Module}}/app/{{lower .Service}}/service/internal/data/bun/models"
+
+	{{.ApiPackage}} "{{.Module}}/api/gen/go/{{lower .Service}}/service/{{.ApiPackageVersion}}"
+)
+
+type {{.ClassName}} struct {
+	db  *bun.DB
+	log *log.Helper
+
+	mapper *mapper.CopierMapper[{{.ApiPackage}}.{{pascal .Model}}, models.{{pascal .Model}}]
+}
+
+func New{{.ClassName}}(ctx *bootstrap.Context, db *bun.DB) *{{.ClassName}} {
+	repo := &{{.ClassName}}{
+		db:     db,
+		log:    ctx.NewLoggerHelper("{{lower .Model}}/repo/{{lower .Service}}-service"),
+		mapper: mapper.NewCopierMapper[{{.ApiPackage}}.{{pascal .Model}}, models.{{pascal .Model}}](),
+	}
+
+	repo.init()
+
+	return repo
+}
+
+func (r *{{.ClassName}}) init() {
+	r.mapper.AppendConverters(copierutil.NewTimeStringConverterPair())
+	r.mapper.AppendConverters(copierutil.NewTimeTimestamppbConverterPair())
+{{- range .Fields.StringEnumFields}}
+	r.mapper.AppendConverters(copierutil.NewEnumTypeConverterPair[{{$.ApiPackage}}.{{pascal $.Model}}_{{.EnumName}}, string](
+		{{.EnumNameMap}},
+		{{.EnumValueMap}},
+	))
+{{- end}}
+}
+{{- with .Fields.SensitiveFields}}
+
+// hideSensitive 清除不在响应中返回的敏感字段
+func (r *{{$.ClassName}}) hideSensitive(dto *{{$.ApiPackage}}.{{pascal $.Model}}) *{{$.ApiPackage}}.{{pascal $.Model}} {
+	if dto != nil {
+{{- range .}}
+		dto.{{.PascalName}} = nil
+{{- end}}
+	}
+	return dto
+}
+{{- end}}
+
+func (r *{{.ClassName}}) List(ctx context.Context, req *pagination.PagingRequest) (*{{.ApiPackage}}.List{{pascal .Model}}Response, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	var entities []*models.{{pascal .Model}}
+	query := r.db.NewSelect().Model(&entities)
+	if !req.GetNoPaging() && req.GetPageSize() > 0 {
+		query = query.Limit(int(req.GetPageSize())).Offset(int((req.GetPage() - 1) * req.GetPageSize()))
+	}
+
+	total, err := query.ScanAndCount(ctx)
+	if err != nil {
+		r.log.Errorf("query {{lower .Model}} list failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorInternalServerError("query {{lower .Model}} list failed")
+	}
+
+	items := make([]*{{.ApiPackage}}.{{pascal .Model}}, 0, len(entities))
+	for _, entity := range entities {
+		items = append(items, {{if .Fields.SensitiveFields}}r.hideSensitive(r.mapper.ToDTO(entity)){{else}}r.mapper.ToDTO(entity){{end}})
+	}
+
+	return &{{.ApiPackage}}.List{{pascal .Model}}Response{
+		Total: uint32(total),
+		Items: items,
+	}, nil
+}
+
+func (r *{{.ClassName}}) Get(ctx context.Context, req *{{.ApiPackage}}.Get{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	entity := new(models.{{pascal .Model}})
+	if err := r.db.NewSelect().Model(entity).Where("id = ?", req.GetId()).Scan(ctx); err != nil {
+		r.log.Errorf("query {{lower .Model}} failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorNotFound("{{lower .Model}} not found")
+	}
+
+	return {{if .Fields.SensitiveFields}}r.hideSensitive(r.mapper.ToDTO(entity)){{else}}r.mapper.ToDTO(entity){{end}}, nil
+}
+{{- if not .ReadOnly}}
+
+func (r *{{.ClassName}}) Create(ctx context.Context, req *{{.ApiPackage}}.Create{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
+	if req == nil || req.Data == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	entity := r.mapper.ToEntity(req.Data)
+	if _, err := r.db.NewInsert().Model(entity).Returning("*").Exec(ctx); err != nil {
+		r.log.Errorf("insert {{lower .Model}} failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorInternalServerError("insert {{lower .Model}} failed")
+	}
+
+	return {{if .Fields.SensitiveFields}}r.hideSensitive(r.mapper.ToDTO(entity)){{else}}r.mapper.ToDTO(entity){{end}}, nil
+}
+
+func (r *{{.ClassName}}) Update(ctx context.Context, req *{{.ApiPackage}}.Update{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
+	if req == nil || req.Data == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	entity := r.mapper.ToEntity(req.Data)
+	query := r.db.NewUpdate().Model(entity).WherePK()
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		query = query.Column(paths...)
+	}
+	if _, err := query.Exec(ctx); err != nil {
+		r.log.Errorf("update {{lower .Model}} failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorInternalServerError("update {{lower .Model}} failed")
+	}
+
+	return r.Get(ctx, &{{.ApiPackage}}.Get{{pascal .Model}}Request{QueryBy: &{{.ApiPackage}}.Get{{pascal .Model}}Request_Id{Id: req.Data.GetId()}})
+}
+
+func (r *{{.ClassName}}) Delete(ctx context.Context, req *{{.ApiPackage}}.Delete{{pascal .Model}}Request) (bool, error) {
+	if req == nil {
+		return false, errors.New("request is nil")
+	}
+
+	result, err := r.db.NewDelete().Model((*models.{{pascal .Model}})(nil)).Where("id = ?", req.GetId()).Exec(ctx)
+	if err != nil {
+		r.log.Errorf("delete {{lower .Model}} failed: %s", err.Error())
+		return false, {{.ApiPackage}}.ErrorInternalServerError("delete {{lower .Model}} failed")
+	}
+
+	affected, _ := result.RowsAffected()
+
+	return affected > 0, nil
+}
+{{- end}}
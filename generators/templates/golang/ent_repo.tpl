@@ -59,7 +59,25 @@ func (r *{{.ClassName}}) init() {
 
 	r.mapper.AppendConverters(copierutil.NewTimeStringConverterPair())
 	r.mapper.AppendConverters(copierutil.NewTimeTimestamppbConverterPair())
+{{- range .Fields.EntEnumFields}}
+	r.mapper.AppendConverters(copierutil.NewEnumTypeConverterPair[{{$.ApiPackage}}.{{pascal $.Model}}_{{.EnumName}}, {{lower $.Model}}.{{.EntPascalName}}](
+		{{.EnumNameMap}},
+		{{.EnumValueMap}},
+	))
+{{- end}}
+}
+{{- with .Fields.SensitiveFields}}
+
+// hideSensitive 清除不在响应中返回的敏感字段
+func (r *{{$.ClassName}}) hideSensitive(dto *{{$.ApiPackage}}.{{pascal $.Model}}) *{{$.ApiPackage}}.{{pascal $.Model}} {
+	if dto != nil {
+{{- range .}}
+		dto.{{.PascalName}} = nil
+{{- end}}
+	}
+	return dto
 }
+{{- end}}
 
 func (r *{{.ClassName}}) Count(ctx context.Context, req *paginationV1.PagingRequest) (int, error) {
 	builder := r.entClient.Client().{{pascal .Model}}.Query()
@@ -93,6 +111,13 @@ func (r *{{.ClassName}}) List(ctx context.Context, req *pagination.PagingRequest
         return &{{.ApiPackage}}.List{{pascal .Model}}Response{Total: 0, Items: nil}, nil
     }
 
+{{- if .Fields.SensitiveFields}}
+
+    for _, item := range ret.Items {
+        r.hideSensitive(item)
+    }
+{{- end}}
+
     return &{{.ApiPackage}}.List{{pascal .Model}}Response{
         Total: ret.Total,
         Items: ret.Items,
@@ -128,8 +153,13 @@ func (r *{{.ClassName}}) Get(ctx context.Context, req *{{.ApiPackage}}.Get{{pasc
 	if err != nil {
 		return nil, err
 	}
+{{- if .Fields.SensitiveFields}}
+
+	return r.hideSensitive(dto), err
+{{- else}}
 
 	return dto, err
+{{- end}}
 }
 
 func (r *{{.ClassName}}) Create(ctx context.Context, req *{{.ApiPackage}}.Create{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
@@ -146,7 +176,7 @@ func (r *{{.ClassName}}) Create(ctx context.Context, req *{{.ApiPackage}}.Create
 		r.log.Errorf("insert one data failed: %s", err.Error())
 		return nil, {{.ApiPackage}}.ErrorInternalServerError("insert data failed")
 	} else {
-		return r.mapper.ToDTO(ret), nil
+		return {{if .Fields.SensitiveFields}}r.hideSensitive(r.mapper.ToDTO(ret)){{else}}r.mapper.ToDTO(ret){{end}}, nil
 	}
 }
 
@@ -0,0 +1,131 @@
+﻿package data
+
+import (
+	"context"
+{{- if .Fields.HasField "id"}}
+
+	"entgo.io/ent/dialect/sql"
+{{- end}}
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/tx7do/kratos-bootstrap/bootstrap"
+
+	"github.com/tx7do/go-utils/copierutil"
+	"github.com/tx7do/go-utils/mapper"
+
+	pagination "github.com/tx7do/go-crud/api/gen/go/pagination/v1"
+	entCrud "github.com/tx7do/go-crud/entgo"
+
+	"{{.Module}}/app/{{lower .Service}}/service/internal/data/ent"
+{{- if .Fields.HasField "id"}}
+	"{{.Module}}/app/{{lower .Service}}/service/internal/data/ent/{{lower (pascal .Model)}}"
+{{- end}}
+
+	{{.ApiPackage}} "{{.Module}}/api/gen/go/{{lower .Service}}/service/{{.ApiPackageVersion}}"
+)
+
+// {{.ClassName}} 只读仓库，ent 不为视图生成 Create、Update、Delete 构造器，只能查询
+type {{.ClassName}} struct {
+	entClient *entCrud.EntClient[*ent.Client]
+	log       *log.Helper
+
+	mapper *mapper.CopierMapper[{{.ApiPackage}}.{{pascal .Model}}, ent.{{pascal .Model}}]
+}
+
+func New{{.ClassName}}(ctx *bootstrap.Context, entClient *entCrud.EntClient[*ent.Client]) *{{.ClassName}} {
+	repo := &{{.ClassName}}{
+		log:       ctx.NewLoggerHelper("{{lower .Model}}/repo/{{lower .Service}}-service"),
+		entClient: entClient,
+		mapper:    mapper.NewCopierMapper[{{.ApiPackage}}.{{pascal .Model}}, ent.{{pascal .Model}}](),
+	}
+
+	repo.init()
+
+	return repo
+}
+
+func (r *{{.ClassName}}) init() {
+	r.mapper.AppendConverters(copierutil.NewTimeStringConverterPair())
+	r.mapper.AppendConverters(copierutil.NewTimeTimestamppbConverterPair())
+}
+{{- with .Fields.SensitiveFields}}
+
+// hideSensitive 清除不在响应中返回的敏感字段
+func (r *{{$.ClassName}}) hideSensitive(dto *{{$.ApiPackage}}.{{pascal $.Model}}) *{{$.ApiPackage}}.{{pascal $.Model}} {
+	if dto != nil {
+{{- range .}}
+		dto.{{.PascalName}} = nil
+{{- end}}
+	}
+	return dto
+}
+{{- end}}
+
+func (r *{{.ClassName}}) Count(ctx context.Context, _ *pagination.PagingRequest) (int, error) {
+	count, err := r.entClient.Client().{{pascal .Model}}.Query().Count(ctx)
+	if err != nil {
+		r.log.Errorf("query {{lower .Model}} count failed: %s", err.Error())
+		return 0, {{.ApiPackage}}.ErrorInternalServerError("query {{lower .Model}} count failed")
+	}
+
+	return count, nil
+}
+
+func (r *{{.ClassName}}) List(ctx context.Context, req *pagination.PagingRequest) (*{{.ApiPackage}}.List{{pascal .Model}}Response, error) {
+	if req == nil {
+		return nil, {{.ApiPackage}}.ErrorBadRequest("invalid parameter")
+	}
+
+	builder := r.entClient.Client().{{pascal .Model}}.Query()
+
+	total, err := builder.Clone().Count(ctx)
+	if err != nil {
+		r.log.Errorf("query {{lower .Model}} count failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorInternalServerError("query {{lower .Model}} count failed")
+	}
+
+	if !req.GetNoPaging() && req.GetPageSize() > 0 {
+		builder.Limit(int(req.GetPageSize())).Offset(int((req.GetPage() - 1) * req.GetPageSize()))
+	}
+
+	entities, err := builder.All(ctx)
+	if err != nil {
+		r.log.Errorf("query {{lower .Model}} list failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorInternalServerError("query {{lower .Model}} list failed")
+	}
+
+	items := make([]*{{.ApiPackage}}.{{pascal .Model}}, 0, len(entities))
+	for _, entity := range entities {
+		items = append(items, {{if .Fields.SensitiveFields}}r.hideSensitive(r.mapper.ToDTO(entity)){{else}}r.mapper.ToDTO(entity){{end}})
+	}
+
+	return &{{.ApiPackage}}.List{{pascal .Model}}Response{
+		Total: uint64(total),
+		Items: items,
+	}, nil
+}
+
+func (r *{{.ClassName}}) Get(ctx context.Context, req *{{.ApiPackage}}.Get{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
+	if req == nil {
+		return nil, {{.ApiPackage}}.ErrorBadRequest("invalid parameter")
+	}
+{{- if .Fields.HasField "id"}}
+
+	// 视图没有主键，按其 id 列查询
+	entity, err := r.entClient.Client().{{pascal .Model}}.Query().
+		Where(sql.FieldEQ({{lower (pascal .Model)}}.FieldID, req.GetId())).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, {{.ApiPackage}}.ErrorNotFound("{{lower .Model}} not found")
+		}
+		r.log.Errorf("query one {{lower .Model}} failed: %s", err.Error())
+		return nil, {{.ApiPackage}}.ErrorInternalServerError("query {{lower .Model}} failed")
+	}
+
+	return {{if .Fields.SensitiveFields}}r.hideSensitive(r.mapper.ToDTO(entity)){{else}}r.mapper.ToDTO(entity){{end}}, nil
+{{- else}}
+
+	// 视图没有 id 列，无法查询单条数据
+	return nil, {{.ApiPackage}}.ErrorBadRequest("{{lower .Model}} has no id")
+{{- end}}
+}
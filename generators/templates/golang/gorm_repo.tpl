@@ -47,7 +47,25 @@ func (r *{{.ClassName}}) init() {
 
 	r.mapper.AppendConverters(copierutil.NewTimeStringConverterPair())
 	r.mapper.AppendConverters(copierutil.NewTimeTimestamppbConverterPair())
+{{- range .Fields.StringEnumFields}}
+	r.mapper.AppendConverters(copierutil.NewEnumTypeConverterPair[{{$.ApiPackage}}.{{pascal $.Model}}_{{.EnumName}}, string](
+		{{.EnumNameMap}},
+		{{.EnumValueMap}},
+	))
+{{- end}}
 }
+{{- with .Fields.SensitiveFields}}
+
+// hideSensitive 清除不在响应中返回的敏感字段
+func (r *{{$.ClassName}}) hideSensitive(dto *{{$.ApiPackage}}.{{pascal $.Model}}) *{{$.ApiPackage}}.{{pascal $.Model}} {
+	if dto != nil {
+{{- range .}}
+		dto.{{.PascalName}} = nil
+{{- end}}
+	}
+	return dto
+}
+{{- end}}
 
 func (r *{{.ClassName}}) List(ctx context.Context, req *pagination.PagingRequest) (*{{.ApiPackage}}.List{{pascal .Model}}Response, error) {
 	if req == nil {
@@ -62,6 +80,13 @@ func (r *{{.ClassName}}) List(ctx context.Context, req *pagination.PagingRequest
 		return &{{.ApiPackage}}.List{{pascal .Model}}Response{Total: 0, Items: nil}, nil
 	}
 
+{{- if .Fields.SensitiveFields}}
+
+	for _, item := range ret.Items {
+		r.hideSensitive(item)
+	}
+{{- end}}
+
 	return &{{.ApiPackage}}.List{{pascal .Model}}Response{
 		Total: ret.Total,
 		Items: ret.Items,
@@ -85,9 +110,15 @@ func (r *{{.ClassName}}) Get(ctx context.Context, req *{{.ApiPackage}}.Get{{pasc
 	if err != nil {
 		return nil, err
 	}
+{{- if .Fields.SensitiveFields}}
+
+	return r.hideSensitive(dto), err
+{{- else}}
 
 	return dto, err
+{{- end}}
 }
+{{- if not .ReadOnly}}
 
 func (r *{{.ClassName}}) Create(ctx context.Context, req *{{.ApiPackage}}.Create{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
 	if req == nil || req.Data == nil {
@@ -95,8 +126,13 @@ func (r *{{.ClassName}}) Create(ctx context.Context, req *{{.ApiPackage}}.Create
 	}
 
 	result, err := r.repository.Create(ctx, r.gormClient, req.Data, nil)
+{{- if .Fields.SensitiveFields}}
+
+	return r.hideSensitive(result), err
+{{- else}}
 
 	return result, err
+{{- end}}
 }
 
 func (r *{{.ClassName}}) Update(ctx context.Context, req *{{.ApiPackage}}.Update{{pascal .Model}}Request) (*{{.ApiPackage}}.{{pascal .Model}}, error) {
@@ -138,3 +174,4 @@ func (r *{{.ClassName}}) Delete(ctx context.Context, req *{{.ApiPackage}}.Delete
 
 	return result > 0, err
 }
+{{- end}}
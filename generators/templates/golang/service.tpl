@@ -5,11 +5,13 @@ import (
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/tx7do/kratos-bootstrap/bootstrap"
-{{if not .IsGrpc}}
+{{if and (not .IsGrpc) (not .ReadOnly)}}
 	"github.com/tx7do/go-utils/trans"
 {{end}}
 	paginationV1 "github.com/tx7do/go-crud/api/gen/go/pagination/v1"
+{{- if not .ReadOnly}}
 	"google.golang.org/protobuf/types/known/emptypb"
+{{- end}}
 {{if .UseRepo}}
 	"{{.Module}}/app/{{.Service}}/service/internal/data"
 {{end}}
@@ -18,7 +20,7 @@ import (
 	{{.SourceApiPackage}} "{{.Module}}/api/gen/go/{{lower .SourceApiPackageName}}/service/{{lower .SourceApiPackageVersion}}"
 {{end -}}
 
-{{if not .IsGrpc}}
+{{if and (not .IsGrpc) (not .ReadOnly)}}
 	"{{.Module}}/pkg/middleware/auth"
 {{- end}}
 )
@@ -48,6 +50,7 @@ func (s *{{.ClassName}}) List(ctx context.Context, req *paginationV1.PagingReque
 func (s *{{.ClassName}}) Get(ctx context.Context, req *{{.SourceApiPackage}}.Get{{pascal .Model}}Request) (*{{.SourceApiPackage}}.{{pascal .Model}}, error) {
 	return s.{{.DataSourceVar}}.Get(ctx, req)
 }
+{{- if not .ReadOnly}}
 
 func (s *{{.ClassName}}) Create(ctx context.Context, req *{{.SourceApiPackage}}.Create{{pascal .Model}}Request) (*{{.SourceApiPackage}}.{{pascal .Model}}, error) {
 	if req == nil || req.Data == nil {
@@ -114,3 +117,4 @@ func (s *{{.ClassName}}) Delete(ctx context.Context, req *{{.SourceApiPackage}}.
     return s.{{.DataSourceVar}}.Delete(ctx, req)
 {{end -}}
 }
+{{- end}}
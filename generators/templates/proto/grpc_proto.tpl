@@ -4,8 +4,8 @@ package {{.Package}};
 
 import "gnostic/openapi/v3/annotations.proto";
 
-import "google/protobuf/empty.proto";
-import "google/protobuf/timestamp.proto";
+{{if not .ReadOnly}}import "google/protobuf/empty.proto";
+{{end}}import "google/protobuf/timestamp.proto";
 import "google/protobuf/field_mask.proto";
 
 import "pagination/v1/pagination.proto";
@@ -20,6 +20,7 @@ service {{pascal .Model}}Service {
 
   // 查询{{.ModelName}}详情
   rpc Get (Get{{pascal .Model}}Request) returns ({{pascal .Model}}) {}
+{{- if not .ReadOnly}}
 
   // 创建{{.ModelName}}
   rpc Create (Create{{pascal .Model}}Request) returns ({{pascal .Model}}) {}
@@ -32,13 +33,20 @@ service {{pascal .Model}}Service {
 
   // 批量创建{{.ModelName}}
   rpc BatchCreate (BatchCreate{{pascal .Model}}Request) returns (BatchCreate{{pascal .Model}}Response) {}
+{{- end}}
 }
 
 // {{.ModelName}}
 message {{pascal .Model}} {
-{{range .Fields}}  optional {{.Type}} {{snake .Name}} = {{.Number}} [
-    json_name = "{{camel .Name}}",
-    (gnostic.openapi.v3.property) = {description: "{{.Comment}}"}
+{{range .Fields}}{{if .EnumName}}{{if .Comment}}  // {{.Comment}}
+{{end}}  enum {{.EnumName}} {
+{{range .EnumValues}}    {{.Name}} = {{.Number}};
+{{end}}  }
+
+{{end}}{{end -}}
+{{range .Fields}}  optional {{.FieldType}} {{snake .Name}} = {{.Number}} [
+    json_name = "{{.JSONName}}",
+    (gnostic.openapi.v3.property) = {description: "{{.Comment}}"{{.PropertyOptions}}}
   ]; // {{.Comment}}
 
 {{end -}}
@@ -64,6 +72,7 @@ message Get{{pascal .Model}}Request {
     }
   ]; // 视图字段过滤器，用于控制返回的字段
 }
+{{- if not .ReadOnly}}
 
 message Create{{pascal .Model}}Request {
   {{pascal .Model}} data = 1;
@@ -103,6 +112,7 @@ message BatchCreate{{pascal .Model}}Request {
 message BatchCreate{{pascal .Model}}Response {
   repeated {{pascal .Model}} data = 1;
 }
+{{- end}}
 
 message Count{{pascal .Model}}Response {
   uint64 count = 1;
@@ -3,8 +3,8 @@
 package {{.TargetPackage}};
 
 import "gnostic/openapi/v3/annotations.proto";
-import "google/protobuf/empty.proto";
-import "google/api/annotations.proto";
+{{if not .ReadOnly}}import "google/protobuf/empty.proto";
+{{end}}import "google/api/annotations.proto";
 import "pagination/v1/pagination.proto";
 
 import "{{.SourceProto}}";
@@ -24,6 +24,7 @@ service {{pascal .Model}}Service {
         get: "{{.Path}}/{id}"
     };
   }
+{{- if not .ReadOnly}}
 
   // 创建{{.ModelName}}
   rpc Create ({{.SourcePackage}}.Create{{pascal .Model}}Request) returns ({{.SourcePackage}}.{{pascal .Model}}) {
@@ -55,4 +56,5 @@ service {{pascal .Model}}Service {
         body: "*"
     };
   }
+{{- end}}
 }
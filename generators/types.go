@@ -2,28 +2,185 @@ package generators
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/tx7do/go-utils/stringcase"
+
+	"github.com/tx7do/go-wind-toolkit/generators/annotation"
 )
 
 // ProtoField 字段数据
 type ProtoField struct {
-	Name    string // 字段名
-	Type    string // 字段类型
-	Null    bool   // 是否允许为 NULL
-	Comment string // 字段注释
-	Number  int    // 字段编号
+	Name        string                 // 字段名
+	Type        string                 // 字段类型
+	Null        bool                   // 是否允许为 NULL
+	Comment     string                 // 字段注释，不含注解
+	Number      int                    // 字段编号
+	Annotations annotation.Annotations // 字段注释中的注解
+}
+
+// ProtoEnumValue 枚举值
+type ProtoEnumValue struct {
+	Name   string // 枚举值名
+	Number int64  // 枚举值编号
+}
+
+// JSONName 返回字段的 JSON 名称，@json 注解优先
+func (f ProtoField) JSONName() string {
+	if f.Annotations.JSON != "" {
+		return f.Annotations.JSON
+	}
+	return stringcase.LowerCamelCase(f.Name)
+}
+
+// EnumName 返回 @enum 注解生成的枚举类型名，没有 @enum 注解时返回空
+func (f ProtoField) EnumName() string {
+	if len(f.Annotations.Enum) == 0 {
+		return ""
+	}
+	return stringcase.ToPascalCase(f.Name)
+}
+
+// FieldType 返回字段的 proto 类型，@enum 注解的字段为其枚举类型
+func (f ProtoField) FieldType() string {
+	if name := f.EnumName(); name != "" {
+		return name
+	}
+	return f.Type
+}
+
+// EnumValues 返回枚举值，值名以字段名为前缀。proto3 要求第一个值为 0，缺少时补充 UNSPECIFIED
+func (f ProtoField) EnumValues() []ProtoEnumValue {
+	prefix := strings.ToUpper(stringcase.SnakeCase(f.Name)) + "_"
+	values := []ProtoEnumValue{{Name: prefix + "UNSPECIFIED"}}
+	for _, v := range f.Annotations.Enum {
+		name := strings.ToUpper(stringcase.SnakeCase(v.Name))
+		if !strings.HasPrefix(name, prefix) {
+			name = prefix + name
+		}
+		if v.Number == 0 {
+			values[0].Name = name
+			continue
+		}
+		values = append(values, ProtoEnumValue{Name: name, Number: v.Number})
+	}
+	return values
+}
+
+// PropertyOptions 返回注解生成的 OpenAPI 属性选项，敏感字段只写，不在响应中返回
+func (f ProtoField) PropertyOptions() string {
+	var b strings.Builder
+	a := f.Annotations
+	if a.Sensitive {
+		b.WriteString(", write_only: true")
+	}
+	if a.Min != "" {
+		b.WriteString(", minimum: " + a.Min)
+	}
+	if a.Max != "" {
+		b.WriteString(", maximum: " + a.Max)
+	}
+	if a.MinLen > 0 {
+		fmt.Fprintf(&b, ", min_length: %d", a.MinLen)
+	}
+	if a.MaxLen > 0 {
+		fmt.Fprintf(&b, ", max_length: %d", a.MaxLen)
+	}
+	if a.Pattern != "" {
+		b.WriteString(`, pattern: "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(a.Pattern) + `"`)
+	}
+	return b.String()
 }
 
 // DataField 数据库字段定义
 type DataField struct {
-	Name    string // 字段名
-	Type    string // 字段类型
-	Comment string // 字段注释
+	Name         string                 // 字段名
+	Type         string                 // 字段类型
+	Comment      string                 // 字段注释
+	Sensitive    bool                   // 是否为敏感字段，不在响应中返回
+	Enum         []annotation.EnumValue // @enum 注解的枚举值
+	EnumNumbered bool                   // 枚举值是否以编号存储，例如 @enum(1:ACTIVE)
 }
 
 type DataFieldArray []DataField
 
+// EntEnumFields 返回 ent 中为枚举类型的 @enum 字段：字符串列，以及以编号存储的整数列。
+// ent 枚举为字符串类型，reflect 无法与 proto 的 int32 枚举互相转换，需要注册枚举转换器
+func (a DataFieldArray) EntEnumFields() DataFieldArray {
+	var fields DataFieldArray
+	for _, f := range a {
+		if len(f.Enum) > 0 && (f.Type == "string" || f.EnumNumbered) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// StringEnumFields 返回字符串列上的 @enum 字段，gorm 与 bun 模型中为 string，需要注册枚举转换器
+func (a DataFieldArray) StringEnumFields() DataFieldArray {
+	var fields DataFieldArray
+	for _, f := range a {
+		if len(f.Enum) > 0 && f.Type == "string" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// SensitiveFields 返回敏感字段
+func (a DataFieldArray) SensitiveFields() DataFieldArray {
+	var fields DataFieldArray
+	for _, f := range a {
+		if f.Sensitive {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// HasField 判断是否存在指定名称的字段
+func (a DataFieldArray) HasField(name string) bool {
+	for _, f := range a {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumName 返回 @enum 注解生成的 proto 枚举类型名，与 ProtoField.EnumName 一致
+func (f DataField) EnumName() string {
+	return ProtoField{Name: f.Name, Annotations: annotation.Annotations{Enum: f.Enum}}.EnumName()
+}
+
+// EnumNameMap 返回枚举编号到数据库中存储值的 map 字面量，带编号的枚举存储编号，否则存储值名
+func (f DataField) EnumNameMap() string {
+	items := make([]string, 0, len(f.Enum))
+	for _, v := range f.Enum {
+		items = append(items, fmt.Sprintf("%d: %s", v.Number, strconv.Quote(f.enumValue(v))))
+	}
+	return "map[int32]string{" + strings.Join(items, ", ") + "}"
+}
+
+// EnumValueMap 返回数据库中存储值到枚举编号的 map 字面量
+func (f DataField) EnumValueMap() string {
+	items := make([]string, 0, len(f.Enum))
+	for _, v := range f.Enum {
+		items = append(items, fmt.Sprintf("%s: %d", strconv.Quote(f.enumValue(v)), v.Number))
+	}
+	return "map[string]int32{" + strings.Join(items, ", ") + "}"
+}
+
+// enumValue 返回枚举值在数据库中存储的值
+func (f DataField) enumValue(v annotation.EnumValue) string {
+	if f.EnumNumbered {
+		return strconv.FormatInt(v.Number, 10)
+	}
+	return v.Name
+}
+
 func (f DataField) CamelName() string {
 	return stringcase.LowerCamelCase(f.Name)
 }
@@ -161,9 +161,9 @@ func (a *App) ImportDatabaseTables(cfg database.DBConfig) string {
 	//runtime.LogInfof(a.ctx, "[%v] 导入的表名列表: [%v]", cfg.Type, tables)
 
 	a.generator.CleanOptions()
-	for _, t := range tables {
+	for _, tableName := range database.TableNames(tables) {
 		opt := &generator.Option{
-			TableName: t.Name,
+			TableName: tableName,
 		}
 		a.generator.AddOption(opt)
 	}
@@ -93,6 +93,7 @@ export namespace database {
 	    password: string;
 	    ssl: boolean;
 	    dbPath: string;
+	    schemas?: string[];
 	    useDSN?: boolean;
 	    dsn?: string;
 	    sqlContent?: string;
@@ -113,6 +114,7 @@ export namespace database {
 	        this.password = source["password"];
 	        this.ssl = source["ssl"];
 	        this.dbPath = source["dbPath"];
+	        this.schemas = source["schemas"];
 	        this.useDSN = source["useDSN"];
 	        this.dsn = source["dsn"];
 	        this.sqlContent = source["sqlContent"];
@@ -122,6 +124,7 @@ export namespace database {
 	}
 	
 	export class TableInfo {
+	    table_schema: string;
 	    table_name: string;
 	    table_type: string;
 	    table_engine: string;
@@ -137,6 +140,7 @@ export namespace database {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table_schema = source["table_schema"];
 	        this.table_name = source["table_name"];
 	        this.table_type = source["table_type"];
 	        this.table_engine = source["table_engine"];
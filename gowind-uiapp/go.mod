@@ -16,6 +16,7 @@ require (
 	github.com/sijms/go-ora/v2 v2.9.0
 	github.com/tx7do/go-utils/ddl_parser v0.0.5
 	github.com/tx7do/go-wind-toolkit/sql-kratos v0.1.0
+	github.com/tx7do/go-wind-toolkit/sql-orm v0.1.0
 	github.com/wailsapp/wails/v2 v2.12.0
 	modernc.org/sqlite v1.49.1
 )
@@ -83,7 +84,6 @@ require (
 	github.com/tx7do/go-utils v1.1.37 // indirect
 	github.com/tx7do/go-utils/code_generator v0.0.1 // indirect
 	github.com/tx7do/go-wind-toolkit/generators v0.1.0 // indirect
-	github.com/tx7do/go-wind-toolkit/sql-orm v0.1.0 // indirect
 	github.com/tx7do/go-wind-toolkit/sql-proto v0.1.0 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasttemplate v1.2.2 // indirect
@@ -149,8 +149,13 @@ func generateConnKey(cfg DBConfig) string {
 		return fmt.Sprintf("%s://custom-dsn", cfg.Type)
 	}
 	// 否则基于配置字段生成键
-	return fmt.Sprintf("%s://%s@%s:%d/%s",
+	key := fmt.Sprintf("%s://%s@%s:%d/%s",
 		cfg.Type, cfg.Username, cfg.Host, cfg.Port, cfg.Database)
+	// search_path 不同的连接不能复用
+	if path := searchPath(cfg.Schemas); path != "" {
+		key += "?search_path=" + path
+	}
+	return key
 }
 
 // 构建 DSN（Data Source Name）
@@ -171,8 +176,8 @@ func buildDSN(cfg DBConfig) (string, error) {
 		if cfg.SSL {
 			sslMode = "require"
 		}
-		return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s&timezone=Asia/Shanghai",
-			cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database, sslMode), nil
+		return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s&timezone=Asia/Shanghai%s",
+			cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database, sslMode, searchPathParam(cfg.Schemas)), nil
 
 	case DbTypeSQLite:
 		if cfg.DBPath == "" {
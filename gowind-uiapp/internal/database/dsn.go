@@ -88,16 +88,36 @@ func buildPostgresDSN(cfg DBConfig) (string, error) {
 		sslMode = "require"
 	}
 
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s&timezone=Asia/Shanghai",
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s&timezone=Asia/Shanghai%s",
 		url.QueryEscape(cfg.Username),
 		url.QueryEscape(cfg.Password), // URL 编码避免特殊字符问题
 		host,
 		port,
 		cfg.Database,
 		sslMode,
+		searchPathParam(cfg.Schemas),
 	), nil
 }
 
+// searchPath 返回模式列表对应的 search_path，例如 "auth,billing"，未指定模式时返回空字符串
+func searchPath(schemas []string) string {
+	var names []string
+	for _, s := range schemas {
+		if s = strings.TrimSpace(s); s != "" {
+			names = append(names, s)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// searchPathParam 返回模式列表对应的 search_path 参数，未指定模式时返回空字符串
+func searchPathParam(schemas []string) string {
+	if path := searchPath(schemas); path != "" {
+		return "&search_path=" + url.QueryEscape(path)
+	}
+	return ""
+}
+
 // SQLite DSN
 func buildSQLiteDSN(cfg DBConfig) (string, error) {
 	if cfg.DBPath == "" {
@@ -7,13 +7,14 @@ import (
 	"strings"
 )
 
-// GetTables 获取数据库表列表
+// GetTables 获取数据库表和视图列表，视图的类型为 VIEW
 func GetTables(conn *DBConnection, dbType DbType) ([]TableInfo, error) {
 	var query string
 	switch dbType {
 	case DbTypeMySQL:
 		query = `
 			SELECT 
+				t.TABLE_SCHEMA AS table_schema,
 				t.TABLE_NAME AS table_name,
 				t.TABLE_TYPE AS table_type,
 				t.ENGINE AS table_engine,
@@ -31,6 +32,7 @@ func GetTables(conn *DBConnection, dbType DbType) ([]TableInfo, error) {
 	case DbTypePostgreSQL:
 		query = `
 			SELECT 
+				t.table_schema AS table_schema,
 				t.table_name AS table_name,
 				t.table_type AS table_type,
 				NULL AS table_engine,
@@ -38,19 +40,21 @@ func GetTables(conn *DBConnection, dbType DbType) ([]TableInfo, error) {
 				NULL AS create_time,
 				obj_description(c.oid) AS table_comment,
 				(SELECT COUNT(*) FROM information_schema.columns 
-				 WHERE table_name = t.table_name AND table_schema = 'public') AS table_columns,
+				 WHERE table_name = t.table_name AND table_schema = t.table_schema) AS table_columns,
 				(SELECT COUNT(*) FROM pg_indexes 
-				 WHERE tablename = t.table_name AND schemaname = 'public') AS table_indexes
+				 WHERE tablename = t.table_name AND schemaname = t.table_schema) AS table_indexes
 			FROM information_schema.tables t
-			LEFT JOIN pg_class c ON c.relname = t.table_name
-			WHERE t.table_schema = 'public' AND t.table_type = 'BASE TABLE'
-			ORDER BY t.table_name
+			LEFT JOIN pg_namespace n ON n.nspname = t.table_schema
+			LEFT JOIN pg_class c ON c.relname = t.table_name AND c.relnamespace = n.oid
+			WHERE t.table_schema = ANY(current_schemas(false)) AND t.table_type IN ('BASE TABLE', 'VIEW')
+			ORDER BY t.table_schema, t.table_name
 		`
 	case DbTypeSQLite:
 		query = `
 			SELECT 
+				'main' AS table_schema,
 				name AS table_name,
-				'table' AS table_type,
+				CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END AS table_type,
 				NULL AS table_engine,
 				0 AS table_rows,
 				NULL AS create_time,
@@ -58,12 +62,13 @@ func GetTables(conn *DBConnection, dbType DbType) ([]TableInfo, error) {
 				(SELECT COUNT(*) FROM pragma_table_info(name)) AS table_columns,
 				(SELECT COUNT(*) FROM pragma_index_list(name)) AS table_indexes
 			FROM sqlite_master 
-			WHERE type='table' AND name NOT LIKE 'sqlite_%'
+			WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
 			ORDER BY name
 		`
 	case DbTypeOracle:
 		query = `
 			SELECT 
+				USER AS table_schema,
 				t.table_name AS table_name,
 				'BASE TABLE' AS table_type,
 				NULL AS table_engine,
@@ -96,6 +101,7 @@ func GetTables(conn *DBConnection, dbType DbType) ([]TableInfo, error) {
 		var tableComment sql.NullString
 
 		err = rows.Scan(
+			&table.Schema,
 			&table.Name,
 			&table.Type,
 			&tableEngine,
@@ -133,6 +139,26 @@ func GetTables(conn *DBConnection, dbType DbType) ([]TableInfo, error) {
 	return tables, rows.Err()
 }
 
+// TableNames 返回表名列表，表分布在多个模式中时使用带模式名的表名，例如 "auth.users"
+func TableNames(tables []TableInfo) []string {
+	multiSchema := false
+	for _, t := range tables {
+		if t.Schema != tables[0].Schema {
+			multiSchema = true
+			break
+		}
+	}
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		if multiSchema {
+			names = append(names, t.Schema+"."+t.Name)
+		} else {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
 // GetColumns 获取表的列信息
 func GetColumns(conn *DBConnection, dbType DbType, tableName string) ([]ColumnInfo, error) {
 	var query string
@@ -27,6 +27,9 @@ type DBConfig struct {
 	// SQLite 特有
 	DBPath string `json:"dbPath"` // SQLite 文件路径
 
+	// PostgreSQL 特有
+	Schemas []string `json:"schemas,omitempty"` // 模式列表，作为 search_path（默认 public）
+
 	// 高级模式
 	UseDSN bool   `json:"useDSN,omitempty"` // 是否使用自定义 DSN
 	DSN    string `json:"dsn,omitempty"`    // 自定义 DSN（仅当 UseDSN=true 时生效）
@@ -70,6 +73,7 @@ func (e *DBError) Error() string {
 
 // TableInfo 表元数据
 type TableInfo struct {
+	Schema     string `json:"table_schema"` // 所属模式（数据库）
 	Name       string `json:"table_name"`
 	Type       string `json:"table_type"`    // BASE TABLE, VIEW...
 	Engine     string `json:"table_engine"`  // InnoDB, MyISAM...
@@ -64,7 +64,8 @@ func getTableCount(conn *DBConnection, dbType DbType) (int, error) {
 	case DbTypeMySQL:
 		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE()"
 	case DbTypePostgreSQL:
-		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = 'public'"
+		// 统计 search_path 中所有模式的表
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = ANY(current_schemas(false))"
 	case DbTypeSQLite:
 		query = "SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%'"
 	case DbTypeOracle:
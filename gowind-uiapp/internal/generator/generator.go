@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/gommon/log"
 	sqlkratos "github.com/tx7do/go-wind-toolkit/sql-kratos"
+	sqlorm "github.com/tx7do/go-wind-toolkit/sql-orm"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"github.com/tx7do/go-wind-toolkit/gowind-uiapp/internal/database"
@@ -159,10 +160,17 @@ func (g *Generator) GenerateGrpcCode(
 			options.IncludedTables = append(options.IncludedTables, opt.TableName)
 		}
 
+		// 无法导入的表和列不会中断生成，在报告中列出
+		options.Report = &sqlorm.Report{}
+
 		if err := sqlkratos.Generate(ctx, options); err != nil {
 			runtime.LogErrorf(ctx, "生成代码失败: %v", err)
 			return err
 		}
+
+		for _, d := range options.Report.Diagnostics {
+			runtime.LogWarningf(ctx, "ORM 代码导入问题: %s", d)
+		}
 	}
 
 	return nil
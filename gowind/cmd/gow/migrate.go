@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/tx7do/go-wind-toolkit/gowind/internal/migrate"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate <service> <name>",
+	Short: "Generate versioned SQL migrations from the ent schema",
+	Long: `Diff the ent schema of the service against its migration directory and write the changes as a new
+versioned migration, keeping the atlas.sum integrity file current.
+Usage example: gow migrate admin add_user_email
+The schema is read from app/<service>/service/internal/data/ent/schema and the migrations are written to
+app/<service>/service/internal/data/ent/migrate/migrations.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: migrate.RunDiff,
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrate.Format, "format", "", "format of the migrations (atlas, golang-migrate, goose)")
+	migrateCmd.Flags().StringVar(&migrate.DevURL, "dev-url", "", "clean dev database computing the migrations (default in-memory SQLite)")
+	migrateCmd.Flags().StringVar(&migrate.Baseline, "baseline", "", "DDL file of the schema the migrations start from")
+
+	rootCmd.AddCommand(migrateCmd)
+}
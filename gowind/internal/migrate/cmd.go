@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tx7do/go-wind-toolkit/gowind/internal/pkg"
+)
+
+const sql2ormPackage = "github.com/tx7do/go-wind-toolkit/sql-orm/cmd/sql2orm@latest"
+
+var (
+	// Format 迁移文件格式：atlas、golang-migrate、goose
+	Format string
+	// DevURL 用于计算差异的开发数据库
+	DevURL string
+	// Baseline 迁移目录起始的 DDL 基线文件
+	Baseline string
+)
+
+// RunDiff 用于 cobra 的 RunE：校验参数并为服务的 ent schema 生成版本化迁移文件。
+func RunDiff(cmd *cobra.Command, args []string) error {
+	cmdArgs, _ := pkg.SplitArgs(cmd, args)
+	if len(cmdArgs) < 2 {
+		_ = cmd.Help()
+		return fmt.Errorf("usage: migrate <service> <name>")
+	}
+
+	serviceName := strings.TrimSpace(cmdArgs[0])
+	name := strings.TrimSpace(cmdArgs[1])
+	if serviceName == "" || name == "" {
+		_ = cmd.Help()
+		return fmt.Errorf("service name and migration name are required")
+	}
+
+	inspector, err := pkg.NewModuleInspectorFromGo("")
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "\033[31mERROR: %s\033[m\n", err.Error())
+		return err
+	}
+
+	var valid bool
+	valid, err = pkg.IsValidServiceName(inspector.Root, serviceName)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "\033[31mERROR: %s\033[m\n", err.Error())
+		return err
+	}
+	if !valid {
+		err = fmt.Errorf("service '%s' does not exist or is not valid (missing cmd/server or configs)", serviceName)
+		_, _ = fmt.Fprintf(os.Stderr, "\033[31mERROR: %s\033[m\n", err.Error())
+		return err
+	}
+
+	servicePath := filepath.Join(inspector.Root, "app", serviceName, "service")
+	if err = migrateDiff(cmd.Context(), servicePath, name); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "\033[31mERROR: migrate for service %s failed: %v\033[m\n", serviceName, err)
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "\033[32mSUCCESS: migration '%s' generated for service '%s'\033[m\n", name, serviceName)
+	return nil
+}
+
+// migrateDiff 在服务目录执行 `sql2orm migrate diff`，将 internal/data/ent/schema 的变更写入
+// internal/data/ent/migrate/migrations。优先使用全局安装的 sql2orm，未找到则回退到 go run。
+func migrateDiff(ctx context.Context, serviceRootPath, name string) error {
+	schemaPath := filepath.Join("internal", "data", "ent", "schema")
+	if _, err := os.Stat(filepath.Join(serviceRootPath, schemaPath)); err != nil {
+		return fmt.Errorf("ent schema not found: %w", err)
+	}
+
+	args := []string{
+		"migrate", "diff", name,
+		"--schema", schemaPath,
+		"--dir", filepath.Join("internal", "data", "ent", "migrate", "migrations"),
+	}
+	if Format != "" {
+		args = append(args, "--format", Format)
+	}
+	if DevURL != "" {
+		args = append(args, "--dev-url", DevURL)
+	}
+	if Baseline != "" {
+		baseline, err := filepath.Abs(Baseline)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--baseline", baseline)
+	}
+
+	// 尝试查找全局 sql2orm 可执行文件
+	sql2ormPath, lookErr := exec.LookPath("sql2orm")
+
+	var cmd *exec.Cmd
+	if lookErr == nil {
+		cmd = exec.CommandContext(ctx, sql2ormPath, args...)
+	} else {
+		// 回退到使用 go run 运行 sql2orm 命令
+		cmd = exec.CommandContext(ctx, "go", append([]string{"run", sql2ormPackage}, args...)...)
+	}
+
+	// ent schema 需要在项目模块内加载
+	cmd.Dir = serviceRootPath
+	cmd.Env = os.Environ()
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if runErr := cmd.Run(); runErr != nil {
+		if lookErr == nil {
+			return fmt.Errorf("failed to run global sql2orm (%s): %w", sql2ormPath, runErr)
+		}
+		return fmt.Errorf("failed to run fallback 'go run %s': %w", sql2ormPackage, runErr)
+	}
+	return nil
+}
@@ -6,8 +6,8 @@ import (
 	"log"
 	"path"
 
-	"github.com/jinzhu/inflection"
 	"github.com/tx7do/go-wind-toolkit/generators"
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
 
 	sqlorm "github.com/tx7do/go-wind-toolkit/sql-orm"
 	sqlproto "github.com/tx7do/go-wind-toolkit/sql-proto"
@@ -39,19 +39,32 @@ func (g *Generator) Generate(ctx context.Context, opts GeneratorOptions) error {
 
 	var tables sqlproto.TableDataArray
 
+	// 命名规则，保证 Protobuf、ORM 和 data 层代码的命名一致
+	names, err := naming.Load(opts.NamingFile)
+	if err != nil {
+		return err
+	}
+
 	// 生成 Protobuf schema
-	if tables, err = g.generateProtobufCode(ctx, opts); err != nil {
+	if tables, err = g.generateProtobufCode(ctx, opts, names); err != nil {
 		return err
 	}
 
 	services := make([]string, 0)
 	servicePackageMap := make(map[string]string)
+	serviceTables := make(map[string]string)
 	for _, table := range tables {
 		if len(table.Fields) == 0 {
 			continue
 		}
 
-		name := inflection.Singular(table.Name)
+		name := names.Name(table.QualifiedName())
+
+		// 不同模式的同名表生成同名的服务和仓库，后者会覆盖前者
+		if other, ok := serviceTables[name]; ok {
+			return fmt.Errorf("sql2kratos: tables %v and %v are both imported as %v, rename one of them with the type_names of the naming rules", other, table.QualifiedName(), name)
+		}
+		serviceTables[name] = table.QualifiedName()
 
 		services = append(services, name)
 		servicePackageMap[name] = opts.ModuleName
@@ -84,6 +97,7 @@ func (g *Generator) Generate(ctx context.Context, opts GeneratorOptions) error {
 			opts.ModuleName, opts.ModuleVersion,
 			tables,
 			services,
+			names,
 		); err != nil {
 			return err
 		}
@@ -102,6 +116,7 @@ func (g *Generator) Generate(ctx context.Context, opts GeneratorOptions) error {
 			opts.UseRepo, useGrpc,
 			tables,
 			services,
+			names,
 		); err != nil {
 			return err
 		}
@@ -140,28 +155,49 @@ func (g *Generator) Generate(ctx context.Context, opts GeneratorOptions) error {
 }
 
 // generateProtobufCode generates the Protobuf code from the database schema.
-func (g *Generator) generateProtobufCode(ctx context.Context, opts GeneratorOptions) (sqlproto.TableDataArray, error) {
-	var err error
-	var tables sqlproto.TableDataArray
+func (g *Generator) generateProtobufCode(ctx context.Context, opts GeneratorOptions, names *naming.Strategy) (sqlproto.TableDataArray, error) {
+	var servers []string
+	for _, server := range opts.Servers {
+		if server == "grpc" || server == "rest" {
+			servers = append(servers, server)
+		}
+	}
+	if len(servers) == 0 {
+		return nil, nil
+	}
 
 	protoPath := path.Join(opts.OutputPath, "/api/protos/")
 
-	for _, server := range opts.Servers {
-		if server != "grpc" && server != "rest" {
-			continue
-		}
+	tables, err := sqlproto.Convert(
+		ctx,
+		&opts.Source,
+		&protoPath,
+		&opts.ModuleName,
+		&opts.SourceModuleName,
+		&opts.ModuleVersion,
+		&servers[0],
+		opts.IncludedTables,
+		opts.ExcludedTables,
+		false,
+		sqlproto.WithNaming(names),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tables = skipViews(opts, tables, names)
 
-		if tables, err = sqlproto.Convert(
-			ctx,
-			&opts.Source,
-			&protoPath,
-			&opts.ModuleName,
-			&opts.SourceModuleName,
-			&opts.ModuleVersion,
-			&server,
-			opts.IncludedTables,
-			opts.ExcludedTables,
-			opts.GenerateProto,
+	if !opts.GenerateProto {
+		return tables, nil
+	}
+
+	for _, server := range servers {
+		if err = sqlproto.WriteServicesProto(
+			protoPath,
+			server,
+			opts.ModuleName, opts.SourceModuleName, opts.ModuleVersion,
+			tables,
+			sqlproto.WithNaming(names),
 		); err != nil {
 			return nil, err
 		}
@@ -170,6 +206,36 @@ func (g *Generator) generateProtobufCode(ctx context.Context, opts GeneratorOpti
 	return tables, nil
 }
 
+// skipViews leaves out the views when the ORM generates the models of the tables only, as gorm and bun
+// do, so that no service or repository is generated without a model behind it. The skipped views are
+// logged, and added to the report if any.
+func skipViews(opts GeneratorOptions, tables sqlproto.TableDataArray, names *naming.Strategy) sqlproto.TableDataArray {
+	if opts.OrmType != "gorm" && opts.OrmType != "bun" {
+		return tables
+	}
+
+	kept := tables[:0:0]
+	for _, table := range tables {
+		if !table.View {
+			kept = append(kept, table)
+			continue
+		}
+
+		name := names.Name(table.QualifiedName())
+		msg := fmt.Sprintf("%s generates no model for views, the view is skipped", opts.OrmType)
+		log.Printf("Skipping view %s: %s", name, msg)
+		if opts.Report != nil {
+			opts.Report.Diagnostics = append(opts.Report.Diagnostics, sqlorm.Diagnostic{
+				Severity: sqlorm.SeveritySkipped,
+				Table:    table.QualifiedName(),
+				Message:  msg,
+			})
+		}
+	}
+
+	return kept
+}
+
 // generateOrmCode generates the ORM code based on the specified ORM type.
 func (g *Generator) generateOrmCode(
 	ctx context.Context,
@@ -188,6 +254,8 @@ func (g *Generator) generateOrmCode(
 	case "gorm":
 		schemaPath = path.Join(serviceRootPath, "/data/gorm/schema")
 		daoPath = path.Join(serviceRootPath, "/data/gorm/dao")
+	case "bun":
+		schemaPath = path.Join(serviceRootPath, "/data/bun/models")
 	}
 
 	if err = sqlorm.Importer(
@@ -199,6 +267,9 @@ func (g *Generator) generateOrmCode(
 		&daoPath,
 		opts.IncludedTables,
 		opts.ExcludedTables,
+		sqlorm.WithNamingFile(opts.NamingFile),
+		sqlorm.WithStrict(opts.Strict),
+		sqlorm.WithReport(opts.Report),
 	); err != nil {
 		return err
 	}
@@ -235,6 +306,7 @@ func (g *Generator) generateServicePackageCode(
 	userRepo, isGrpcService bool,
 	tables sqlproto.TableDataArray,
 	services []string,
+	names *naming.Strategy,
 ) error {
 
 	for _, table := range tables {
@@ -242,14 +314,14 @@ func (g *Generator) generateServicePackageCode(
 			continue
 		}
 
-		name := inflection.Singular(table.Name)
+		name := names.Name(table.QualifiedName())
 
 		if err := g.WriteServicePackageCode(
 			outputPath,
 			projectName, serviceName,
 			name,
 			targetModuleName, sourceModuleName, moduleVersion,
-			userRepo, isGrpcService,
+			userRepo, isGrpcService, table.View,
 		); err != nil {
 			return err
 		}
@@ -265,6 +337,7 @@ func (g *Generator) generateDataPackageCode(
 	moduleName, moduleVersion string,
 	tables sqlproto.TableDataArray,
 	services []string,
+	names *naming.Strategy,
 ) error {
 	if len(tables) == 0 {
 		return nil
@@ -276,7 +349,7 @@ func (g *Generator) generateDataPackageCode(
 			continue
 		}
 
-		name := inflection.Singular(table.Name)
+		name := names.Name(table.QualifiedName())
 
 		dataFields = make([]generators.DataField, 0)
 		for _, field := range table.Fields {
@@ -288,7 +361,10 @@ func (g *Generator) generateDataPackageCode(
 				Name: field.Name,
 				Type: field.Type,
 				//Null:    field.Null,
-				Comment: field.Comment,
+				Comment:      field.Comment,
+				Sensitive:    field.Annotations.Sensitive,
+				Enum:         field.Annotations.Enum,
+				EnumNumbered: field.Annotations.Numbered,
 			}
 			dataFields = append(dataFields, dataField)
 		}
@@ -299,6 +375,7 @@ func (g *Generator) generateDataPackageCode(
 			projectName, serviceName, name,
 			moduleName, moduleVersion,
 			dataFields,
+			table.View,
 		); err != nil {
 			return err
 		}
@@ -309,6 +386,8 @@ func (g *Generator) generateDataPackageCode(
 		_ = g.WriteWireSetCode(outputPath, projectName, serviceName, "data", "Client", []string{"Ent"})
 	case "gorm":
 		_ = g.WriteWireSetCode(outputPath, projectName, serviceName, "data", "Client", []string{"Gorm"})
+	case "bun":
+		_ = g.WriteWireSetCode(outputPath, projectName, serviceName, "data", "Client", []string{"Bun"})
 	}
 
 	return g.WriteWireSetCode(outputPath, projectName, serviceName, "data", "Repo", services)
@@ -1,5 +1,7 @@
 package sqlkratos
 
+import sqlorm "github.com/tx7do/go-wind-toolkit/sql-orm"
+
 type (
 	GeneratorOptions struct {
 		Driver string
@@ -14,7 +16,7 @@ type (
 		ModuleName       string
 		ModuleVersion    string
 
-		OrmType string // ORM type, e.g., "gorm", "sqlx", "ent"
+		OrmType string // ORM type, e.g., "gorm", "bun", "ent"
 
 		ProjectName string
 		ServiceName string
@@ -23,6 +25,11 @@ type (
 
 		UseRepo bool
 
+		NamingFile string // YAML file of the naming rules, shared by the Protobuf, ORM and data layer code
+
+		Strict bool           // Fail on the first table or column that the ent import cannot handle
+		Report *sqlorm.Report // If set, receives the diagnostics of the ent import
+
 		GenerateProto   bool
 		GenerateServer  bool
 		GenerateService bool
@@ -75,3 +82,21 @@ func WithSource(dsn string) GeneratorOption {
 		i.Source = dsn
 	}
 }
+
+func WithNamingFile(path string) GeneratorOption {
+	return func(i *GeneratorOptions) {
+		i.NamingFile = path
+	}
+}
+
+func WithStrict(b bool) GeneratorOption {
+	return func(i *GeneratorOptions) {
+		i.Strict = b
+	}
+}
+
+func WithReport(r *sqlorm.Report) GeneratorOption {
+	return func(i *GeneratorOptions) {
+		i.Report = r
+	}
+}
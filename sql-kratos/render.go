@@ -20,6 +20,7 @@ func (g *Generator) WriteDataPackageCode(
 	name string,
 	moduleName, moduleVersion string,
 	protoFields []generators.DataField,
+	readOnly bool,
 ) error {
 	var copyDataFields generators.DataFieldArray
 	for _, field := range protoFields {
@@ -28,9 +29,12 @@ func (g *Generator) WriteDataPackageCode(
 		}
 
 		copyDataField := generators.DataField{
-			Name:    field.Name,
-			Type:    field.Type,
-			Comment: field.Comment,
+			Name:         field.Name,
+			Type:         field.Type,
+			Comment:      field.Comment,
+			Sensitive:    field.Sensitive,
+			Enum:         field.Enum,
+			EnumNumbered: field.EnumNumbered,
 		}
 		copyDataFields = append(copyDataFields, copyDataField)
 	}
@@ -40,13 +44,19 @@ func (g *Generator) WriteDataPackageCode(
 		if err := g.writeEntClientCode(outputPath, projectName, serviceName); err != nil {
 			return err
 		}
-		return g.writeEntRepoCode(outputPath, projectName, serviceName, name, moduleName, moduleVersion, copyDataFields)
+		return g.writeEntRepoCode(outputPath, projectName, serviceName, name, moduleName, moduleVersion, copyDataFields, readOnly)
 
 	case "gorm":
 		if err := g.writeGormClientCode(outputPath, projectName, serviceName); err != nil {
 			return err
 		}
-		return g.writeGormRepoCode(outputPath, projectName, serviceName, name, moduleName, moduleVersion, copyDataFields)
+		return g.writeGormRepoCode(outputPath, projectName, serviceName, name, moduleName, moduleVersion, copyDataFields, readOnly)
+
+	case "bun":
+		if err := g.writeBunClientCode(outputPath, projectName, serviceName); err != nil {
+			return err
+		}
+		return g.writeBunRepoCode(outputPath, projectName, serviceName, name, moduleName, moduleVersion, copyDataFields, readOnly)
 
 	default:
 		return errors.New("sqlproto: unsupported orm: " + orm)
@@ -102,6 +112,23 @@ func (g *Generator) writeGormClientCode(
 	return nil
 }
 
+func (g *Generator) writeBunClientCode(
+	outputPath string,
+	projectModule string,
+	serviceName string,
+) error {
+	opts := code_generator.Options{
+		OutDir: outputPath,
+		Module: projectModule,
+		Vars: map[string]any{
+			"Service": serviceName,
+		},
+	}
+
+	_, err := g.goGenerator.GenerateBunClient(context.Background(), opts)
+	return err
+}
+
 func (g *Generator) writeEntRepoCode(
 	outputPath string,
 	projectModule string,
@@ -110,6 +137,7 @@ func (g *Generator) writeEntRepoCode(
 	apiPackageName string,
 	apiPackageVersion string,
 	fields generators.DataFieldArray,
+	readOnly bool,
 ) error {
 	opts := code_generator.Options{
 		OutDir: outputPath,
@@ -119,6 +147,7 @@ func (g *Generator) writeEntRepoCode(
 			"ApiPackage": stringcase.LowerCamelCase(apiPackageName) + stringcase.UpperCamelCase(apiPackageVersion),
 			"Model":      model,
 			"Fields":     fields,
+			"ReadOnly":   readOnly,
 		},
 	}
 
@@ -134,6 +163,7 @@ func (g *Generator) writeGormRepoCode(
 	apiPackageName string,
 	apiPackageVersion string,
 	fields generators.DataFieldArray,
+	readOnly bool,
 ) error {
 	opts := code_generator.Options{
 		OutDir: outputPath,
@@ -143,6 +173,7 @@ func (g *Generator) writeGormRepoCode(
 			"ApiPackage": stringcase.LowerCamelCase(apiPackageName) + stringcase.UpperCamelCase(apiPackageVersion),
 			"Model":      model,
 			"Fields":     fields,
+			"ReadOnly":   readOnly,
 		},
 	}
 
@@ -150,13 +181,39 @@ func (g *Generator) writeGormRepoCode(
 	return err
 }
 
+func (g *Generator) writeBunRepoCode(
+	outputPath string,
+	projectModule string,
+	serviceName string,
+	model string,
+	apiPackageName string,
+	apiPackageVersion string,
+	fields generators.DataFieldArray,
+	readOnly bool,
+) error {
+	opts := code_generator.Options{
+		OutDir: outputPath,
+		Module: projectModule,
+		Vars: map[string]any{
+			"Service":    serviceName,
+			"ApiPackage": stringcase.LowerCamelCase(apiPackageName) + stringcase.UpperCamelCase(apiPackageVersion),
+			"Model":      model,
+			"Fields":     fields,
+			"ReadOnly":   readOnly,
+		},
+	}
+
+	_, err := g.goGenerator.GenerateBunRepo(context.Background(), opts)
+	return err
+}
+
 func (g *Generator) WriteServicePackageCode(
 	outputPath string,
 	projectName string,
 	serviceName string,
 	name string,
 	targetModuleName, sourceModuleName, moduleVersion string,
-	useRepo, isGrpcService bool,
+	useRepo, isGrpcService, readOnly bool,
 ) error {
 	o := code_generator.Options{
 		OutDir: outputPath,
@@ -172,6 +229,8 @@ func (g *Generator) WriteServicePackageCode(
 			"Model":   name,
 			"IsGrpc":  isGrpcService,
 			"UseRepo": useRepo,
+
+			"ReadOnly": readOnly,
 		},
 	}
 
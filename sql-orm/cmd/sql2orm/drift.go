@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm"
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Report the drift between the database and the ent schema",
+	Long: `drift inspects the database of --dsn, or loads the schema dump of "file://dump.sql" or "text://<sql>" in
+the dev database, and reports the tables, columns, indexes and foreign keys that differ from the ent schema of --schema.
+It exits with status 1 when the database drifted from the ent schema.`,
+	Args: cobra.NoArgs,
+	Run:  drift,
+}
+
+func init() {
+	driftCmd.Flags().StringVar(&opts.DevURL, "dev-url", sqlorm.DefaultDevURL, "clean dev database loading a schema dump")
+	driftCmd.Flags().BoolVar(&opts.JSON, "json", false, "print the report as JSON")
+
+	rootCmd.AddCommand(driftCmd)
+}
+
+func drift(cmd *cobra.Command, _ []string) {
+	if opts.Source == "" {
+		log.Println("sql2orm: dsn must be provided")
+		_ = cmd.Help()
+		os.Exit(2)
+	}
+
+	report, err := sqlorm.Drift(
+		cmd.Context(),
+		opts.SchemaPath,
+		opts.Source,
+		opts.IncludedTables, opts.ExcludedTables,
+		sqlorm.WithDevURL(opts.DevURL),
+	)
+	if err != nil {
+		log.Printf("sql2orm: %v", err)
+		os.Exit(2)
+	}
+
+	if opts.JSON {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Printf("sql2orm: %v", err)
+		os.Exit(2)
+	}
+	if report.HasDrift() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,32 @@
+package sqlorm
+
+import (
+	"context"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entmigrate"
+)
+
+// DriftReport lists the differences between a database and an ent schema, see Drift.
+type DriftReport = entmigrate.DriftReport
+
+// DriftChange is a difference between a database and an ent schema: an object of the database only
+// ("added"), of the ent schema only ("removed"), or different in both ("changed").
+type DriftChange = entmigrate.DriftChange
+
+// Drift compares the tables, columns, indexes and foreign keys of the database specified by the DSN with
+// those of the ent schema of schemaPath. The DSN may be the DDL file of a schema dump, e.g. "file://dump.sql",
+// which is loaded in the dev database of WithDevURL. The tables are selected by the included and excluded
+// tables, and the revision tables of the migration tools are left out.
+func Drift(ctx context.Context, schemaPath, dsn string, includeTables, excludeTables []string, opts ...MigrateOption) (*DriftReport, error) {
+	o := &MigrateOptions{}
+	for _, apply := range opts {
+		apply(o)
+	}
+	return entmigrate.Drift(ctx, entmigrate.DriftOptions{
+		SchemaPath:    schemaPath,
+		DSN:           dsn,
+		DevURL:        o.DevURL,
+		IncludeTables: includeTables,
+		ExcludeTables: excludeTables,
+	})
+}
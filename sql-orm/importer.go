@@ -3,10 +3,15 @@ package sqlorm
 import (
 	"context"
 	"errors"
+	"os"
 	"strings"
 
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/bun"
 	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
 	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/gorm"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/sqlc"
 )
 
 func Importer(
@@ -15,13 +20,67 @@ func Importer(
 	drv, dsn,
 	schemaPath, daoPath *string,
 	includeTables, excludeTables []string,
+	opts ...ImporterOption,
 ) error {
+	o := &ImporterOptions{}
+	for _, apply := range opts {
+		apply(o)
+	}
+
 	switch OrmType(strings.ToLower(strings.TrimSpace(orm))) {
 	case OrmTypeEnt:
-		return entimport.Importer(ctx, dsn, schemaPath, includeTables, excludeTables)
+		decimalType, err := entimport.ParseDecimalType(o.DecimalType)
+		if err != nil {
+			return err
+		}
+		var mixins []entimport.Mixin
+		if o.MixinsFile != "" {
+			if mixins, err = entimport.LoadMixins(o.MixinsFile); err != nil {
+				return err
+			}
+		}
+		strategy, err := naming.Load(o.NamingFile)
+		if err != nil {
+			return err
+		}
+		return entimport.Importer(ctx, dsn, schemaPath, includeTables, excludeTables,
+			entimport.WithDecimalType(decimalType),
+			entimport.WithMixins(mixins),
+			entimport.WithNaming(strategy),
+			entimport.WithMerge(o.Merge),
+			entimport.WithMergeOutput(os.Stdout),
+			entimport.WithSchemaPackages(o.SchemaPackages),
+			entimport.WithStrict(o.Strict),
+			entimport.WithReport(o.Report),
+			entimport.WithReportOutput(os.Stdout),
+		)
 
 	case OrmTypeGorm:
-		return gorm.Importer(ctx, drv, dsn, schemaPath, daoPath, includeTables, excludeTables)
+		config, err := gorm.LoadConfig(o.GormConfigFile)
+		if err != nil {
+			return err
+		}
+		for _, apply := range o.gorm {
+			apply(config)
+		}
+		if config.Naming, err = naming.Load(o.NamingFile); err != nil {
+			return err
+		}
+		return gorm.Importer(ctx, drv, dsn, schemaPath, daoPath, includeTables, excludeTables, config)
+
+	case OrmTypeBun:
+		strategy, err := naming.Load(o.NamingFile)
+		if err != nil {
+			return err
+		}
+		return bun.Importer(ctx, dsn, schemaPath, includeTables, excludeTables, strategy)
+
+	case OrmTypeSqlc:
+		strategy, err := naming.Load(o.NamingFile)
+		if err != nil {
+			return err
+		}
+		return sqlc.Importer(ctx, dsn, schemaPath, includeTables, excludeTables, strategy)
 
 	default:
 		return errors.New("sql2orm: unsupported orm type: " + orm)
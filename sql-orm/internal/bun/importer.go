@@ -0,0 +1,161 @@
+// Package bun generates the uptrace/bun models of the tables of a database.
+package bun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
+)
+
+const header = "// Code generated " + "by bunimport, DO NOT EDIT."
+
+// Importer generates the bun models of the tables of the database specified by the DSN, one file by table, and
+// writes them to the schemaPath. The DSN is inspected as for ent, so it may be a DDL source as well. The models
+// are named by the naming strategy, which may be nil.
+//
+// The foreign keys become the belongs-to, has-one, has-many and m2m relations of the models. The join models of
+// the m2m relations are listed by the JoinModels function of the package, to be registered with
+// bun.DB.RegisterModel.
+func Importer(ctx context.Context, dsn, schemaPath *string, includeTables, excludeTables []string, names *naming.Strategy) error {
+	if schemaPath == nil {
+		return errors.New("bunimport: schema path is nil")
+	}
+	if dsn == nil {
+		return errors.New("bunimport: dsn is nil")
+	}
+
+	tables, dialect, err := entimport.InspectTables(ctx, *dsn, includeTables, excludeTables)
+	if err != nil {
+		return fmt.Errorf("bunimport: %w", err)
+	}
+	if len(tables) == 0 {
+		return errors.New("bunimport: no table to import")
+	}
+
+	if err = os.MkdirAll(*schemaPath, os.ModePerm); err != nil {
+		return fmt.Errorf("bunimport: create schema path: %w", err)
+	}
+	pkg := packageName(*schemaPath)
+
+	models := newModels(tables, dialect, names)
+	for _, m := range models {
+		if err = writeFile(filepath.Join(*schemaPath, m.Table+".go"), modelTemplate, map[string]any{
+			"Header":  header,
+			"Package": pkg,
+			"Model":   m,
+		}); err != nil {
+			return err
+		}
+	}
+	return writeFile(filepath.Join(*schemaPath, "bun_models.go"), registryTemplate, map[string]any{
+		"Header":  header,
+		"Package": pkg,
+		"Models":  models,
+	})
+}
+
+// packageName returns the name of the package of the directory, "models" if it is not a Go identifier.
+func packageName(dir string) string {
+	name := strings.ToLower(filepath.Base(filepath.Clean(dir)))
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		return "models"
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return "models"
+		}
+	}
+	return name
+}
+
+// writeFile renders the template to a formatted Go file.
+func writeFile(path string, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("bunimport: render %s: %w", filepath.Base(path), err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("bunimport: format %s: %w", filepath.Base(path), err)
+	}
+	if err = os.WriteFile(path, src, 0o644); err != nil {
+		return fmt.Errorf("bunimport: write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+var funcs = template.FuncMap{
+	// comment writes a text as a line comment.
+	"comment": func(text string) string {
+		return "// " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n// ")
+	},
+}
+
+var modelTemplate = template.Must(template.New("model").Funcs(funcs).Parse(`{{.Header}}
+
+package {{.Package}}
+
+import (
+{{- range .Model.Imports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/uptrace/bun"
+)
+
+{{with .Model -}}
+// {{.Name}} mapped from table <{{.Table}}>
+{{- with .Comment}}
+{{comment .}}
+{{- end}}
+type {{.Name}} struct {
+	bun.BaseModel ` + "`" + `bun:"table:{{.Table}}"` + "`" + `
+
+{{range .Fields}}
+{{- with .Comment}}{{comment .}}
+{{end -}}
+	{{.Name}} {{.Type}} ` + "`" + `{{.Tag}}` + "`" + `
+{{end}}
+{{- if .Relations}}
+{{range .Relations}}
+	{{.Name}} {{.Type}} ` + "`" + `{{.Tag}}` + "`" + `
+{{- end}}
+{{- end}}
+}
+{{- end}}
+`))
+
+var registryTemplate = template.Must(template.New("registry").Parse(`{{.Header}}
+
+package {{.Package}}
+
+// Models returns the models of the tables, e.g. to create the tables.
+func Models() []any {
+	return []any{
+{{- range .Models}}
+		(*{{.Name}})(nil),
+{{- end}}
+	}
+}
+
+// JoinModels returns the models of the join tables of the m2m relations, which must be registered with
+// bun.DB.RegisterModel before the relations are queried.
+func JoinModels() []any {
+	return []any{
+{{- range .Models}}{{if .Join}}
+		(*{{.Name}})(nil),
+{{- end}}{{end}}
+	}
+}
+`))
@@ -0,0 +1,54 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImporter(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.db")
+	db, err := sql.Open("sqlite3", path)
+	r.NoError(err)
+	for _, ddl := range []string{
+		"CREATE TABLE users (id integer PRIMARY KEY, name varchar(64) NOT NULL, email text NULL UNIQUE, active boolean NOT NULL DEFAULT true, created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE posts (id integer PRIMARY KEY AUTOINCREMENT, author_id integer NOT NULL REFERENCES users (id), body text NULL, payload json NULL)",
+		"CREATE TABLE groups (id integer PRIMARY KEY, name text NOT NULL)",
+		"CREATE TABLE user_groups (user_id integer NOT NULL REFERENCES users (id), group_id integer NOT NULL REFERENCES groups (id), PRIMARY KEY (user_id, group_id))",
+	} {
+		_, err = db.Exec(ddl)
+		r.NoError(err)
+	}
+	r.NoError(db.Close())
+
+	dsn := "sqlite://" + path
+	schemaPath := filepath.Join(dir, "models")
+	r.NoError(Importer(context.Background(), &dsn, &schemaPath, nil, nil, nil))
+
+	model := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(schemaPath, name))
+		r.NoError(err)
+		return string(b)
+	}
+	user := model("users.go")
+	r.Contains(user, "package models")
+	r.Contains(user, "bun.BaseModel `bun:\"table:users\"`")
+	r.Contains(user, "ID        int64     `bun:\"id,pk,autoincrement\" json:\"id\"`")
+	r.Contains(user, "Email     *string   `bun:\"email,unique\" json:\"email\"`")
+	r.Contains(user, "Active    bool      `bun:\"active,notnull,default:true\" json:\"active\"`")
+	r.Contains(user, "Posts  []*Post  `bun:\"rel:has-many,join:id=author_id\" json:\"posts,omitempty\"`")
+	r.Contains(user, "Groups []*Group `bun:\"m2m:user_groups,join:User=Group\" json:\"groups,omitempty\"`")
+
+	post := model("posts.go")
+	r.Contains(post, "Payload  json.RawMessage `bun:\"payload\" json:\"payload\"`")
+	r.Contains(post, "Author *User `bun:\"rel:belongs-to,join:author_id=id\" json:\"author,omitempty\"`")
+
+	r.Contains(model("user_groups.go"), "User  *User  `bun:\"rel:belongs-to,join:user_id=id\" json:\"user,omitempty\"`")
+	r.Contains(model("bun_models.go"), "func JoinModels() []any {\n\treturn []any{\n\t\t(*UserGroup)(nil),\n\t}\n}")
+}
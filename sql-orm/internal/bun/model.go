@@ -0,0 +1,321 @@
+package bun
+
+import (
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/go-openapi/inflect"
+	gormschema "gorm.io/gorm/schema"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/relation"
+)
+
+// model is a bun model generated for a table.
+type model struct {
+	Name    string
+	Table   string
+	Comment string
+	Imports []string
+	Fields  []modelField
+	// Relations are the fields of the associations of the model.
+	Relations []modelField
+	// Join reports if the model is the join model of a many to many relation, to be registered in bun.
+	Join bool
+}
+
+// modelField is a field of a bun model.
+type modelField struct {
+	Name    string
+	Type    string
+	Tag     string
+	Comment string
+}
+
+// fieldNames names the fields of the columns like the Go initialisms, e.g. "UserID" for "user_id".
+var fieldNames = gormschema.NamingStrategy{SingularTable: true}
+
+// fieldName returns the name of the field of a column.
+func fieldName(column string) string {
+	return fieldNames.SchemaName(column)
+}
+
+// newModels returns the models of the tables of the dialect, with the association fields of their
+// foreign keys.
+func newModels(tables []*schema.Table, dialect string, names *naming.Strategy) []*model {
+	selected := make(map[*schema.Table]bool, len(tables))
+	for _, t := range tables {
+		selected[t] = true
+	}
+	assocs := relation.Associations(tables, func(t *schema.Table) bool { return selected[t] }, relation.Naming{
+		Field:  fieldName,
+		Type:   names.TypeName,
+		Plural: names.PluralTypeName,
+	})
+
+	var (
+		models = make([]*model, 0, len(tables))
+		joins  = make(map[string]bool)
+	)
+	for _, t := range tables {
+		m := &model{
+			Name:    names.TypeName(t.Name),
+			Table:   t.Name,
+			Comment: comment(t.Attrs),
+		}
+		imports := make(map[string]bool)
+		for _, c := range t.Columns {
+			typ, imp := goType(t, c, dialect)
+			if imp != "" {
+				imports[imp] = true
+			}
+			m.Fields = append(m.Fields, modelField{
+				Name:    fieldName(c.Name),
+				Type:    typ,
+				Tag:     `bun:"` + columnTag(t, c, dialect) + `" json:"` + c.Name + `"`,
+				Comment: comment(c.Attrs),
+			})
+		}
+		for _, a := range assocs[t] {
+			f, ok := relationField(a, names, assocs)
+			if !ok {
+				continue
+			}
+			if a.Kind == relation.ManyToMany {
+				joins[a.Join().Name] = true
+			}
+			m.Relations = append(m.Relations, f)
+		}
+		for imp := range imports {
+			m.Imports = append(m.Imports, imp)
+		}
+		sort.Strings(m.Imports)
+		models = append(models, m)
+	}
+	for _, m := range models {
+		m.Join = joins[m.Table]
+	}
+	return models
+}
+
+// comment returns the comment of a table or a column.
+func comment(attrs []schema.Attr) string {
+	for _, attr := range attrs {
+		if c, ok := attr.(*schema.Comment); ok {
+			return c.Text
+		}
+	}
+	return ""
+}
+
+// relationField returns the field of an association. The many to many associations need the model of their
+// join table, which names the relations to the two tables.
+func relationField(a relation.Association, names *naming.Strategy, assocs map[*schema.Table][]relation.Association) (modelField, bool) {
+	typ := names.TypeName(a.Table.Name)
+	json := inflect.Underscore(a.Name)
+	var tag string
+	switch a.Kind {
+	case relation.BelongsTo:
+		typ = "*" + typ
+		tag = "rel:belongs-to,join:" + a.ForeignKey.Column + "=" + a.ForeignKey.RefColumn
+	case relation.HasOne:
+		typ = "*" + typ
+		tag = "rel:has-one,join:" + a.ForeignKey.RefColumn + "=" + a.ForeignKey.Column
+	case relation.HasMany:
+		typ = "[]*" + typ
+		tag = "rel:has-many,join:" + a.ForeignKey.RefColumn + "=" + a.ForeignKey.Column
+	case relation.ManyToMany:
+		from, ok := belongsTo(assocs[a.Join()], a.ForeignKey)
+		if !ok {
+			return modelField{}, false
+		}
+		to, ok := belongsTo(assocs[a.Join()], a.JoinForeignKey)
+		if !ok {
+			return modelField{}, false
+		}
+		typ = "[]*" + typ
+		tag = "m2m:" + a.Join().Name + ",join:" + from + "=" + to
+	}
+	return modelField{
+		Name: a.Name,
+		Type: typ,
+		Tag:  `bun:"` + tag + `" json:"` + json + `,omitempty"`,
+	}, true
+}
+
+// belongsTo returns the name of the belongs-to relation of the foreign key among the associations of its table.
+func belongsTo(assocs []relation.Association, fk relation.ForeignKey) (string, bool) {
+	for _, a := range assocs {
+		if a.Kind == relation.BelongsTo && a.ForeignKey.Column == fk.Column {
+			return a.Name, true
+		}
+	}
+	return "", false
+}
+
+// columnTag returns the bun tag of a column: its name, followed by the pk, autoincrement, notnull, unique,
+// array, nullzero and default options.
+func columnTag(t *schema.Table, c *schema.Column, dialect string) string {
+	opts := []string{c.Name}
+	typ, _ := goType(t, c, dialect)
+	pk := primaryKey(t, c)
+	if pk {
+		opts = append(opts, "pk")
+	}
+	if autoIncrement(t, c, dialect) {
+		opts = append(opts, "autoincrement")
+	}
+	if !pk && !c.Type.Null {
+		opts = append(opts, "notnull")
+	}
+	if !pk && uniqueColumn(t, c) {
+		opts = append(opts, "unique")
+	}
+	if _, ok := c.Type.Type.(*postgres.ArrayType); ok && strings.HasPrefix(typ, "[]") {
+		opts = append(opts, "array")
+	}
+	if def, ok := defaultValue(c); ok {
+		// The zero values of the nullzero fields are written as DEFAULT, so that the database sets the default.
+		// A false or a 0 is a value of its own, so only the nullable and time columns are nullzero.
+		if c.Type.Null || typ == "time.Time" {
+			opts = append(opts, "nullzero")
+		}
+		opts = append(opts, "default:"+def)
+	}
+	return strings.Join(opts, ",")
+}
+
+// primaryKey reports if the column is part of the primary key of its table.
+func primaryKey(t *schema.Table, c *schema.Column) bool {
+	if t.PrimaryKey == nil {
+		return false
+	}
+	for _, p := range t.PrimaryKey.Parts {
+		if p.C != nil && p.C.Name == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueColumn reports if the column has a unique index of its own.
+func uniqueColumn(t *schema.Table, c *schema.Column) bool {
+	for _, idx := range t.Indexes {
+		if idx.Unique && len(idx.Parts) == 1 && idx.Parts[0].C != nil && idx.Parts[0].C.Name == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// autoIncrement reports if the database generates the values of the column.
+func autoIncrement(t *schema.Table, c *schema.Column, dialect string) bool {
+	if _, ok := c.Type.Type.(*postgres.SerialType); ok {
+		return true
+	}
+	for _, attr := range c.Attrs {
+		switch attr.(type) {
+		case *mysql.AutoIncrement, *sqlite.AutoIncrement, *postgres.Identity:
+			return true
+		}
+	}
+	if def, ok := defaultValue(c); ok && strings.HasPrefix(strings.ToLower(def), "nextval(") {
+		return true
+	}
+	// The INTEGER PRIMARY KEY of SQLite is an alias of the row ID.
+	typ, ok := c.Type.Type.(*schema.IntegerType)
+	return ok && dialect == "sqlite3" && strings.EqualFold(typ.T, "integer") &&
+		primaryKey(t, c) && len(t.PrimaryKey.Parts) == 1
+}
+
+// defaultValue returns the default value of a column, unless it cannot be written in a bun tag.
+func defaultValue(c *schema.Column) (string, bool) {
+	var def string
+	switch d := c.Default.(type) {
+	case *schema.Literal:
+		def = d.V
+	case *schema.RawExpr:
+		def = d.X
+	case *schema.NamedDefault:
+		return defaultValue(&schema.Column{Default: d.Expr})
+	default:
+		return "", false
+	}
+	def = strings.TrimSpace(def)
+	if def == "" || strings.ContainsAny(def, ",\"`") {
+		return "", false
+	}
+	return def, true
+}
+
+// goType returns the Go type of a column, and the import path of its package. The nullable columns are
+// pointers, but for the slices.
+func goType(t *schema.Table, c *schema.Column, dialect string) (string, string) {
+	typ, imp := baseType(c.Type.Type)
+	// The integers of SQLite are 64-bit.
+	if it, ok := c.Type.Type.(*schema.IntegerType); ok && dialect == "sqlite3" && strings.EqualFold(it.T, "integer") {
+		typ = "int64"
+	}
+	// SQLite reports the INTEGER PRIMARY KEY columns as nullable, but a primary key is never null.
+	if c.Type.Null && !primaryKey(t, c) && !strings.HasPrefix(typ, "[]") && typ != "json.RawMessage" {
+		typ = "*" + typ
+	}
+	return typ, imp
+}
+
+// baseType returns the Go type of a column type, and the import path of its package.
+func baseType(t schema.Type) (string, string) {
+	switch t := t.(type) {
+	case *schema.BoolType:
+		return "bool", ""
+	case *schema.IntegerType:
+		typ := "int32"
+		switch strings.ToLower(t.T) {
+		case "tinyint":
+			typ = "int8"
+		case "smallint", "int2":
+			typ = "int16"
+		case "bigint", "int8":
+			typ = "int64"
+		}
+		if t.Unsigned {
+			typ = "u" + typ
+		}
+		return typ, ""
+	case *postgres.SerialType:
+		switch strings.ToLower(t.T) {
+		case "smallserial", "serial2":
+			return "int16", ""
+		case "bigserial", "serial8":
+			return "int64", ""
+		}
+		return "int32", ""
+	case *schema.FloatType:
+		if strings.EqualFold(t.T, "real") || (strings.EqualFold(t.T, "float") && t.Precision <= 23) {
+			return "float32", ""
+		}
+		return "float64", ""
+	case *schema.DecimalType:
+		return "float64", ""
+	case *schema.StringType, *schema.EnumType:
+		return "string", ""
+	case *schema.BinaryType:
+		return "[]byte", ""
+	case *schema.JSONType:
+		return "json.RawMessage", "encoding/json"
+	case *schema.TimeType:
+		return "time.Time", "time"
+	case *schema.UUIDType:
+		return "uuid.UUID", "github.com/google/uuid"
+	case *postgres.ArrayType:
+		if typ, imp := baseType(t.Type); !strings.HasPrefix(typ, "[]") && typ != "json.RawMessage" {
+			return "[]" + typ, imp
+		}
+	}
+	return "string", ""
+}
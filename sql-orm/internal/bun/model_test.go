@@ -0,0 +1,57 @@
+package bun
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+)
+
+func TestColumnTagDefault(t *testing.T) {
+	r := require.New(t)
+	for _, tt := range []struct {
+		column *schema.Column
+		want   string
+	}{
+		{
+			// A false is written as is, instead of the default.
+			column: schema.NewBoolColumn("active", "bool").SetDefault(&schema.RawExpr{X: "1"}),
+			want:   "active,notnull,default:1",
+		},
+		{
+			column: schema.NewIntColumn("count", "int").SetDefault(&schema.Literal{V: "0"}),
+			want:   "count,notnull,default:0",
+		},
+		{
+			column: schema.NewTimeColumn("created_at", "datetime").SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP"}),
+			want:   "created_at,notnull,nullzero,default:CURRENT_TIMESTAMP",
+		},
+		{
+			column: schema.NewNullStringColumn("nickname", "varchar").SetDefault(&schema.Literal{V: "'guest'"}),
+			want:   "nickname,nullzero,default:'guest'",
+		},
+	} {
+		table := schema.NewTable("users").AddColumns(tt.column)
+		r.Equal(tt.want, columnTag(table, tt.column, "mysql"), tt.column.Name)
+	}
+}
+
+func TestNewModelsPlural(t *testing.T) {
+	r := require.New(t)
+	teamID := schema.NewIntColumn("id", "bigint")
+	teams := schema.NewTable("teams").AddColumns(teamID)
+	teams.SetPrimaryKey(schema.NewPrimaryKey(teamID))
+	staffID, staffTeamID := schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("team_id", "bigint")
+	staves := schema.NewTable("staves").AddColumns(staffID, staffTeamID)
+	staves.SetPrimaryKey(schema.NewPrimaryKey(staffID))
+	staves.AddForeignKeys(schema.NewForeignKey("fk_team").AddColumns(staffTeamID).SetRefTable(teams).AddRefColumns(teamID))
+
+	// The has-many associations are named by the plural of the naming strategy.
+	names := naming.New(naming.Config{Irregulars: map[string]string{"staff": "staves"}})
+	models := newModels([]*schema.Table{teams, staves}, "mysql", names)
+	r.Equal("Team", models[0].Name)
+	r.Len(models[0].Relations, 1)
+	r.Equal("Staves", models[0].Relations[0].Name)
+}
@@ -0,0 +1,82 @@
+package entimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent/schema/field"
+
+	"github.com/tx7do/go-wind-toolkit/generators/annotation"
+)
+
+// applyEnumAnnotation turns a string or integer field into an enum of the values of its @enum annotation.
+// The enum keeps the exact column type, so the numbered values, e.g. @enum(1:ACTIVE), are stored as the
+// numbers of an integer column and named by NamedValues. The names alone cannot be stored in an integer
+// column, so the annotation is ignored there.
+func applyEnumAnnotation(dlct string, desc *field.Descriptor, col *schema.Column, a annotation.Annotations) {
+	t := desc.Info.Type
+	if len(a.Enum) == 0 || !(t == field.TypeString || t.Integer() && a.Numbered) {
+		return
+	}
+	desc.Info = &field.TypeInfo{Type: field.TypeEnum}
+	desc.Enums = make([]struct{ N, V string }, 0, len(a.Enum))
+	for _, v := range a.Enum {
+		value := v.Name
+		if a.Numbered {
+			value = strconv.FormatInt(v.Number, 10)
+		}
+		desc.Enums = append(desc.Enums, struct{ N, V string }{N: v.Name, V: value})
+	}
+	if _, ok := entTypes[dlct]; ok {
+		if typ := columnType(dlct, col); typ != "" {
+			desc.SchemaType = map[string]string{dlct: typ}
+		}
+	}
+}
+
+// applyCommentAnnotations applies the @sensitive, @json and validation annotations of a column comment.
+// The validation hints become the validators of the field, before the ones derived from the column.
+func applyCommentAnnotations(desc *field.Descriptor, a annotation.Annotations) {
+	if a.Sensitive {
+		desc.Sensitive = true
+	} else if a.JSON != "" {
+		// The sensitive fields are not serialized, so they keep no JSON name.
+		desc.Tag = fmt.Sprintf(`json:"%s,omitempty"`, a.JSON)
+	}
+	if !a.Validated() {
+		return
+	}
+	switch t := desc.Info.Type; {
+	case t == field.TypeString:
+		switch {
+		case a.MinLen == 1:
+			appendStringValidator(desc, "NotEmpty")
+		case a.MinLen > 1:
+			appendStringValidator(desc, "MinLen", intLit(a.MinLen))
+		}
+		if a.MaxLen > 0 {
+			appendStringValidator(desc, "MaxLen", intLit(a.MaxLen))
+		}
+		if a.Pattern != "" {
+			appendStringValidator(desc, "Match", call(selector("regexp", "MustCompile"), rawStrLit(a.Pattern)))
+		}
+	case t.Integer(), t.Float() && !isDecimal(desc):
+		for _, v := range []string{a.Min, a.Max} {
+			// The integer fields only take integer bounds, and the unsigned ones no negative bound.
+			if t.Integer() && strings.Contains(v, ".") || t >= field.TypeUint8 && t <= field.TypeUint64 && strings.HasPrefix(v, "-") {
+				return
+			}
+		}
+		switch {
+		case a.Min != "" && a.Max != "":
+			appendValidator(desc, "Range", "", numLit(a.Min), numLit(a.Max))
+		case a.Min != "":
+			appendValidator(desc, "Min", "", numLit(a.Min))
+		case a.Max != "":
+			appendValidator(desc, "Max", "", numLit(a.Max))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package entimport
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+)
+
+const (
+	// DecimalFloat imports the DECIMAL and NUMERIC columns as float64 fields, which may lose precision.
+	DecimalFloat DecimalType = "float"
+	// DecimalShopspring imports the DECIMAL and NUMERIC columns as github.com/shopspring/decimal values.
+	DecimalShopspring DecimalType = "decimal"
+)
+
+// decimalPkg is the import path of the Go type used by DecimalShopspring.
+const decimalPkg = "github.com/shopspring/decimal"
+
+// ParseDecimalType returns the DecimalType of the given name. An empty name selects DecimalFloat.
+func ParseDecimalType(name string) (DecimalType, error) {
+	switch t := DecimalType(strings.ToLower(strings.TrimSpace(name))); t {
+	case "":
+		return DecimalFloat, nil
+	case DecimalFloat, DecimalShopspring:
+		return t, nil
+	default:
+		return "", fmt.Errorf("entimport: unsupported decimal type %q", name)
+	}
+}
+
+// decimalField converts a DECIMAL or NUMERIC column to a field. By default, the column becomes a float
+// field, and with DecimalShopspring it keeps its exact value through field.GoType. The column type is
+// kept by applySchemaType for the database importers, and set here for the text importer that has no dialect.
+func (i *ImportOptions) decimalField(dlct string, typ *schema.DecimalType, name string) ent.Field {
+	f := field.Float(name)
+	if i.decimalType != DecimalShopspring {
+		return f
+	}
+	desc := f.Descriptor()
+	appendBuilderCall(desc, "GoType", decimalPkg, &ast.CompositeLit{Type: selector("decimal", "Decimal")})
+	if dlct == "" {
+		t := strings.ToLower(typ.T)
+		if typ.Precision > 0 {
+			t = fmt.Sprintf("%s(%d,%d)", t, typ.Precision, typ.Scale)
+		}
+		desc.SchemaType = map[string]string{
+			dialect.MySQL:    t,
+			dialect.Postgres: t,
+			dialect.SQLite:   t,
+		}
+	}
+	return f
+}
+
+// isDecimal reports if the field was imported with the decimal Go type. Its default values and validators
+// cannot be expressed with float64 literals, so they are kept as SQL or skipped.
+func isDecimal(desc *field.Descriptor) bool {
+	for _, annot := range desc.Annotations {
+		calls, ok := annot.(builderCalls)
+		if !ok {
+			continue
+		}
+		for _, c := range calls {
+			if c.method == "GoType" && len(c.imports) > 0 && c.imports[0] == decimalPkg {
+				return true
+			}
+		}
+	}
+	return false
+}
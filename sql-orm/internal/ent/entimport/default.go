@@ -0,0 +1,288 @@
+package entimport
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/field"
+)
+
+var (
+	// reCast matches a literal followed by a PostgreSQL type cast, e.g. 'active'::character varying.
+	reCast = regexp.MustCompile(`^('(?:[^']|'')*'|[-+]?[0-9.]+)::[\w\s]+(?:\(\d+\))?(?:\[\])?$`)
+	// reNow matches the expressions that set a column to the current date and time.
+	reNow = regexp.MustCompile(`(?i)^(?:current_timestamp|now|localtimestamp|statement_timestamp|transaction_timestamp|clock_timestamp|datetime)(?:\((?:\d*|'now'|'now',\s*'localtime')\))?$`)
+	// reUUID matches the expressions that generate a random UUID.
+	reUUID = regexp.MustCompile(`(?i)^(?:gen_random_uuid|uuid_generate_v4|uuid)\(\)$`)
+)
+
+// entTypes holds, per dialect, the column types that ent generates by default for a field type.
+// A column whose formatted type is not listed keeps its exact type through field.SchemaType.
+var entTypes = map[string]map[field.Type][]string{
+	dialect.MySQL: {
+		field.TypeBool:    {"bool", "boolean", "tinyint(1)"},
+		field.TypeInt8:    {"tinyint"},
+		field.TypeUint8:   {"tinyint unsigned"},
+		field.TypeInt16:   {"smallint"},
+		field.TypeUint16:  {"smallint unsigned"},
+		field.TypeInt32:   {"int"},
+		field.TypeUint32:  {"int unsigned"},
+		field.TypeInt:     {"bigint"},
+		field.TypeInt64:   {"bigint"},
+		field.TypeUint:    {"bigint unsigned"},
+		field.TypeUint64:  {"bigint unsigned"},
+		field.TypeFloat32: {"double"},
+		field.TypeFloat64: {"double"},
+		field.TypeString:  {"varchar(255)"},
+		field.TypeBytes:   {"blob"},
+		field.TypeJSON:    {"json"},
+		field.TypeTime:    {"timestamp"},
+		field.TypeUUID:    {"char(36)", "uuid"},
+	},
+	dialect.Postgres: {
+		field.TypeBool:    {"boolean"},
+		field.TypeInt8:    {"smallint"},
+		field.TypeUint8:   {"smallint"},
+		field.TypeInt16:   {"smallint"},
+		field.TypeUint16:  {"integer"},
+		field.TypeInt32:   {"integer"},
+		field.TypeUint32:  {"bigint"},
+		field.TypeInt:     {"bigint"},
+		field.TypeInt64:   {"bigint"},
+		field.TypeUint:    {"bigint"},
+		field.TypeUint64:  {"bigint"},
+		field.TypeFloat32: {"real"},
+		field.TypeFloat64: {"double precision"},
+		field.TypeString:  {"character varying"},
+		field.TypeBytes:   {"bytea"},
+		field.TypeJSON:    {"jsonb"},
+		field.TypeTime:    {"timestamp with time zone", "timestamptz"},
+		field.TypeUUID:    {"uuid"},
+	},
+	dialect.SQLite: {
+		field.TypeBool:    {"bool", "boolean"},
+		field.TypeInt8:    {"integer"},
+		field.TypeUint8:   {"integer"},
+		field.TypeInt16:   {"integer"},
+		field.TypeUint16:  {"integer"},
+		field.TypeInt32:   {"integer"},
+		field.TypeUint32:  {"integer"},
+		field.TypeInt:     {"integer"},
+		field.TypeInt64:   {"integer"},
+		field.TypeUint:    {"integer"},
+		field.TypeUint64:  {"integer"},
+		field.TypeFloat32: {"real"},
+		field.TypeFloat64: {"real"},
+		field.TypeString:  {"text"},
+		field.TypeBytes:   {"blob"},
+		field.TypeJSON:    {"json"},
+		field.TypeTime:    {"datetime"},
+		field.TypeUUID:    {"uuid"},
+	},
+}
+
+// applySchemaType preserves the exact column type when ent would generate a different one.
+func applySchemaType(dlct string, desc *field.Descriptor, col *schema.Column) {
+	types, ok := entTypes[dlct]
+	if !ok || len(desc.SchemaType) > 0 || desc.Info.Type == field.TypeEnum {
+		return
+	}
+	typ := columnType(dlct, col)
+	if typ == "" {
+		return
+	}
+	for _, t := range types[desc.Info.Type] {
+		if t == typ {
+			return
+		}
+	}
+	desc.SchemaType = map[string]string{dlct: typ}
+}
+
+// columnType returns the lower-cased column type, formatted for the given dialect.
+func columnType(dlct string, col *schema.Column) string {
+	var (
+		typ string
+		err error
+	)
+	switch dlct {
+	case dialect.MySQL:
+		typ, err = mysql.FormatType(col.Type.Type)
+	case dialect.Postgres:
+		typ, err = postgres.FormatType(col.Type.Type)
+	case dialect.SQLite:
+		// SQLite keeps the declared type as is, e.g. varchar(255).
+		typ = col.Type.Raw
+	}
+	if err != nil || typ == "" {
+		typ = col.Type.Raw
+	}
+	return strings.ToLower(strings.TrimSpace(typ))
+}
+
+// applyDefault translates the column default into the field default. Values that ent can express in
+// Go become Default (or Default(time.Now)/Default(uuid.New) for the well-known functions), and any
+// other default is kept as SQL through the entsql annotations.
+func applyDefault(desc *field.Descriptor, col *schema.Column) {
+	expr := col.Default
+	if n, ok := expr.(*schema.NamedDefault); ok {
+		expr = n.Expr
+	}
+	var (
+		v   string
+		raw bool
+	)
+	switch x := expr.(type) {
+	case *schema.Literal:
+		v = x.V
+	case *schema.RawExpr:
+		v, raw = x.X, true
+	default:
+		return
+	}
+	v = strings.TrimSpace(v)
+	for len(v) > 1 && v[0] == '(' && v[len(v)-1] == ')' {
+		v = strings.TrimSpace(v[1 : len(v)-1])
+	}
+	if m := reCast.FindStringSubmatch(v); m != nil {
+		v = m[1]
+	}
+	// Sequences back auto-increment columns, which ent already handles.
+	if v == "" || strings.EqualFold(v, "null") || strings.HasPrefix(strings.ToLower(v), "nextval(") {
+		return
+	}
+	s, quoted := unquote(v)
+	switch t := desc.Info.Type; {
+	case t == field.TypeBool:
+		switch strings.ToLower(s) {
+		case "1", "t", "true", "b'1'", "y", "yes", "on":
+			desc.Default = true
+			return
+		case "0", "f", "false", "b'0'", "n", "no", "off":
+			desc.Default = false
+			return
+		}
+	case t.Integer():
+		if t >= field.TypeUint8 {
+			if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+				desc.Default = n
+				return
+			}
+		} else if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			desc.Default = n
+			return
+		}
+	case t.Float() && !isDecimal(desc):
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			desc.Default = n
+			return
+		}
+	case t == field.TypeString, t == field.TypeEnum:
+		if quoted || !raw {
+			desc.Default = s
+			return
+		}
+	case t == field.TypeTime:
+		if reNow.MatchString(v) {
+			desc.Default = time.Now
+			return
+		}
+	case t == field.TypeUUID:
+		if reUUID.MatchString(v) {
+			appendBuilderCall(desc, "Default", "github.com/google/uuid", selector("uuid", "New"))
+			return
+		}
+	}
+	switch t := desc.Info.Type; {
+	case quoted && (t == field.TypeJSON || t == field.TypeBytes):
+		// ent quotes the default of these types, so only the value is kept.
+		desc.Annotations = append(desc.Annotations, entsql.Annotation{Default: s})
+	case t == field.TypeTime || t == field.TypeUUID:
+		// ent writes the default of these types as is.
+		desc.Annotations = append(desc.Annotations, entsql.Annotation{Default: v})
+	default:
+		appendBuilderCall(desc, "Annotations", "entgo.io/ent/dialect/entsql",
+			call(selector("entsql", "DefaultExpr"), strLit(v)))
+	}
+}
+
+// databaseDefault moves the default value of a field to the column, as an entsql.DefaultExpr. The default
+// functions, e.g. Default(time.Now), are kept on the field.
+func databaseDefault(desc *field.Descriptor) {
+	var v string
+	switch d := desc.Default.(type) {
+	case bool:
+		v = strconv.FormatBool(d)
+	case int64:
+		v = strconv.FormatInt(d, 10)
+	case uint64:
+		v = strconv.FormatUint(d, 10)
+	case float64:
+		v = strconv.FormatFloat(d, 'g', -1, 64)
+	case string:
+		v = "'" + strings.ReplaceAll(d, "'", "''") + "'"
+	default:
+		return
+	}
+	desc.Default = nil
+	appendBuilderCall(desc, "Annotations", "entgo.io/ent/dialect/entsql",
+		call(selector("entsql", "DefaultExpr"), strLit(v)))
+}
+
+// applyUpdateDefault translates an ON UPDATE CURRENT_TIMESTAMP attribute into UpdateDefault(time.Now).
+func applyUpdateDefault(desc *field.Descriptor, col *schema.Column) {
+	if desc.Info.Type != field.TypeTime {
+		return
+	}
+	for _, attr := range col.Attrs {
+		if a, ok := attr.(*mysql.OnUpdate); ok && reNow.MatchString(a.A) {
+			appendBuilderCall(desc, "UpdateDefault", "time", selector("time", "Now"))
+		}
+	}
+}
+
+// unquote strips the SQL quotes of a string literal and reports whether the value was quoted.
+func unquote(v string) (string, bool) {
+	if len(v) < 2 {
+		return v, false
+	}
+	switch q := v[0]; {
+	case q == '\'' && v[len(v)-1] == '\'':
+		return strings.ReplaceAll(v[1:len(v)-1], "''", "'"), true
+	case q == '"' && v[len(v)-1] == '"':
+		return strings.ReplaceAll(v[1:len(v)-1], `""`, `"`), true
+	}
+	return v, false
+}
+
+// unsupportedType returns the error of a column whose type has no ent field, named by its raw type.
+func unsupportedType(column *schema.Column) error {
+	typ := column.Type.Raw
+	if typ == "" {
+		typ = fmt.Sprintf("%T", column.Type.Type)
+	}
+	return fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
+}
+
+func selector(x, sel string) ast.Expr {
+	return &ast.SelectorExpr{X: ast.NewIdent(x), Sel: ast.NewIdent(sel)}
+}
+
+func call(fun ast.Expr, args ...ast.Expr) ast.Expr {
+	return &ast.CallExpr{Fun: fun, Args: args}
+}
+
+func strLit(s string) ast.Expr {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
@@ -0,0 +1,145 @@
+package entimport
+
+import (
+	"go/ast"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+)
+
+// isEdgeSchemaTable reports if the table joins two tables and holds extra columns, e.g. created_at or role.
+// Such tables are imported as ent edge schemas: either their primary key is made of their two foreign keys,
+// or they have their own primary key and a unique index on their two foreign keys.
+func isEdgeSchemaTable(table *schema.Table) bool {
+	if table.PrimaryKey == nil || len(table.ForeignKeys) != 2 {
+		return false
+	}
+	columns := make([]*schema.Column, 0, len(table.ForeignKeys))
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) != 1 || fk.Columns[0].Type.Null {
+			return false
+		}
+		columns = append(columns, fk.Columns[0])
+	}
+	if columns[0].Name == columns[1].Name {
+		return false
+	}
+	switch len(table.PrimaryKey.Parts) {
+	case 2:
+		return len(table.Columns) > 2 && coversColumns(table.PrimaryKey, columns)
+	case 1:
+		for _, idx := range table.Indexes {
+			if idx.Unique && coversColumns(idx, columns) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCompositeEdgeSchema reports if the edge schema table is identified by its two foreign keys.
+func isCompositeEdgeSchema(table *schema.Table) bool {
+	return isEdgeSchemaTable(table) && len(table.PrimaryKey.Parts) == 2
+}
+
+// coversColumns reports if the index is made of exactly the given columns, in any order.
+func coversColumns(idx *schema.Index, columns []*schema.Column) bool {
+	if len(idx.Parts) != len(columns) {
+		return false
+	}
+	for _, part := range idx.Parts {
+		if part.C == nil {
+			return false
+		}
+		found := false
+		for _, c := range columns {
+			if part.C.Name == c.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// compositeID returns the field.ID annotation of an edge schema identified by its two foreign keys.
+// ent requires the fields in the order of the M2M edge, see upsertEdgeSchema.
+func compositeID(table *schema.Table) schemaAnnotation {
+	args := make([]ast.Expr, 0, len(table.ForeignKeys))
+	for _, fk := range table.ForeignKeys {
+		args = append(args, strLit(fk.Columns[0].Name))
+	}
+	return schemaAnnotation{
+		expr:    call(selector("field", "ID"), args...),
+		imports: []string{"entgo.io/ent/schema/field"},
+	}
+}
+
+// upsertEdgeSchema adds the edges of an edge schema: a required unique edge to each of the joined types
+// on the edge schema, and an M2M edge going through the edge schema between the joined types.
+// If one of the joined tables is not imported or goes to another package, the table is kept as a regular node.
+func (i *ImportOptions) upsertEdgeSchema(mutations map[string]schemast.Mutator, table *schema.Table) {
+	node, ok := mutations[i.tableKey(table)].(*schemast.UpsertSchema)
+	if !ok {
+		return
+	}
+	fkA, fkB := table.ForeignKeys[0], table.ForeignKeys[1]
+	nodeA, okA := mutations[i.tableKey(fkA.RefTable)].(*schemast.UpsertSchema)
+	nodeB, okB := mutations[i.tableKey(fkB.RefTable)].(*schemast.UpsertSchema)
+	if !okA || !okB || !i.samePackage(table, fkA.RefTable, fkB.RefTable) {
+		i.upsertOneToX(mutations, table)
+		return
+	}
+	for _, fk := range table.ForeignKeys {
+		column := fk.Columns[0].Name
+		for _, f := range node.Fields {
+			if d := f.Descriptor(); d.Name == column {
+				d.Optional = false
+			}
+		}
+		name := strings.TrimSuffix(column, "_id")
+		if name == column || name == "" {
+			name = column + "_edge"
+		}
+		e := edge.To(name, ent.Schema.Type).Unique().Required().Field(column)
+		e.Descriptor().Type = i.typeName(i.tableKey(fk.RefTable))
+		node.Edges = append(node.Edges, e)
+	}
+	opts := relOptions{
+		refName:   i.tableName(nodeB.Name),
+		recursive: nodeA == nodeB,
+	}
+	fromA := i.entEdge(i.tableName(nodeA.Name), nodeA.Name, nodeB, from, opts)
+	toB := i.entEdge(i.tableName(nodeB.Name), nodeB.Name, nodeA, to, opts)
+	through := i.tableName(node.Name)
+	if opts.recursive {
+		// Both edge fields reference the same type, so they are given to ent by the storage key.
+		toB.Descriptor().StorageKey = &edge.StorageKey{
+			Table:   table.Name,
+			Columns: []string{fkA.Columns[0].Name, fkB.Columns[0].Name},
+		}
+		setThrough(toB, "child_"+through, node.Name)
+		setThrough(fromA, "parent_"+through, node.Name)
+	} else {
+		setThrough(toB, through, node.Name)
+		setThrough(fromA, through, node.Name)
+	}
+	nodeA.Edges = append(nodeA.Edges, toB)
+	nodeB.Edges = append(nodeB.Edges, fromA)
+}
+
+// setThrough makes an M2M edge go through the given edge schema. schemast does not print
+// the Through option, so it is added as a builder call of the edge.
+func setThrough(e ent.Edge, name, typeName string) {
+	desc := e.Descriptor()
+	desc.Through = &struct{ N, T string }{N: name, T: typeName}
+	desc.Annotations = append(desc.Annotations,
+		newBuilderCalls("Through", "", strLit(name), selector(typeName, "Type")))
+}
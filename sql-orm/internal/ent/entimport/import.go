@@ -3,6 +3,8 @@ package entimport
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"ariga.io/atlas/sql/schema"
 
@@ -14,6 +16,10 @@ import (
 	"entgo.io/ent/schema/edge"
 
 	"github.com/go-openapi/inflect"
+
+	"github.com/tx7do/go-wind-toolkit/generators/annotation"
+	"github.com/tx7do/go-wind-toolkit/generators/tablefilter"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/relation"
 )
 
 // NewImport calls the relevant data source importer based on a given dialect.
@@ -26,6 +32,9 @@ func NewImport(opts ...ImportOption) (SchemaImporter, error) {
 	for _, apply := range opts {
 		apply(i)
 	}
+	if i.filter, err = tablefilter.New(i.tables, i.excludedTables); err != nil {
+		return nil, err
+	}
 
 	switch i.driver.Dialect {
 	case dialect.MySQL:
@@ -40,6 +49,12 @@ func NewImport(opts ...ImportOption) (SchemaImporter, error) {
 			return nil, err
 		}
 
+	case dialect.SQLite:
+		si, err = NewSQLite(i)
+		if err != nil {
+			return nil, err
+		}
+
 	case "text":
 		si, err = NewText(i)
 		if err != nil {
@@ -59,9 +74,29 @@ func WriteSchema(mutations []schemast.Mutator, opts ...ImportOption) error {
 	for _, apply := range opts {
 		apply(i)
 	}
+	// The schemas imported with WithSchemaPackages are written to their own package.
+	packages := schemaPackages(mutations)
+	if len(packages) == 0 {
+		return writeSchema(mutations, i)
+	}
+	for _, pkg := range sortedKeys(packages) {
+		o := *i
+		o.schemaPath = filepath.Join(i.schemaPath, pkg)
+		if err := writeSchema(packages[pkg], &o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSchema writes the ent schema of the given mutators to the schema path of the options.
+func writeSchema(mutations []schemast.Mutator, i *ImportOptions) error {
 	if err := os.MkdirAll(i.schemaPath, os.ModePerm); err != nil {
 		return err
 	}
+	if i.merge {
+		return mergeSchema(mutations, i)
+	}
 	ctx, err := schemast.Load(i.schemaPath)
 	if err != nil {
 		return err
@@ -73,7 +108,7 @@ func WriteSchema(mutations []schemast.Mutator, opts ...ImportOption) error {
 }
 
 // entEdge creates an edge based on the given params and direction.
-func entEdge(nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir edgeDir, opts relOptions) (e ent.Edge) {
+func (i *ImportOptions) entEdge(nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir edgeDir, opts relOptions) (e ent.Edge) {
 	var desc *edge.Descriptor
 	switch dir {
 	case to:
@@ -81,7 +116,7 @@ func entEdge(nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir
 		desc = e.Descriptor()
 		if opts.uniqueEdgeToChild {
 			desc.Unique = true
-			desc.Name = inflect.Singularize(nodeName)
+			desc.Name = i.naming.Singular(nodeName)
 		}
 		if opts.recursive {
 			desc.Name = "child_" + desc.Name
@@ -91,7 +126,7 @@ func entEdge(nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir
 		desc = e.Descriptor()
 		if opts.uniqueEdgeFromParent {
 			desc.Unique = true
-			desc.Name = inflect.Singularize(nodeName)
+			desc.Name = i.naming.Singular(nodeName)
 		}
 		if opts.edgeField != "" {
 			setEdgeField(e, opts, currentNode)
@@ -100,7 +135,7 @@ func entEdge(nodeName, nodeType string, currentNode *schemast.UpsertSchema, dir
 		// because there can be multiple references from one node to another.
 		refName := opts.refName
 		if opts.uniqueEdgeToChild {
-			refName = inflect.Singularize(refName)
+			refName = i.naming.Singular(refName)
 		}
 		desc.RefName = refName
 		if opts.recursive {
@@ -129,64 +164,103 @@ func setEdgeField(e ent.Edge, opts relOptions, childNode *schemast.UpsertSchema)
 				f.Descriptor().Name = edgeField
 			}
 		}
+		for _, idx := range childNode.Indexes {
+			for i, name := range idx.Descriptor().Fields {
+				if name == opts.edgeField {
+					idx.Descriptor().Fields[i] = edgeField
+				}
+			}
+		}
 	}
 	e.Descriptor().Field = edgeField
 }
 
 // upsertRelation takes 2 nodes and created the edges between them.
-func upsertRelation(nodeA *schemast.UpsertSchema, nodeB *schemast.UpsertSchema, opts relOptions) {
-	tableA := tableName(nodeA.Name)
-	tableB := tableName(nodeB.Name)
-	fromA := entEdge(tableA, nodeA.Name, nodeB, from, opts)
-	toB := entEdge(tableB, nodeB.Name, nodeA, to, opts)
+func (i *ImportOptions) upsertRelation(nodeA *schemast.UpsertSchema, nodeB *schemast.UpsertSchema, opts relOptions) {
+	tableA := i.tableName(nodeA.Name)
+	tableB := i.tableName(nodeB.Name)
+	fromA := i.entEdge(tableA, nodeA.Name, nodeB, from, opts)
+	toB := i.entEdge(tableB, nodeB.Name, nodeA, to, opts)
 	nodeA.Edges = append(nodeA.Edges, toB)
 	nodeB.Edges = append(nodeB.Edges, fromA)
 }
 
 // upsertManyToMany handles the creation of M2M relations.
-func upsertManyToMany(mutations map[string]schemast.Mutator, table *schema.Table) error {
+func (i *ImportOptions) upsertManyToMany(mutations map[string]schemast.Mutator, table *schema.Table) error {
 	tableA := table.ForeignKeys[0].RefTable
 	tableB := table.ForeignKeys[1].RefTable
 	var opts relOptions
-	if tableA.Name == tableB.Name {
+	if i.tableKey(tableA) == i.tableKey(tableB) {
 		opts.recursive = true
 	}
-	nodeA, ok := mutations[tableA.Name].(*schemast.UpsertSchema)
+	nodeA, ok := mutations[i.tableKey(tableA)].(*schemast.UpsertSchema)
 	if !ok {
 		return joinTableErr
 	}
-	nodeB, ok := mutations[tableB.Name].(*schemast.UpsertSchema)
+	nodeB, ok := mutations[i.tableKey(tableB)].(*schemast.UpsertSchema)
 	if !ok {
 		return joinTableErr
 	}
-	opts.refName = tableName(nodeB.Name)
-	upsertRelation(nodeA, nodeB, opts)
+	// ent edges do not cross the packages, so the tables are left unrelated.
+	if !i.samePackage(tableA, tableB) {
+		return nil
+	}
+	opts.refName = i.tableName(nodeB.Name)
+	i.upsertRelation(nodeA, nodeB, opts)
 	return nil
 }
 
-// Note: at this moment ent doesn't support fields on m2m relations.
-func isJoinTable(table *schema.Table) bool {
-	if table.PrimaryKey == nil || len(table.PrimaryKey.Parts) != 2 || len(table.ForeignKeys) != 2 {
-		return false
+// typeName returns the name of the ent type generated for a table.
+func (i *ImportOptions) typeName(tableName string) string {
+	return i.naming.TypeName(tableName)
+}
+
+// multiSchema reports if the tables of several database schemas are imported.
+func (i *ImportOptions) multiSchema() bool {
+	return i.driver != nil && len(i.driver.Schemas) > 1
+}
+
+// tableKey returns the name identifying a table in the import: the table name, qualified with
+// its schema when several schemas are imported, e.g. "auth.users".
+func (i *ImportOptions) tableKey(t *schema.Table) string {
+	return i.qualify(t.Schema, t.Name)
+}
+
+// qualify qualifies the name of a table or a view with its schema when several schemas are imported.
+func (i *ImportOptions) qualify(s *schema.Schema, name string) string {
+	if i.multiSchema() && s != nil {
+		return s.Name + "." + name
 	}
-	// Make sure that the foreign key columns exactly match primary key column.
-	for _, fk := range table.ForeignKeys {
-		if len(fk.Columns) != 1 {
-			return false
-		}
-		if fk.Columns[0] != table.PrimaryKey.Parts[0].C && fk.Columns[0] != table.PrimaryKey.Parts[1].C {
+	return name
+}
+
+// packageName returns the package of the ent schema generated for a table: the name of its database
+// schema when the schemas are written to their own packages, or an empty string.
+func (i *ImportOptions) packageName(t *schema.Table) string {
+	return i.schemaPackage(t.Schema)
+}
+
+// schemaPackage returns the package of the ent schemas generated for a database schema.
+func (i *ImportOptions) schemaPackage(s *schema.Schema) string {
+	if !i.schemaPackages || !i.multiSchema() || s == nil {
+		return ""
+	}
+	return strings.ToLower(s.Name)
+}
+
+// samePackage reports if the ent schemas of the given tables are written to the same package.
+func (i *ImportOptions) samePackage(tables ...*schema.Table) bool {
+	for _, t := range tables[1:] {
+		if i.packageName(t) != i.packageName(tables[0]) {
 			return false
 		}
 	}
 	return true
 }
 
-func typeName(tableName string) string {
-	return inflect.Camelize(inflect.Singularize(tableName))
-}
-
-func tableName(typeName string) string {
-	return inflect.Underscore(inflect.Pluralize(typeName))
+// tableName returns the plural name of an ent type, used to name the edges to this type.
+func (i *ImportOptions) tableName(typeName string) string {
+	return i.naming.Plural(inflect.Underscore(typeName))
 }
 
 // resolvePrimaryKey returns the primary key as an ent field for a given table.
@@ -200,17 +274,30 @@ func resolvePrimaryKey(field fieldFunc, table *schema.Table) (f ent.Field, err e
 	if f, err = field(table.PrimaryKey.Parts[0].C); err != nil {
 		return nil, err
 	}
-	if d := f.Descriptor(); d.Name != "id" {
+	d := f.Descriptor()
+	if d.Name != "id" {
 		d.StorageKey = d.Name
 		d.Name = "id"
 	}
+	// SQLite reports INTEGER PRIMARY KEY columns as nullable, but an ID is never optional.
+	d.Optional = false
 	return f, nil
 }
 
+// hasField reports if the node has a field stored in the given column.
+func hasField(node *schemast.UpsertSchema, column string) bool {
+	for _, f := range node.Fields {
+		if d := f.Descriptor(); d.Name == column || d.StorageKey == column {
+			return true
+		}
+	}
+	return false
+}
+
 // upsertNode handles the creation of a node from a given table.
-func upsertNode(field fieldFunc, table *schema.Table) (*schemast.UpsertSchema, error) {
+func (i *ImportOptions) upsertNode(field fieldFunc, table *schema.Table) (*schemast.UpsertSchema, error) {
 	upsert := &schemast.UpsertSchema{
-		Name: typeName(table.Name),
+		Name: i.typeName(i.tableKey(table)),
 	}
 
 	//fmt.Println("UpsertSchema", table.Name, table.Attrs)
@@ -247,15 +334,18 @@ func upsertNode(field fieldFunc, table *schema.Table) (*schemast.UpsertSchema, e
 	if collation != "" {
 		annotation.Collation = collation
 	}
-	if tableName(table.Name) != table.Name {
-		annotation.Table = tableName(table.Name)
-	} else {
-		annotation.Table = table.Name
-	}
+	annotation.Table = table.Name
 
 	upsert.Annotations = []entschema.Annotation{
 		annotation,
 	}
+	if i.multiSchema() && table.Schema != nil {
+		// The schema of the table is set with entsql.Schema, that requires the sql/schemaconfig feature of ent.
+		upsert.Annotations = append(upsert.Annotations, schemaAnnotation{
+			expr:    call(selector("entsql", "Schema"), strLit(table.Schema.Name)),
+			imports: []string{"entgo.io/ent/dialect/entsql"},
+		})
+	}
 
 	if comment != "" {
 		//upsert.Annotations = append(upsert.Annotations, entschema.Comment(comment))
@@ -265,90 +355,165 @@ func upsertNode(field fieldFunc, table *schema.Table) (*schemast.UpsertSchema, e
 	for _, f := range upsert.Fields {
 		fields[f.Descriptor().StorageKey] = f
 	}
-	pk, err := resolvePrimaryKey(field, table)
-	if err != nil {
-		return nil, err
-	}
-	if _, ok := fields[pk.Descriptor().StorageKey]; !ok {
-		fields[pk.Descriptor().StorageKey] = pk
-		upsert.Fields = append(upsert.Fields, pk)
+	leftOut := make(map[string]bool)
+	composite := isCompositeEdgeSchema(table)
+	if composite {
+		// The edge schema is identified by its edge fields, that are added with the other columns.
+		upsert.Annotations = append(upsert.Annotations, compositeID(table))
+	} else {
+		pk, err := resolvePrimaryKey(field, table)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := fields[table.PrimaryKey.Parts[0].C.Name]; !ok {
+			fields[table.PrimaryKey.Parts[0].C.Name] = pk
+			upsert.Fields = append(upsert.Fields, pk)
+		}
 	}
 	for _, column := range table.Columns {
-		if table.PrimaryKey != nil &&
+		if !composite &&
+			table.PrimaryKey != nil &&
 			len(table.PrimaryKey.Parts) != 0 &&
 			table.PrimaryKey.Parts[0].C.Name == column.Name {
 			continue
 		}
 		fld, err := field(column)
 		if err != nil {
-			return nil, err
+			// The column is left out, and so is its foreign key.
+			if err = i.fail(i.tableKey(table), column.Name, err); err != nil {
+				return nil, err
+			}
+			leftOut[column.Name] = true
+			continue
 		}
 		if _, ok := fields[column.Name]; !ok {
 			fields[column.Name] = fld
 			upsert.Fields = append(upsert.Fields, fld)
 		}
 	}
-	for _, index := range table.Indexes {
-		if index.Unique && len(index.Parts) == 1 {
-			fields[index.Parts[0].C.Name].Descriptor().Unique = true
-		}
-	}
+	upsertIndexes(upsert, table, fields)
+	applyChecks(table, fields)
 	for _, fk := range table.ForeignKeys {
 		for _, column := range fk.Columns {
 			// FK / Reference column
 			fld, ok := fields[column.Name]
+			if !ok && leftOut[column.Name] {
+				continue
+			}
 			if !ok {
 				return nil, fmt.Errorf("foreign key for column: %q doesn't exist in referenced table", column.Name)
 			}
 			fld.Descriptor().Optional = true
 		}
 	}
-	return upsert, err
+	return upsert, nil
 }
 
 // applyColumnAttributes adds column attributes to a given ent field.
-func applyColumnAttributes(f ent.Field, col *schema.Column) {
+// The exact column type is preserved for the given dialect, if it's not empty.
+// The annotations of the column comment are applied and stripped from the field comment.
+func applyColumnAttributes(dlct string, f ent.Field, col *schema.Column) {
 	desc := f.Descriptor()
 	desc.Optional = col.Type.Null
+	var annots annotation.Annotations
 	for _, attr := range col.Attrs {
 		if a, ok := attr.(*schema.Comment); ok {
-			desc.Comment = a.Text
+			annots = annotation.Parse(a.Text)
+			desc.Comment = annots.Comment
 		}
 	}
+	applyEnumAnnotation(dlct, desc, col, annots)
+	applySchemaType(dlct, desc, col)
+	applyDefault(desc, col)
+	applyUpdateDefault(desc, col)
+	applyCommentAnnotations(desc, annots)
+	applyValidators(dlct, desc, col)
 }
 
 // schemaMutations is in charge of creating all the schema mutations needed for an ent schema.
-func schemaMutations(field fieldFunc, tables []*schema.Table) ([]schemast.Mutator, error) {
-	mutations := make(map[string]schemast.Mutator)
-	joinTables := make(map[string]*schema.Table)
+// The views are imported as read-only ent views, without edges nor mixins.
+func (i *ImportOptions) schemaMutations(field fieldFunc, tables []*schema.Table, views []*schema.View) ([]schemast.Mutator, error) {
+	var (
+		mutations  = make(map[string]schemast.Mutator)
+		joinTables = make(map[string]*schema.Table)
+		packages   = make(map[string]string)
+		typeTables = make(map[string]string)
+	)
 	for _, table := range tables {
-		if isJoinTable(table) {
-			joinTables[table.Name] = table
+		key := i.tableKey(table)
+		if relation.IsJoinTable(table) {
+			joinTables[key] = table
 			continue
 		}
-		node, err := upsertNode(field, table)
+		i.diagnostics().table = key
+		node, err := i.upsertNode(field, table)
 		if err != nil {
-			return nil, fmt.Errorf("entimport: issue with table %v: %w", table.Name, err)
+			if err = i.fail(key, "", err); err != nil {
+				return nil, fmt.Errorf("entimport: issue with table %v: %w", key, err)
+			}
+			continue
+		}
+		pkg := i.packageName(table)
+		if other, ok := typeTables[pkg+"."+node.Name]; ok {
+			if err = i.fail(key, "", typeCollision(other, key, node.Name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		typeTables[pkg+"."+node.Name] = key
+		mutations[key] = node
+		packages[key] = pkg
+	}
+	viewMutations := make(map[string]schemast.Mutator, len(views))
+	for _, view := range views {
+		key := i.qualify(view.Schema, view.Name)
+		i.diagnostics().table = key
+		node, err := i.upsertView(field, view)
+		if err != nil {
+			if err = i.fail(key, "", err); err != nil {
+				return nil, fmt.Errorf("entimport: issue with view %v: %w", key, err)
+			}
+			continue
 		}
-		mutations[table.Name] = node
+		pkg := i.schemaPackage(view.Schema)
+		if other, ok := typeTables[pkg+"."+node.Name]; ok {
+			if err = i.fail(key, "", typeCollision(other, key, node.Name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		typeTables[pkg+"."+node.Name] = key
+		viewMutations[key] = node
+		packages[key] = pkg
 	}
 
 	for _, table := range tables {
-		if t, ok := joinTables[table.Name]; ok {
-			err := upsertManyToMany(mutations, t)
-			if err != nil {
-				return nil, err
+		if t, ok := joinTables[i.tableKey(table)]; ok {
+			if err := i.upsertManyToMany(mutations, t); err != nil {
+				if err = i.fail(i.tableKey(t), "", err); err != nil {
+					return nil, err
+				}
 			}
 			continue
 		}
-		upsertOneToX(mutations, table)
+		if isEdgeSchemaTable(table) {
+			i.upsertEdgeSchema(mutations, table)
+			continue
+		}
+		i.upsertOneToX(mutations, table)
 	}
-
-	ml := make([]schemast.Mutator, 0, len(mutations))
-	for _, mutator := range mutations {
-		ml = append(ml, mutator)
+	applyMixins(mutations, i.mixins)
+	for key, node := range viewMutations {
+		mutations[key] = node
 	}
-	return ml, nil
+	i.diagnostics().Tables = len(mutations)
+
+	return upsertSchemas(mutations, packages), nil
+}
+
+// typeCollision returns the error of two tables imported as the same type.
+func typeCollision(table, other, typeName string) error {
+	return fmt.Errorf("entimport: tables %v and %v are both imported as %v, rename one of them with the type_names of the naming rules", table, other, typeName)
 }
 
 // O2O Two Types - Child Table has a unique reference (FK) to Parent table
@@ -356,45 +521,43 @@ func schemaMutations(field fieldFunc, tables []*schema.Table) ([]schemast.Mutato
 // O2M (The "Many" side, keeps a reference to the "One" side).
 // O2M Two Types - Parent has a non-unique reference to Child, and Child has a unique back-reference to Parent
 // O2M Same Type - Parent has a non-unique reference to Child, and Child doesn't have a back-reference to Parent.
-func upsertOneToX(mutations map[string]schemast.Mutator, table *schema.Table) {
+func (i *ImportOptions) upsertOneToX(mutations map[string]schemast.Mutator, table *schema.Table) {
 	if table.ForeignKeys == nil {
 		return
 	}
-	idxes := make(map[string]*schema.Index)
-	for _, idx := range table.Indexes {
-		if len(idx.Parts) != 1 {
-			continue
-		}
-		idxes[idx.Parts[0].C.Name] = idx
-	}
-	for _, fk := range table.ForeignKeys {
-		if len(fk.Columns) != 1 {
-			continue
-		}
+	for _, fk := range relation.ForeignKeys(table) {
 		parent := fk.RefTable
 		child := table
-		colName := fk.Columns[0].Name
+		colName := fk.Column
 		opts := relOptions{
 			uniqueEdgeFromParent: true,
-			refName:              tableName(child.Name),
+			refName:              i.tableName(i.typeName(i.tableKey(child))),
 			edgeField:            colName,
 		}
-		if child.Name == parent.Name {
+		if i.tableKey(child) == i.tableKey(parent) {
 			opts.recursive = true
 		}
-		idx, ok := idxes[colName]
-		if ok && idx.Unique {
+		if fk.Unique {
 			opts.uniqueEdgeToChild = true
 		}
 		// If at least one table in the relation does not exist, there is no point to create it.
-		parentNode, ok := mutations[parent.Name].(*schemast.UpsertSchema)
+		childNode, ok := mutations[i.tableKey(child)].(*schemast.UpsertSchema)
 		if !ok {
 			return
 		}
-		childNode, ok := mutations[child.Name].(*schemast.UpsertSchema)
+		parentNode, ok := mutations[i.tableKey(parent)].(*schemast.UpsertSchema)
 		if !ok {
-			return
+			i.skipf(i.tableKey(child), "edge of the foreign key %v, table %v is not imported", colName, i.tableKey(parent))
+			continue
+		}
+		if !hasField(childNode, colName) {
+			i.skipf(i.tableKey(child), "edge of the foreign key %v, the column is not imported", colName)
+			continue
+		}
+		// ent edges do not cross the packages, so the foreign key is kept as a plain field.
+		if !i.samePackage(parent, child) {
+			continue
 		}
-		upsertRelation(parentNode, childNode, opts)
+		i.upsertRelation(parentNode, childNode, opts)
 	}
 }
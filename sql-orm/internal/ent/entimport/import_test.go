@@ -283,7 +283,7 @@ func MockMySQLTableFieldsWithUniqueIndexes() *schema.Schema {
 				},
 				Indexes: []*schema.Index{
 					{
-						Name:   "users_age_uindex",
+						Name:   "age",
 						Unique: true,
 						Attrs: []schema.Attr{
 							&mysql.IndexType{
@@ -347,7 +347,7 @@ func MockMySQLTableFieldsWithUniqueIndexes() *schema.Schema {
 	}
 	table.Indexes = []*schema.Index{
 		{
-			Name:   "users_age_uindex",
+			Name:   "age",
 			Unique: true,
 			Attrs: []schema.Attr{
 				&mysql.IndexType{
@@ -395,7 +395,7 @@ func MockMySQLMultiTableFields() *schema.Schema {
 					Null: false,
 				}, Indexes: []*schema.Index{
 					{
-						Name:   "users_age_uindex",
+						Name:   "age",
 						Unique: true,
 						Attrs: []schema.Attr{
 							&mysql.IndexType{
@@ -469,7 +469,7 @@ func MockMySQLMultiTableFields() *schema.Schema {
 	}
 	tableA.Indexes = []*schema.Index{
 		{
-			Name:   "users_age_uindex",
+			Name:   "age",
 			Unique: true,
 			Attrs: []schema.Attr{
 				&mysql.IndexType{
@@ -586,7 +586,7 @@ func MockMySQLNonDefaultPrimaryKey() *schema.Schema {
 	}
 	table.Indexes = []*schema.Index{
 		{
-			Name:   "users_last_name_uindex",
+			Name:   "last_name",
 			Unique: true,
 			Attrs: []schema.Attr{
 				&mysql.IndexType{
@@ -609,6 +609,201 @@ func MockMySQLNonDefaultPrimaryKey() *schema.Schema {
 	}
 }
 
+func MockMySQLTableFieldsWithDefaults() *schema.Schema {
+	id := &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{
+			Type: &schema.IntegerType{T: "bigint"},
+			Raw:  "bigint",
+		},
+		Attrs: []schema.Attr{
+			&mysql.AutoIncrement{},
+		},
+	}
+	table := &schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			id,
+			{
+				Name: "status",
+				Type: &schema.ColumnType{
+					Type: &schema.StringType{T: "varchar", Size: 20},
+					Raw:  "varchar(20)",
+				},
+				Default: &schema.Literal{V: "'active'"},
+			},
+			{
+				Name: "balance",
+				Type: &schema.ColumnType{
+					Type: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2},
+					Raw:  "decimal(10,2)",
+				},
+				Default: &schema.Literal{V: "0.50"},
+			},
+			{
+				Name: "verified",
+				Type: &schema.ColumnType{
+					Type: &schema.BoolType{T: "tinyint(1)"},
+					Raw:  "tinyint(1)",
+				},
+				Default: &schema.Literal{V: "0"},
+			},
+			{
+				Name: "created_at",
+				Type: &schema.ColumnType{
+					Type: &schema.TimeType{T: "datetime"},
+					Raw:  "datetime",
+				},
+				Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP"},
+			},
+			{
+				Name: "updated_at",
+				Type: &schema.ColumnType{
+					Type: &schema.TimeType{T: "timestamp"},
+					Raw:  "timestamp",
+				},
+				Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP"},
+				Attrs: []schema.Attr{
+					&mysql.OnUpdate{A: "CURRENT_TIMESTAMP"},
+				},
+			},
+		},
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:  "PRI",
+		Parts: []*schema.IndexPart{{C: id}},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{table},
+	}
+}
+
+func MockMySQLTableFieldsWithIndexes() *schema.Schema {
+	id := &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{
+			Type: &schema.IntegerType{T: "bigint"},
+			Raw:  "bigint",
+		},
+		Attrs: []schema.Attr{
+			&mysql.AutoIncrement{},
+		},
+	}
+	title := &schema.Column{
+		Name: "title",
+		Type: &schema.ColumnType{
+			Type: &schema.StringType{T: "varchar", Size: 255},
+			Raw:  "varchar(255)",
+		},
+	}
+	body := &schema.Column{
+		Name: "body",
+		Type: &schema.ColumnType{
+			Type: &schema.StringType{T: "varchar", Size: 255},
+			Raw:  "varchar(255)",
+		},
+	}
+	table := &schema.Table{
+		Name:    "posts",
+		Columns: []*schema.Column{id, title, body},
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:  "PRI",
+		Parts: []*schema.IndexPart{{C: id}},
+	}
+	table.Indexes = []*schema.Index{
+		{
+			Name:  "title_prefix",
+			Table: table,
+			Attrs: []schema.Attr{
+				&mysql.IndexType{T: "BTREE"},
+			},
+			Parts: []*schema.IndexPart{
+				{SeqNo: 1, C: title, Attrs: []schema.Attr{&mysql.SubPart{Len: 10}}},
+			},
+		},
+		{
+			Name:  "posts_title_body",
+			Table: table,
+			Attrs: []schema.Attr{
+				&mysql.IndexType{T: "FULLTEXT"},
+			},
+			Parts: []*schema.IndexPart{
+				{SeqNo: 1, C: title},
+				{SeqNo: 2, C: body},
+			},
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{table},
+	}
+}
+
+func MockMySQLTableFieldsWithNamedUniqueIndex() *schema.Schema {
+	id := &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{
+			Type: &schema.IntegerType{T: "bigint"},
+			Raw:  "bigint",
+		},
+		Attrs: []schema.Attr{
+			&mysql.AutoIncrement{},
+		},
+	}
+	email := &schema.Column{
+		Name: "email",
+		Type: &schema.ColumnType{
+			Type: &schema.StringType{T: "varchar", Size: 255},
+			Raw:  "varchar(255)",
+		},
+	}
+	nickname := &schema.Column{
+		Name: "nickname",
+		Type: &schema.ColumnType{
+			Type: &schema.StringType{T: "varchar", Size: 255},
+			Raw:  "varchar(255)",
+		},
+	}
+	table := &schema.Table{
+		Name:    "users",
+		Columns: []*schema.Column{id, email, nickname},
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:  "PRI",
+		Parts: []*schema.IndexPart{{C: id}},
+	}
+	table.Indexes = []*schema.Index{
+		{
+			Name:   "uk_email",
+			Unique: true,
+			Table:  table,
+			Attrs: []schema.Attr{
+				&mysql.IndexType{T: "BTREE"},
+			},
+			Parts: []*schema.IndexPart{
+				{SeqNo: 1, C: email},
+			},
+		},
+		{
+			Name:   "nickname",
+			Unique: true,
+			Table:  table,
+			Attrs: []schema.Attr{
+				&mysql.IndexType{T: "BTREE"},
+			},
+			Parts: []*schema.IndexPart{
+				{SeqNo: 1, C: nickname},
+			},
+		},
+	}
+	return &schema.Schema{
+		Name:   "test",
+		Tables: []*schema.Table{table},
+	}
+}
+
 func MockMySQLM2MTwoTypes() *schema.Schema {
 	tableA := &schema.Table{
 		Name: "groups",
@@ -2104,7 +2299,7 @@ func MockPostgresTableFieldsWithUniqueIndexes() *schema.Schema {
 	}
 	table.Indexes = []*schema.Index{
 		{
-			Name:   "users_age_uindex",
+			Name:   "users_age_key",
 			Unique: true,
 			Parts: []*schema.IndexPart{
 				{
@@ -2176,7 +2371,7 @@ func MockPostgresMultiTableFields() *schema.Schema {
 	}
 	tableA.Indexes = []*schema.Index{
 		{
-			Name:   "users_age_uindex",
+			Name:   "users_age_key",
 			Unique: true,
 			Table:  tableA,
 			Parts: []*schema.IndexPart{
@@ -2300,7 +2495,7 @@ func MockPostgresNonDefaultPrimaryKey() *schema.Schema {
 	}
 	table.Indexes = []*schema.Index{
 		{
-			Name:   "users_last_name_uindex",
+			Name:   "users_last_name_key",
 			Unique: true,
 			Table:  table,
 			Parts: []*schema.IndexPart{
@@ -2379,7 +2574,7 @@ func MockPostgresNonDefaultPrimaryKeyWithIndexes() *schema.Schema {
 	}
 	table.Indexes = []*schema.Index{
 		{
-			Name:   "users_my_id_uindex",
+			Name:   "users_my_id_key",
 			Unique: true,
 			Table:  table,
 			Parts: []*schema.IndexPart{
@@ -2425,6 +2620,228 @@ func MockPostgresNonDefaultPrimaryKeyWithIndexes() *schema.Schema {
 	}
 }
 
+func MockPostgresTableFieldsWithDefaults() *schema.Schema {
+	id := &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{
+			Type: &postgres.UUIDType{T: "uuid"},
+			Raw:  "uuid",
+		},
+		Default: &schema.RawExpr{X: "gen_random_uuid()"},
+	}
+	table := &schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			id,
+			{
+				Name: "name",
+				Type: &schema.ColumnType{
+					Type: &schema.StringType{T: "character varying", Size: 100},
+					Raw:  "character varying",
+				},
+				Default: &schema.RawExpr{X: "'anonymous'::character varying"},
+			},
+			{
+				Name: "score",
+				Type: &schema.ColumnType{
+					Type: &schema.IntegerType{T: "integer"},
+					Raw:  "integer",
+				},
+				Default: &schema.RawExpr{X: "0"},
+			},
+			{
+				Name: "created_at",
+				Type: &schema.ColumnType{
+					Type: &schema.TimeType{T: "timestamp without time zone"},
+					Raw:  "timestamp without time zone",
+				},
+				Default: &schema.RawExpr{X: "now()"},
+			},
+			{
+				Name: "expires_at",
+				Type: &schema.ColumnType{
+					Type: &schema.TimeType{T: "timestamp with time zone"},
+					Raw:  "timestamp with time zone",
+					Null: true,
+				},
+				Default: &schema.RawExpr{X: "(now() + '1 day'::interval)"},
+			},
+		},
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:  "users_pkey",
+		Parts: []*schema.IndexPart{{C: id}},
+	}
+	return &schema.Schema{
+		Name:   "public",
+		Tables: []*schema.Table{table},
+	}
+}
+
+func MockPostgresTableFieldsWithChecks() *schema.Schema {
+	id := &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{
+			Type: &schema.IntegerType{T: "bigint"},
+			Raw:  "bigint",
+		},
+	}
+	table := &schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			id,
+			{
+				Name: "age",
+				Type: &schema.ColumnType{
+					Type: &schema.IntegerType{T: "integer"},
+					Raw:  "integer",
+				},
+			},
+			{
+				Name: "rating",
+				Type: &schema.ColumnType{
+					Type: &schema.IntegerType{T: "smallint"},
+					Raw:  "smallint",
+					Null: true,
+				},
+			},
+			{
+				Name: "code",
+				Type: &schema.ColumnType{
+					Type: &schema.StringType{T: "character varying", Size: 20},
+					Raw:  "character varying",
+				},
+			},
+			{
+				Name: "nickname",
+				Type: &schema.ColumnType{
+					Type: &schema.StringType{T: "text"},
+					Raw:  "text",
+					Null: true,
+				},
+			},
+		},
+		Attrs: []schema.Attr{
+			&schema.Check{Name: "users_age_check", Expr: "((age > 0))"},
+			&schema.Check{Name: "users_rating_check", Expr: "((rating >= 1) AND (rating <= 5))"},
+			&schema.Check{Name: "users_code_check", Expr: "((code)::text ~ '^[a-z]+$'::text)"},
+			&schema.Check{Name: "users_nickname_check", Expr: "(length(nickname) <= 32)"},
+		},
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:  "users_pkey",
+		Parts: []*schema.IndexPart{{C: id}},
+	}
+	return &schema.Schema{
+		Name:   "public",
+		Tables: []*schema.Table{table},
+	}
+}
+
+func MockPostgresTableFieldsWithSpecificTypes() *schema.Schema {
+	id := &schema.Column{
+		Name: "id",
+		Type: &schema.ColumnType{
+			Type: &schema.IntegerType{T: "bigint"},
+			Raw:  "bigint",
+		},
+	}
+	table := &schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			id,
+			{
+				Name: "tags",
+				Type: &schema.ColumnType{
+					Type: &postgres.ArrayType{Type: &schema.StringType{T: "text"}, T: "text[]"},
+					Raw:  "text[]",
+				},
+				Default: &schema.RawExpr{X: "'{}'::text[]"},
+			},
+			{
+				Name: "scores",
+				Type: &schema.ColumnType{
+					Type: &postgres.ArrayType{Type: &schema.IntegerType{T: "integer"}, T: "integer[]"},
+					Raw:  "integer[]",
+					Null: true,
+				},
+			},
+			{
+				Name: "ip",
+				Type: &schema.ColumnType{
+					Type: &postgres.NetworkType{T: "inet"},
+					Raw:  "inet",
+					Null: true,
+				},
+			},
+			{
+				Name: "ttl",
+				Type: &schema.ColumnType{
+					Type: &postgres.IntervalType{T: "interval"},
+					Raw:  "interval",
+					Null: true,
+				},
+			},
+			{
+				Name: "search",
+				Type: &schema.ColumnType{
+					Type: &postgres.TextSearchType{T: "tsvector"},
+					Raw:  "tsvector",
+					Null: true,
+				},
+			},
+			{
+				Name: "attrs",
+				Type: &schema.ColumnType{
+					Type: &postgres.UserDefinedType{T: "hstore"},
+					Raw:  "hstore",
+					Null: true,
+				},
+			},
+			{
+				Name: "price",
+				Type: &schema.ColumnType{
+					Type: &postgres.CurrencyType{T: "money"},
+					Raw:  "money",
+					Null: true,
+				},
+			},
+			{
+				Name: "flags",
+				Type: &schema.ColumnType{
+					Type: &postgres.BitType{T: "bit", Len: 8},
+					Raw:  "bit",
+					Null: true,
+				},
+			},
+			{
+				Name: "email",
+				Type: &schema.ColumnType{
+					Type: &postgres.DomainType{T: "email", Type: &schema.StringType{T: "text"}},
+					Raw:  "email",
+					Null: true,
+				},
+			},
+			{
+				Name: "address",
+				Type: &schema.ColumnType{
+					Type: &postgres.UserDefinedType{T: "address", C: "c"},
+					Raw:  "address",
+					Null: true,
+				},
+			},
+		},
+	}
+	table.PrimaryKey = &schema.Index{
+		Name:  "users_pkey",
+		Parts: []*schema.IndexPart{{C: id}},
+	}
+	return &schema.Schema{
+		Name:   "public",
+		Tables: []*schema.Table{table},
+	}
+}
+
 func MockPostgresM2MTwoTypes() *schema.Schema {
 	tableA := &schema.Table{
 		Name: "groups",
@@ -3363,7 +3780,7 @@ func MockPostgresO2OBidirectional() *schema.Schema {
 	}
 	table.Indexes = []*schema.Index{
 		{
-			Name:   "users_user_spouse_key",
+			Name:   "user_user_spouse_key",
 			Unique: true,
 			Table:  table,
 			Attrs: []schema.Attr{
@@ -3,15 +3,21 @@ package entimport
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"os"
-	"strings"
+
+	"github.com/tx7do/go-wind-toolkit/generators/datasource"
 
 	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
 )
 
 // Importer imports the schema from the database specified by the DSN and writes it to the schemaPath.
-func Importer(ctx context.Context, dsn, schemaPath *string, includeTables, excludeTables []string) error {
+// The given options are applied after the tables, driver and DSN options.
+//
+// The tables and columns that cannot be imported are recorded in the report of WithReport, and the
+// other tables are written. The report is printed to the writer of WithReportOutput at the end of the
+// import. With WithStrict, the import fails on the first of them instead.
+func Importer(ctx context.Context, dsn, schemaPath *string, includeTables, excludeTables []string, opts ...ImportOption) error {
 	if schemaPath == nil {
 		return errors.New("entimport: schema path is nil")
 	}
@@ -19,14 +25,20 @@ func Importer(ctx context.Context, dsn, schemaPath *string, includeTables, exclu
 		return errors.New("entimport: dsn is nil")
 	}
 
+	o := &ImportOptions{}
+	for _, apply := range opts {
+		apply(o)
+	}
+	report := o.diagnostics()
+
 	_ = os.MkdirAll(*schemaPath, os.ModePerm)
 
 	// Normalize the DSN to ensure it has a valid scheme
-	normalizedDSN := normalizeDSN(*dsn)
+	normalizedDSN := datasource.Normalize(*dsn)
 
 	drv, err := mux.Default.OpenImport(normalizedDSN)
 	if err != nil {
-		log.Fatalf("entimport: failed to create import driver - %v", err)
+		return fmt.Errorf("entimport: failed to create import driver - %w", err)
 	}
 	defer func(drv *mux.ImportDriver) {
 		//if drv != nil {
@@ -37,43 +49,25 @@ func Importer(ctx context.Context, dsn, schemaPath *string, includeTables, exclu
 		//}
 	}(drv)
 
-	i, err := NewImport(
+	i, err := NewImport(append([]ImportOption{
 		WithTables(includeTables),
 		WithExcludedTables(excludeTables),
 		WithDriver(drv),
 		WithSchemaPath(normalizedDSN),
-	)
+	}, append(opts, WithReport(report))...)...)
 	if err != nil {
-		log.Fatalf("entimport: create importer failed: %v", err)
+		return fmt.Errorf("entimport: create importer failed: %w", err)
 	}
 
 	mutations, err := i.SchemaMutations(ctx)
 	if err != nil {
-		log.Fatalf("entimport: schema import failed - %v", err)
+		return fmt.Errorf("entimport: schema import failed - %w", err)
 	}
 
-	if err = WriteSchema(mutations, WithSchemaPath(*schemaPath)); err != nil {
-		log.Fatalf("entimport: schema writing failed - %v", err)
+	if err = WriteSchema(mutations, append(opts, WithSchemaPath(*schemaPath))...); err != nil {
+		return fmt.Errorf("entimport: schema writing failed - %w", err)
 	}
 
+	report.Print(o.reportOutput)
 	return nil
 }
-
-// normalizeDSN normalizes the DSN to ensure it has a valid scheme.
-// If the input is a file path, it will be prefixed with "file://".
-// If it's SQL text content, it will be prefixed with "text://".
-// If it already has a scheme (mysql://, postgres://, etc.), it's returned as-is.
-func normalizeDSN(dsn string) string {
-	// Check if it already has a scheme
-	if strings.Contains(dsn, "://") {
-		return dsn
-	}
-
-	// Check if it's a file path
-	if _, err := os.Stat(dsn); err == nil {
-		return "file://" + dsn
-	}
-
-	// Treat it as SQL text content
-	return "text://" + dsn
-}
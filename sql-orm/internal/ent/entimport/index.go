@@ -0,0 +1,169 @@
+package entimport
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	"entgo.io/ent/schema/index"
+)
+
+// upsertIndexes adds the indexes of a table to the given node. Unique single-column indexes named as ent
+// names them are kept as unique fields, and all the other indexes are added to the Indexes method of the
+// schema, so that their name is kept.
+func upsertIndexes(upsert *schemast.UpsertSchema, table *schema.Table, fields map[string]ent.Field) {
+	for _, idx := range table.Indexes {
+		if len(idx.Parts) == 0 || isImplicitIndex(table, idx) {
+			continue
+		}
+		names := make([]string, 0, len(idx.Parts))
+		for _, part := range idx.Parts {
+			// Expression indexes cannot be described by ent.
+			if part.C == nil {
+				names = nil
+				break
+			}
+			f, ok := fields[part.C.Name]
+			if !ok {
+				names = nil
+				break
+			}
+			names = append(names, f.Descriptor().Name)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		annotations := indexAnnotations(idx)
+		if idx.Unique && len(names) == 1 && len(annotations) == 0 && isUniqueFieldIndex(table, idx) {
+			desc := fields[idx.Parts[0].C.Name].Descriptor()
+			desc.Unique = true
+			// ent rejects a unique field having a default value, so the default is kept in the database.
+			databaseDefault(desc)
+			continue
+		}
+		i := index.Fields(names...)
+		if idx.Unique {
+			i = i.Unique()
+		}
+		if idx.Name != "" && idx.Name != defaultIndexName(table, idx) && !isAutoIndex(idx) {
+			i = i.StorageKey(idx.Name)
+		}
+		if len(annotations) > 0 {
+			desc := i.Descriptor()
+			desc.Annotations = append(desc.Annotations, newBuilderCalls("Annotations", "entgo.io/ent/dialect/entsql", annotations...))
+		}
+		upsert.Indexes = append(upsert.Indexes, i)
+	}
+}
+
+// indexAnnotations returns the entsql annotations describing the index attributes
+// that are exposed by Atlas, e.g. partial, prefix and descending indexes.
+func indexAnnotations(idx *schema.Index) (annotations []ast.Expr) {
+	var (
+		where string
+		typ   string
+		desc  []ast.Expr
+	)
+	for _, attr := range idx.Attrs {
+		switch a := attr.(type) {
+		case *postgres.IndexPredicate:
+			where = a.P
+		case *sqlite.IndexPredicate:
+			where = a.P
+		case *postgres.IndexType:
+			if typ == "" {
+				typ = a.T
+			}
+		case *mysql.IndexType:
+			if typ == "" {
+				typ = a.T
+			}
+		}
+	}
+	if where != "" {
+		annotations = append(annotations, call(selector("entsql", "IndexWhere"), strLit(where)))
+	}
+	if typ != "" && !strings.EqualFold(typ, "BTREE") {
+		annotations = append(annotations, call(selector("entsql", "IndexType"), strLit(strings.ToUpper(typ))))
+	}
+	for _, part := range idx.Parts {
+		if part.C == nil {
+			continue
+		}
+		if part.Desc {
+			desc = append(desc, strLit(part.C.Name))
+		}
+		for _, attr := range part.Attrs {
+			if a, ok := attr.(*mysql.SubPart); ok && a.Len > 0 {
+				annotations = append(annotations, call(selector("entsql", "PrefixColumn"), strLit(part.C.Name), intLit(a.Len)))
+			}
+		}
+	}
+	if len(desc) > 0 {
+		annotations = append(annotations, call(selector("entsql", "DescColumns"), desc...))
+	}
+	return annotations
+}
+
+// isImplicitIndex reports if the index was created by the database for a foreign key,
+// as MySQL does for every foreign key that is not covered by an index.
+func isImplicitIndex(table *schema.Table, idx *schema.Index) bool {
+	if idx.Unique || len(idx.Parts) != 1 || idx.Parts[0].C == nil {
+		return false
+	}
+	for _, fk := range table.ForeignKeys {
+		if fk.Symbol == idx.Name && len(fk.Columns) == 1 && fk.Columns[0].Name == idx.Parts[0].C.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isAutoIndex reports if the index name was generated by the database, e.g. for a SQLite UNIQUE constraint.
+func isAutoIndex(idx *schema.Index) bool {
+	return strings.HasPrefix(idx.Name, "sqlite_autoindex_")
+}
+
+// isUniqueFieldIndex reports if the name of a unique single-column index is the one of the index created
+// for a unique field: the column name in MySQL, "<table>_<column>_key" in PostgreSQL and SQLite, or a name
+// generated by the database. A number is appended to the name when it is taken, e.g. "email_2".
+func isUniqueFieldIndex(table *schema.Table, idx *schema.Index) bool {
+	if idx.Name == "" || isAutoIndex(idx) {
+		return true
+	}
+	column := idx.Parts[0].C.Name
+	for _, name := range []string{column, table.Name + "_" + column + "_key"} {
+		suffix, ok := strings.CutPrefix(idx.Name, name)
+		if !ok {
+			continue
+		}
+		if suffix = strings.TrimPrefix(suffix, "_"); suffix == "" {
+			return true
+		}
+		if _, err := strconv.Atoi(suffix); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultIndexName returns the name ent generates for an index on the given columns.
+func defaultIndexName(table *schema.Table, idx *schema.Index) string {
+	columns := make([]string, 0, len(idx.Parts))
+	for _, part := range idx.Parts {
+		columns = append(columns, part.C.Name)
+	}
+	return strings.ToLower(table.Name + "_" + strings.Join(columns, "_"))
+}
+
+func intLit(i int) ast.Expr {
+	return &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(i)}
+}
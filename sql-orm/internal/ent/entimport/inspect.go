@@ -0,0 +1,45 @@
+package entimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/tx7do/go-wind-toolkit/generators/datasource"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
+)
+
+// InspectTables inspects the tables of the database specified by the DSN, as the import does: the DSN
+// is normalized the same way, and the tables are selected by the included and excluded tables. It returns
+// the tables with their foreign keys and the dialect of the database, for the importers of the other ORMs.
+func InspectTables(ctx context.Context, dsn string, includeTables, excludeTables []string) ([]*schema.Table, string, error) {
+	normalizedDSN := datasource.Normalize(dsn)
+	drv, err := mux.Default.OpenImport(normalizedDSN)
+	if err != nil {
+		return nil, "", fmt.Errorf("entimport: failed to create import driver - %w", err)
+	}
+	if drv.Closer != nil {
+		defer drv.Close()
+	}
+
+	si, err := NewImport(
+		WithTables(includeTables),
+		WithExcludedTables(excludeTables),
+		WithDriver(drv),
+		WithSchemaPath(normalizedDSN),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("entimport: create importer failed: %w", err)
+	}
+	in, ok := si.(inspector)
+	if !ok {
+		return nil, "", errors.New("entimport: the importer does not inspect tables")
+	}
+	tables, _, err := in.inspect(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("entimport: schema inspection failed - %w", err)
+	}
+	return tables, drv.Dialect, nil
+}
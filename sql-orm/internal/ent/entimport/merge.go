@@ -0,0 +1,470 @@
+package entimport
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"entgo.io/contrib/schemast"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// The schema methods whose items are merged, and the kind of item they return.
+var mergedMethods = []struct {
+	method string
+	kind   string
+}{
+	{"Fields", "field"},
+	{"Edges", "edge"},
+	{"Indexes", "index"},
+}
+
+type (
+	// schemaFile is a parsed schema file, kept with its source so that it can be edited in place.
+	schemaFile struct {
+		path  string
+		src   []byte
+		fset  *token.FileSet
+		file  *ast.File
+		edits []sourceEdit
+		// imports holds the packages referenced by the inserted code.
+		imports map[string]string
+	}
+
+	// sourceEdit replaces the source between two offsets.
+	sourceEdit struct {
+		start, end int
+		text       string
+	}
+
+	// mergeReport collects the changes found in each schema file.
+	mergeReport map[string][]string
+)
+
+// mergeSchema writes the imported schema next to the existing one. The schemas and the fields, edges
+// and indexes that are new are added, while the existing code is left as is: the removed and changed
+// columns are only reported, so that hand-written code is never lost.
+func mergeSchema(mutations []schemast.Mutator, i *ImportOptions) error {
+	tmp, err := os.MkdirTemp("", "entimport-merge-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if err = writeSchema(mutations, &ImportOptions{schemaPath: tmp}); err != nil {
+		return err
+	}
+	generated, err := parseSchemaFiles(tmp)
+	if err != nil {
+		return err
+	}
+	existing, err := parseSchemaFiles(i.schemaPath)
+	if err != nil {
+		return err
+	}
+	var (
+		report   = make(mergeReport)
+		genTypes = schemaTypes(generated)
+		oldTypes = schemaTypes(existing)
+	)
+	for _, name := range sortedKeys(genTypes) {
+		gen := genTypes[name]
+		old, ok := oldTypes[name]
+		if !ok {
+			path := filepath.Join(i.schemaPath, filepath.Base(gen.path))
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("entimport: cannot add schema %s, file %s already exists", name, path)
+			}
+			if err := os.WriteFile(path, gen.src, 0o644); err != nil {
+				return err
+			}
+			report.add(filepath.Base(path), "+ schema "+name)
+			continue
+		}
+		mergeType(report, name, old, gen)
+	}
+	for _, name := range sortedKeys(oldTypes) {
+		if _, ok := genTypes[name]; !ok {
+			report.add(filepath.Base(oldTypes[name].path), "- schema "+name+" (table not found, kept)")
+		}
+	}
+	for _, f := range existing {
+		if err := f.write(); err != nil {
+			return err
+		}
+		if _, ok := report[filepath.Base(f.path)]; !ok && len(f.typeNames()) > 0 {
+			report[filepath.Base(f.path)] = nil
+		}
+	}
+	report.print(i.mergeOutput)
+	return nil
+}
+
+// mergeType adds the new items of the generated schema type to the existing one.
+func mergeType(report mergeReport, name string, old, gen *schemaFile) {
+	file := filepath.Base(old.path)
+	for _, m := range mergedMethods {
+		genFn, genLit := methodItems(gen.file, name, m.method)
+		if genFn == nil {
+			continue
+		}
+		oldFn, oldLit := methodItems(old.file, name, m.method)
+		switch {
+		case oldFn == nil:
+			// The method is missing, e.g. the first index of a table.
+			old.insert(len(old.src), "\n"+gen.text(genFn)+"\n", gen)
+			for _, item := range litItems(genLit) {
+				report.add(file, fmt.Sprintf("+ %s %s", m.kind, itemKey(m.kind, item)))
+			}
+			continue
+		case oldLit == nil:
+			if genLit != nil && len(genLit.Elts) > 0 {
+				report.add(file, fmt.Sprintf("! %s.%s() does not return a list, skipped", name, m.method))
+			}
+			continue
+		}
+		var (
+			oldItems = make(map[string]ast.Expr)
+			genItems = make(map[string]bool)
+			added    []string
+		)
+		for _, item := range oldLit.Elts {
+			oldItems[itemKey(m.kind, item)] = item
+		}
+		for _, item := range litItems(genLit) {
+			key := itemKey(m.kind, item)
+			genItems[key] = true
+			oldItem, ok := oldItems[key]
+			switch {
+			case !ok:
+				added = append(added, gen.text(item))
+				report.add(file, fmt.Sprintf("+ %s %s", m.kind, key))
+			case m.kind != "edge":
+				if diff := callsDiff(old, oldItem, gen, item); len(diff) > 0 {
+					report.add(file, fmt.Sprintf("~ %s %s: %s", m.kind, key, strings.Join(diff, ", ")))
+				}
+			}
+		}
+		for _, item := range oldLit.Elts {
+			if key := itemKey(m.kind, item); !genItems[key] {
+				report.add(file, fmt.Sprintf("- %s %s (not found, kept)", m.kind, key))
+			}
+		}
+		if len(added) > 0 {
+			old.appendItems(oldLit, added, gen, genLit)
+		}
+	}
+}
+
+// callsDiff reports the builder calls of the generated field that are missing or have other
+// arguments in the existing one. Calls that were added by hand are not reported.
+func callsDiff(old *schemaFile, oldItem ast.Expr, gen *schemaFile, genItem ast.Expr) []string {
+	oldChain, genChain := callChain(oldItem), callChain(genItem)
+	if len(oldChain) == 0 || len(genChain) == 0 {
+		return nil
+	}
+	var diff []string
+	if o, g := old.text(oldChain[0].Fun), gen.text(genChain[0].Fun); o != g {
+		diff = append(diff, o+" -> "+g)
+	}
+	oldCalls := make(map[string][]string)
+	for _, c := range oldChain[1:] {
+		method := c.Fun.(*ast.SelectorExpr).Sel.Name
+		oldCalls[method] = append(oldCalls[method], old.callText(c))
+	}
+	for _, c := range genChain[1:] {
+		method, text := c.Fun.(*ast.SelectorExpr).Sel.Name, gen.callText(c)
+		calls, ok := oldCalls[method]
+		switch {
+		case !ok:
+			diff = append(diff, "+"+text)
+		case !contains(calls, text):
+			diff = append(diff, strings.Join(calls, ".")+" -> "+text)
+		}
+	}
+	return diff
+}
+
+// callChain returns the calls of a builder expression, starting with the innermost one.
+func callChain(expr ast.Expr) []*ast.CallExpr {
+	var chain []*ast.CallExpr
+	for {
+		c, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
+		chain = append([]*ast.CallExpr{c}, chain...)
+		sel, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return chain
+		}
+		expr = inner
+	}
+}
+
+// itemKey identifies an item of the schema: fields and edges by their name and indexes by their columns.
+func itemKey(kind string, item ast.Expr) string {
+	chain := callChain(item)
+	if len(chain) == 0 {
+		return fmt.Sprintf("<%T>", item)
+	}
+	var names []string
+	for _, arg := range chain[0].Args {
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			continue
+		}
+		if names = append(names, s); kind != "index" {
+			break
+		}
+	}
+	if kind == "index" {
+		return "(" + strings.Join(names, ", ") + ")"
+	}
+	return strings.Join(names, "")
+}
+
+// methodItems returns the declaration of a schema method, and the literal it returns when the method
+// consists of a single return statement.
+func methodItems(file *ast.File, typeName, method string) (*ast.FuncDecl, *ast.CompositeLit) {
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != method || fd.Recv == nil || len(fd.Recv.List) != 1 || fd.Body == nil {
+			continue
+		}
+		typ := fd.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if id, ok := typ.(*ast.Ident); !ok || id.Name != typeName {
+			continue
+		}
+		if len(fd.Body.List) != 1 {
+			return fd, nil
+		}
+		ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			return fd, nil
+		}
+		switch x := ret.Results[0].(type) {
+		case *ast.CompositeLit:
+			return fd, x
+		case *ast.Ident:
+			if x.Name == "nil" {
+				// An empty list, filled by replacing nil with a literal.
+				return fd, &ast.CompositeLit{Lbrace: x.Pos(), Rbrace: x.End()}
+			}
+		}
+		return fd, nil
+	}
+	return nil, nil
+}
+
+func litItems(lit *ast.CompositeLit) []ast.Expr {
+	if lit == nil {
+		return nil
+	}
+	return lit.Elts
+}
+
+// parseSchemaFiles parses the Go files of a schema directory.
+func parseSchemaFiles(dir string) ([]*schemaFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*schemaFile
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".go" || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &schemaFile{path: path, src: src, fset: fset, file: file})
+	}
+	return files, nil
+}
+
+// schemaTypes maps the ent schema types to the files declaring them.
+func schemaTypes(files []*schemaFile) map[string]*schemaFile {
+	types := make(map[string]*schemaFile)
+	for _, f := range files {
+		for _, name := range f.typeNames() {
+			types[name] = f
+		}
+	}
+	return types
+}
+
+// typeNames returns the types of the file that embed ent.Schema or ent.View.
+func (f *schemaFile) typeNames() []string {
+	var names []string
+	for _, decl := range f.file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, fd := range st.Fields.List {
+				if sel, ok := fd.Type.(*ast.SelectorExpr); ok && len(fd.Names) == 0 && (sel.Sel.Name == "Schema" || sel.Sel.Name == "View") {
+					if x, ok := sel.X.(*ast.Ident); ok && x.Name == "ent" {
+						names = append(names, ts.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// offset returns the offset of a position in the file source.
+func (f *schemaFile) offset(pos token.Pos) int {
+	return f.fset.Position(pos).Offset
+}
+
+// text returns the source of a node.
+func (f *schemaFile) text(n ast.Node) string {
+	return string(f.src[f.offset(n.Pos()):f.offset(n.End())])
+}
+
+// callText returns the source of a chained call, without its receiver, e.g. MaxLen(255).
+func (f *schemaFile) callText(c *ast.CallExpr) string {
+	sel := c.Fun.(*ast.SelectorExpr)
+	return string(f.src[f.offset(sel.Sel.Pos()):f.offset(c.End())])
+}
+
+// insert adds the given code, copied from the generated file, at an offset.
+func (f *schemaFile) insert(offset int, text string, from *schemaFile) {
+	f.replace(offset, offset, text, from)
+}
+
+// replace replaces the source between two offsets with code copied from the generated file.
+func (f *schemaFile) replace(start, end int, text string, from *schemaFile) {
+	f.edits = append(f.edits, sourceEdit{start: start, end: end, text: text})
+	for _, spec := range from.file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if strings.Contains(text, name+".") {
+			if f.imports == nil {
+				f.imports = make(map[string]string)
+			}
+			f.imports[path] = ""
+			if spec.Name != nil {
+				f.imports[path] = spec.Name.Name
+			}
+		}
+	}
+}
+
+// appendItems adds the given items at the end of a list.
+func (f *schemaFile) appendItems(lit *ast.CompositeLit, items []string, from *schemaFile, fromLit *ast.CompositeLit) {
+	text := strings.Join(items, ",\n") + ","
+	switch {
+	case lit.Type == nil:
+		// A nil list has no items, so it is replaced with the generated one.
+		f.replace(f.offset(lit.Lbrace), f.offset(lit.Rbrace), from.text(fromLit), from)
+	case len(lit.Elts) == 0:
+		f.insert(f.offset(lit.Lbrace)+1, "\n"+text+"\n", from)
+	case f.fset.Position(lit.Lbrace).Line == f.fset.Position(lit.Rbrace).Line:
+		f.insert(f.offset(lit.Elts[len(lit.Elts)-1].End()), ", "+strings.Join(items, ", "), from)
+	default:
+		f.insert(f.offset(lit.Elts[len(lit.Elts)-1].End()), ",\n"+strings.Join(items, ",\n"), from)
+	}
+}
+
+// write applies the edits to the file and formats it.
+func (f *schemaFile) write() error {
+	if len(f.edits) == 0 {
+		return nil
+	}
+	sort.SliceStable(f.edits, func(i, j int) bool { return f.edits[i].start > f.edits[j].start })
+	src := f.src
+	for _, e := range f.edits {
+		src = append(src[:e.start:e.start], append([]byte(e.text), src[e.end:]...)...)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.path, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("entimport: merging %s: %w", f.path, err)
+	}
+	for path, name := range f.imports {
+		astutil.AddNamedImport(fset, file, name, path)
+	}
+	var buf bytes.Buffer
+	if err = format.Node(&buf, fset, file); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, buf.Bytes(), 0o644)
+}
+
+func (r mergeReport) add(file, change string) {
+	r[file] = append(r[file], change)
+}
+
+// print writes the changes of each file.
+func (r mergeReport) print(w io.Writer) {
+	if w == nil {
+		return
+	}
+	for _, file := range sortedKeys(r) {
+		if len(r[file]) == 0 {
+			fmt.Fprintf(w, "%s: no changes\n", file)
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", file)
+		for _, change := range r[file] {
+			fmt.Fprintf(w, "  %s\n", change)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
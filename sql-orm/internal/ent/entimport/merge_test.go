@@ -0,0 +1,93 @@
+package entimport_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
+)
+
+func TestMerge(t *testing.T) {
+	r := require.New(t)
+	schemas := createTempDir(t)
+	importSchema := func(out *bytes.Buffer, ddl ...string) {
+		drv, err := mux.Default.OpenImport("sqlite://" + createSQLiteDB(t, ddl...))
+		r.NoError(err)
+		importer, err := entimport.NewImport(entimport.WithDriver(drv))
+		r.NoError(err)
+		mutations, err := importer.SchemaMutations(context.Background())
+		r.NoError(err)
+		err = entimport.WriteSchema(mutations,
+			entimport.WithSchemaPath(schemas),
+			entimport.WithMerge(true),
+			entimport.WithMergeOutput(out),
+		)
+		r.NoError(err)
+	}
+
+	var out bytes.Buffer
+	importSchema(&out,
+		"CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, name varchar(100) NOT NULL, age integer NOT NULL)",
+	)
+	r.Equal("user.go:\n  + schema User\n", out.String())
+
+	// Edit the generated schema by hand.
+	path := filepath.Join(schemas, "user.go")
+	buf, err := os.ReadFile(path)
+	r.NoError(err)
+	src := strings.Replace(string(buf), `.MaxLen(100)`, `.MaxLen(100).Immutable()`, 1)
+	src += `
+// Hooks of the User.
+func (User) Hooks() []ent.Hook {
+	return nil
+}
+`
+	r.NoError(os.WriteFile(path, []byte(src), 0o644))
+
+	out.Reset()
+	importSchema(&out,
+		"CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, name varchar(50) NOT NULL, email text NULL)",
+		"CREATE INDEX users_email ON users (email)",
+		"CREATE TABLE pets (id integer PRIMARY KEY AUTOINCREMENT, owner_id integer NOT NULL REFERENCES users (id))",
+	)
+	r.Equal(`pet.go:
+  + schema Pet
+user.go:
+  ~ field name: SchemaType(map[string]string{"sqlite3": "varchar(100)"}) -> SchemaType(map[string]string{"sqlite3": "varchar(50)"}), MaxLen(100) -> MaxLen(50)
+  + field email
+  - field age (not found, kept)
+  + edge pets
+  + index (email)
+`, out.String())
+
+	buf, err = os.ReadFile(path)
+	r.NoError(err)
+	merged := string(buf)
+	r.Contains(merged, `MaxLen(100).Immutable().NotEmpty()`)
+	r.Contains(merged, `field.Int("age")`)
+	r.Contains(merged, `field.String("email").Optional()`)
+	r.Contains(merged, `edge.To("pets", Pet.Type)`)
+	r.Contains(merged, `index.Fields("email")`)
+	r.Contains(merged, "// Hooks of the User.\nfunc (User) Hooks() []ent.Hook {")
+	r.Contains(merged, `"entgo.io/ent/schema/index"`)
+	r.FileExists(filepath.Join(schemas, "pet.go"))
+
+	// Nothing is changed by importing the same schema again.
+	out.Reset()
+	importSchema(&out,
+		"CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, name varchar(100) NOT NULL, age integer NOT NULL, email text NULL)",
+		"CREATE INDEX users_email ON users (email)",
+		"CREATE TABLE pets (id integer PRIMARY KEY AUTOINCREMENT, owner_id integer NOT NULL REFERENCES users (id))",
+	)
+	r.Equal("pet.go: no changes\nuser.go: no changes\n", out.String())
+	buf, err = os.ReadFile(path)
+	r.NoError(err)
+	r.Equal(merged, string(buf))
+}
@@ -0,0 +1,133 @@
+package entimport
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mixin describes an ent mixin declaring a group of common columns. The tables holding all the
+// columns of a group get a reference to the mixin instead of the fields of these columns.
+type Mixin struct {
+	// Type is the qualified Go type of the mixin, e.g. mixin.TimeAt.
+	Type string `yaml:"type"`
+	// Import is the import path of the package declaring the mixin.
+	Import string `yaml:"import"`
+	// Columns are the columns declared by the mixin.
+	Columns []string `yaml:"columns"`
+}
+
+// mixinCatalogue is the content of a mixin catalogue file.
+type mixinCatalogue struct {
+	Mixins []Mixin `yaml:"mixins"`
+}
+
+// LoadMixins reads a mixin catalogue from a YAML file, for example:
+//
+//	mixins:
+//	  - type: mixin.TimeAt
+//	    import: github.com/tx7do/go-utils/entgo/mixin
+//	    columns: [created_at, updated_at, deleted_at]
+//
+// The mixins are matched in the order of the catalogue, and a column is replaced by one mixin at most.
+func LoadMixins(path string) ([]Mixin, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("entimport: read mixin catalogue: %w", err)
+	}
+	var c mixinCatalogue
+	if err = yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("entimport: parse mixin catalogue %q: %w", path, err)
+	}
+	for i, m := range c.Mixins {
+		if pkg, name, ok := strings.Cut(m.Type, "."); !ok || pkg == "" || name == "" {
+			return nil, fmt.Errorf("entimport: mixin %d of %q: type %q must be qualified by its package", i, path, m.Type)
+		}
+		if m.Import == "" {
+			return nil, fmt.Errorf("entimport: mixin %d of %q: missing import path", i, path)
+		}
+		if len(m.Columns) == 0 {
+			return nil, fmt.Errorf("entimport: mixin %d of %q: missing columns", i, path)
+		}
+	}
+	return c.Mixins, nil
+}
+
+// schemaMixins is a schema annotation carrying the mixins of a schema. It is stripped before schemast
+// prints the schema and the Mixin method is added to the printed schema afterward.
+type schemaMixins []Mixin
+
+// Name implements the schema.Annotation interface.
+func (schemaMixins) Name() string {
+	return "EntImportMixins"
+}
+
+// applyMixins replaces the fields of the nodes that hold all the columns of a mixin with the mixin.
+// The fields that were renamed, e.g. for an edge, are not matched.
+func applyMixins(mutations map[string]schemast.Mutator, mixins []Mixin) {
+	if len(mixins) == 0 {
+		return
+	}
+	for _, m := range mutations {
+		node, ok := m.(*schemast.UpsertSchema)
+		if !ok {
+			continue
+		}
+		var applied schemaMixins
+		for _, mixin := range mixins {
+			if !hasColumns(node, mixin.Columns) {
+				continue
+			}
+			node.Fields = removeColumns(node, mixin.Columns)
+			applied = append(applied, mixin)
+		}
+		if len(applied) > 0 {
+			node.Annotations = append(node.Annotations, applied)
+		}
+	}
+}
+
+// hasColumns reports if the node has a field for each of the given columns.
+func hasColumns(node *schemast.UpsertSchema, columns []string) bool {
+	for _, c := range columns {
+		found := false
+		for _, f := range node.Fields {
+			if isColumnField(f.Descriptor().Name, f.Descriptor().StorageKey, c) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// removeColumns returns the fields of the node without the fields of the given columns.
+func removeColumns(node *schemast.UpsertSchema, columns []string) []ent.Field {
+	fields := node.Fields[:0:0]
+	for _, f := range node.Fields {
+		keep := true
+		for _, c := range columns {
+			if isColumnField(f.Descriptor().Name, f.Descriptor().StorageKey, c) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// isColumnField reports if a field with the given name and storage key is stored in the column.
+func isColumnField(name, storageKey, column string) bool {
+	return name == column && (storageKey == "" || storageKey == column)
+}
@@ -0,0 +1,45 @@
+package entimport_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
+)
+
+func TestLoadMixins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mixins.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+mixins:
+  - type: mixin.AutoIncrementId
+    import: github.com/tx7do/go-utils/entgo/mixin
+    columns: [id]
+  - type: mixin.TimeAt
+    import: github.com/tx7do/go-utils/entgo/mixin
+    columns:
+      - created_at
+      - updated_at
+      - deleted_at
+`), 0o644))
+	mixins, err := entimport.LoadMixins(path)
+	require.NoError(t, err)
+	require.Equal(t, []entimport.Mixin{
+		{Type: "mixin.AutoIncrementId", Import: "github.com/tx7do/go-utils/entgo/mixin", Columns: []string{"id"}},
+		{Type: "mixin.TimeAt", Import: "github.com/tx7do/go-utils/entgo/mixin", Columns: []string{"created_at", "updated_at", "deleted_at"}},
+	}, mixins)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+mixins:
+  - type: TimeAt
+    import: github.com/tx7do/go-utils/entgo/mixin
+    columns: [created_at]
+`), 0o644))
+	_, err = entimport.LoadMixins(path)
+	require.Error(t, err)
+
+	_, err = entimport.LoadMixins(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
@@ -3,13 +3,13 @@ package entimport
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/schema"
 
 	"entgo.io/contrib/schemast"
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/schema/field"
 )
 
@@ -35,28 +35,28 @@ func NewMySQL(i *ImportOptions) (*MySQL, error) {
 
 // SchemaMutations implements SchemaImporter.
 func (m *MySQL) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	tables, views, err := m.inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.schemaMutations(m.field, tables, views)
+}
+
+// inspect implements inspector.
+func (m *MySQL) inspect(ctx context.Context) ([]*schema.Table, []*schema.View, error) {
 	inspectOptions := &schema.InspectOptions{
-		Tables: m.tables,
+		Tables: m.filter.Names(),
 	}
 	s, err := m.driver.InspectSchema(ctx, m.driver.SchemaName, inspectOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	tables := s.Tables
-	if m.excludedTables != nil {
-		tables = nil
-		excludedTableNames := make(map[string]bool)
-		for _, t := range m.excludedTables {
-			excludedTableNames[t] = true
-		}
-		// filter out tables that are in excludedTables:
-		for _, t := range s.Tables {
-			if !excludedTableNames[t.Name] {
-				tables = append(tables, t)
-			}
-		}
+	tables := m.filterTables(s.Tables)
+	views, err := m.inspectViews(ctx, m.driver.SchemaName)
+	if err != nil {
+		return nil, nil, err
 	}
-	return schemaMutations(m.field, tables)
+	return tables, views, nil
 }
 
 func (m *MySQL) field(column *schema.Column) (f ent.Field, err error) {
@@ -67,7 +67,7 @@ func (m *MySQL) field(column *schema.Column) (f ent.Field, err error) {
 	case *schema.BoolType:
 		f = field.Bool(name)
 	case *schema.DecimalType:
-		f = field.Float(name)
+		f = m.decimalField(dialect.MySQL, typ, name)
 	case *schema.EnumType:
 		f = field.Enum(name).Values(typ.Values...)
 	case *schema.FloatType:
@@ -81,9 +81,9 @@ func (m *MySQL) field(column *schema.Column) (f ent.Field, err error) {
 	case *schema.TimeType:
 		f = field.Time(name)
 	default:
-		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
+		return nil, unsupportedType(column)
 	}
-	applyColumnAttributes(f, column)
+	applyColumnAttributes(dialect.MySQL, f, column)
 	return f, err
 }
 
@@ -3,12 +3,15 @@ package entimport
 import (
 	"context"
 	"errors"
+	"io"
 
 	"ariga.io/atlas/sql/schema"
 
 	"entgo.io/contrib/schemast"
 	"entgo.io/ent"
 
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+	"github.com/tx7do/go-wind-toolkit/generators/tablefilter"
 	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
 )
 
@@ -41,12 +44,30 @@ type (
 		SchemaMutations(context.Context) ([]schemast.Mutator, error)
 	}
 
+	// inspector is implemented by the SchemaImporters to inspect the imported tables and views.
+	inspector interface {
+		inspect(context.Context) ([]*schema.Table, []*schema.View, error)
+	}
+
+	// DecimalType selects the Go type of the fields imported from DECIMAL and NUMERIC columns.
+	DecimalType string
+
 	// ImportOptions are the options passed on to every SchemaImporter.
 	ImportOptions struct {
 		tables         []string
 		excludedTables []string
+		filter         *tablefilter.Filter
 		schemaPath     string
 		driver         *mux.ImportDriver
+		decimalType    DecimalType
+		mixins         []Mixin
+		naming         *naming.Strategy
+		merge          bool
+		mergeOutput    io.Writer
+		schemaPackages bool
+		strict         bool
+		report         *Report
+		reportOutput   io.Writer
 	}
 
 	// ImportOption allows for managing import configuration using functional options.
@@ -60,14 +81,15 @@ func WithSchemaPath(path string) ImportOption {
 	}
 }
 
-// WithTables limits the schema import to a set of given tables (by all tables are imported)
+// WithTables limits the schema import to a set of given tables (by all tables are imported). The tables
+// may be given by glob or regular expression patterns, see tablefilter.Filter.
 func WithTables(tables []string) ImportOption {
 	return func(i *ImportOptions) {
 		i.tables = tables
 	}
 }
 
-// WithExcludedTables supplies the set of tables to exclude.
+// WithExcludedTables supplies the set of tables to exclude, given by name or by pattern.
 func WithExcludedTables(tables []string) ImportOption {
 	return func(i *ImportOptions) {
 		i.excludedTables = tables
@@ -80,3 +102,70 @@ func WithDriver(drv *mux.ImportDriver) ImportOption {
 		i.driver = drv
 	}
 }
+
+// WithDecimalType sets the Go type of the fields imported from DECIMAL and NUMERIC columns.
+func WithDecimalType(t DecimalType) ImportOption {
+	return func(i *ImportOptions) {
+		i.decimalType = t
+	}
+}
+
+// WithMixins provides the mixins replacing the common columns of the tables.
+func WithMixins(mixins []Mixin) ImportOption {
+	return func(i *ImportOptions) {
+		i.mixins = mixins
+	}
+}
+
+// WithNaming sets the strategy naming the ent types and edges after the tables.
+func WithNaming(s *naming.Strategy) ImportOption {
+	return func(i *ImportOptions) {
+		i.naming = s
+	}
+}
+
+// WithMerge makes WriteSchema merge the imported schema into the existing one instead of rewriting it:
+// only the new schemas, fields, edges and indexes are added, and the hand-written code is kept.
+func WithMerge(merge bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.merge = merge
+	}
+}
+
+// WithMergeOutput sets the writer receiving the summary of the changes made to each schema file by a merge.
+func WithMergeOutput(w io.Writer) ImportOption {
+	return func(i *ImportOptions) {
+		i.mergeOutput = w
+	}
+}
+
+// WithSchemaPackages writes the tables of each database schema to their own package, named after the
+// schema, under the schema path. The foreign keys between the schemas are then kept as plain fields,
+// as ent edges do not cross the packages.
+func WithSchemaPackages(b bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.schemaPackages = b
+	}
+}
+
+// WithStrict makes the import fail on the first table or column that cannot be imported. By default,
+// the error is recorded in the report and the import goes on with the other tables.
+func WithStrict(b bool) ImportOption {
+	return func(i *ImportOptions) {
+		i.strict = b
+	}
+}
+
+// WithReport sets the report collecting the diagnostics of the import.
+func WithReport(r *Report) ImportOption {
+	return func(i *ImportOptions) {
+		i.report = r
+	}
+}
+
+// WithReportOutput sets the writer receiving the report printed by Importer at the end of the import.
+func WithReportOutput(w io.Writer) ImportOption {
+	return func(i *ImportOptions) {
+		i.reportOutput = w
+	}
+}
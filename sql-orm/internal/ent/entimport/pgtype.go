@@ -0,0 +1,132 @@
+package entimport
+
+import (
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent"
+)
+
+// pgtypePkg is the import path of the package providing the Go types of the PostgreSQL specific columns.
+const pgtypePkg = "github.com/jackc/pgtype"
+
+// reTypeModifiers matches the modifiers and the array brackets of a formatted type, e.g. (8) or [].
+var reTypeModifiers = regexp.MustCompile(`\(.*?\)|\[\d*\]`)
+
+// pgTypes maps the PostgreSQL types that have no ent field type to their pgtype type.
+var pgTypes = map[string]string{
+	"inet":        "Inet",
+	"cidr":        "CIDR",
+	"macaddr":     "Macaddr",
+	"macaddr8":    "Macaddr",
+	"interval":    "Interval",
+	"bit":         "Bit",
+	"bit varying": "Varbit",
+	"varbit":      "Varbit",
+	"hstore":      "Hstore",
+	"int4range":   "Int4range",
+	"int8range":   "Int8range",
+	"numrange":    "Numrange",
+	"tsrange":     "Tsrange",
+	"tstzrange":   "Tstzrange",
+	"daterange":   "Daterange",
+	"point":       "Point",
+	"line":        "Line",
+	"lseg":        "Lseg",
+	"box":         "Box",
+	"path":        "Path",
+	"polygon":     "Polygon",
+	"circle":      "Circle",
+}
+
+// pgArrayTypes maps the element type of the PostgreSQL arrays to their pgtype array type.
+var pgArrayTypes = map[string]string{
+	"text":                        "TextArray",
+	"character varying":           "VarcharArray",
+	"varchar":                     "VarcharArray",
+	"character":                   "BPCharArray",
+	"bpchar":                      "BPCharArray",
+	"smallint":                    "Int2Array",
+	"int2":                        "Int2Array",
+	"integer":                     "Int4Array",
+	"int":                         "Int4Array",
+	"int4":                        "Int4Array",
+	"bigint":                      "Int8Array",
+	"int8":                        "Int8Array",
+	"real":                        "Float4Array",
+	"float4":                      "Float4Array",
+	"double precision":            "Float8Array",
+	"float8":                      "Float8Array",
+	"numeric":                     "NumericArray",
+	"decimal":                     "NumericArray",
+	"boolean":                     "BoolArray",
+	"bool":                        "BoolArray",
+	"bytea":                       "ByteaArray",
+	"uuid":                        "UUIDArray",
+	"date":                        "DateArray",
+	"timestamp":                   "TimestampArray",
+	"timestamp without time zone": "TimestampArray",
+	"timestamptz":                 "TimestamptzArray",
+	"timestamp with time zone":    "TimestamptzArray",
+	"inet":                        "InetArray",
+	"cidr":                        "CIDRArray",
+	"macaddr":                     "MacaddrArray",
+	"jsonb":                       "JSONBArray",
+	"json":                        "JSONArray",
+	"hstore":                      "HstoreArray",
+}
+
+// convertArray converts an array column to a field.Other with the pgtype array of its elements. The text
+// and integer arrays are not imported as field.Strings or field.Ints: ent stores these as JSON, which the
+// native arrays do not accept.
+func (p *Postgres) convertArray(typ *postgres.ArrayType, name string) ent.Field {
+	if t, ok := pgArrayTypes[baseTypeName(typ.T)]; ok {
+		return otherField(name, pgtypePkg, t)
+	}
+	return p.fallbackField(typ.T, name)
+}
+
+// convertOther converts a column type that has no ent field type to a field.Other with its pgtype type.
+func (p *Postgres) convertOther(typ schema.Type, name string) ent.Field {
+	t, err := postgres.FormatType(typ)
+	if err != nil {
+		t = rawTypeName(typ)
+	}
+	if pt, ok := pgTypes[baseTypeName(t)]; ok {
+		return otherField(name, pgtypePkg, pt)
+	}
+	return p.fallbackField(t, name)
+}
+
+// fallbackField returns the field of a column that cannot be mapped. It is read and written
+// through pgtype.GenericText, so the import goes on and the field can be fixed by hand.
+func (p *Postgres) fallbackField(typ, name string) ent.Field {
+	p.warnf(name, "unsupported type %q, falling back to pgtype.GenericText", typ)
+	return otherField(name, pgtypePkg, "GenericText")
+}
+
+// baseTypeName strips the modifiers, the array brackets and the schema qualifier of a formatted type.
+func baseTypeName(t string) string {
+	t = strings.ToLower(strings.TrimSpace(reTypeModifiers.ReplaceAllString(t, "")))
+	if i := strings.LastIndexByte(t, '.'); i >= 0 {
+		t = t[i+1:]
+	}
+	return strings.Trim(strings.TrimSpace(t), `"`)
+}
+
+// rawTypeName returns the name of the types that cannot be formatted.
+func rawTypeName(typ schema.Type) string {
+	switch typ := typ.(type) {
+	case *postgres.UserDefinedType:
+		return typ.T
+	case *postgres.DomainType:
+		return typ.T
+	case *schema.UnsupportedType:
+		return typ.T
+	default:
+		return ""
+	}
+}
@@ -3,7 +3,6 @@ package entimport
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
@@ -37,39 +36,77 @@ func NewPostgreSQL(i *ImportOptions) (SchemaImporter, error) {
 
 // SchemaMutations implements SchemaImporter.
 func (p *Postgres) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	tables, views, err := p.inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.schemaMutations(p.field, tables, views)
+}
+
+// inspect implements inspector.
+func (p *Postgres) inspect(ctx context.Context) ([]*schema.Table, []*schema.View, error) {
+	if p.multiSchema() {
+		return p.inspectSchemas(ctx)
+	}
 	inspectOptions := &schema.InspectOptions{
-		Tables: p.tables,
+		Tables: p.filter.Names(),
 	}
 	s, err := p.driver.InspectSchema(ctx, p.driver.SchemaName, inspectOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	tables := s.Tables
-	if p.excludedTables != nil {
-		tables = nil
-		excludedTableNames := make(map[string]bool)
-		for _, t := range p.excludedTables {
-			excludedTableNames[t] = true
-		}
-		// filter out tables that are in excludedTables:
+	tables := p.filterTables(s.Tables)
+	views, err := p.inspectViews(ctx, p.driver.SchemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tables, views, nil
+}
+
+// inspectSchemas inspects the tables and the views of all the schemas of the driver. The included and
+// excluded tables are matched by their name, or by their name qualified with the schema, e.g. "auth.users".
+func (p *Postgres) inspectSchemas(ctx context.Context) ([]*schema.Table, []*schema.View, error) {
+	realm, err := p.driver.InspectRealm(ctx, &schema.InspectRealmOption{
+		Schemas: p.driver.Schemas,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		tables []*schema.Table
+		views  []*schema.View
+	)
+	for _, s := range realm.Schemas {
 		for _, t := range s.Tables {
-			if !excludedTableNames[t.Name] {
+			if p.importedTable(s.Name, t.Name) {
 				tables = append(tables, t)
 			}
 		}
+		v, err := p.inspectViews(ctx, s.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		views = append(views, v...)
 	}
-	return schemaMutations(p.field, tables)
+	return tables, views, nil
 }
 
 func (p *Postgres) field(column *schema.Column) (f ent.Field, err error) {
-	name := column.Name
-	switch typ := column.Type.Type.(type) {
+	f = p.convertType(column.Type.Type, column.Name)
+	applyColumnAttributes(dialect.Postgres, f, column)
+	return f, err
+}
+
+// convertType converts a column type to an ent field. The types that have no ent field type are
+// imported as field.Other with their pgtype type, and the unknown ones fall back to pgtype.GenericText.
+func (p *Postgres) convertType(typ schema.Type, name string) (f ent.Field) {
+	switch typ := typ.(type) {
 	case *schema.BinaryType:
 		f = field.Bytes(name)
 	case *schema.BoolType:
 		f = field.Bool(name)
 	case *schema.DecimalType:
-		f = field.Float(name)
+		f = p.decimalField(dialect.Postgres, typ, name)
 	case *schema.EnumType:
 		f = field.Enum(name).Values(typ.Values...)
 	case *schema.FloatType:
@@ -88,11 +125,24 @@ func (p *Postgres) field(column *schema.Column) (f ent.Field, err error) {
 	case *postgres.UUIDType:
 		f = field.UUID(name, uuid.New())
 
+	case *postgres.ArrayType:
+		f = p.convertArray(typ, name)
+	case *postgres.DomainType:
+		// A domain is stored as its underlying type, and keeps its name through SchemaType.
+		if u := typ.Underlying(); u != nil {
+			return p.convertType(u, name)
+		}
+		f = p.convertOther(typ, name)
+	case *postgres.CurrencyType, *postgres.TextSearchType, *postgres.XMLType:
+		// These types are read and written in their text representation.
+		f = field.String(name)
+	case *postgres.OIDType:
+		f = field.Uint32(name)
+
 	default:
-		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
+		f = p.convertOther(typ, name)
 	}
-	applyColumnAttributes(f, column)
-	return f, err
+	return f
 }
 
 // decimal, numeric - user-specified precision, exact up to 131072 digits before the decimal point;
@@ -0,0 +1,117 @@
+package entimport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Severity is the level of a Diagnostic.
+type Severity string
+
+const (
+	// SeverityError reports a table or a column that could not be imported.
+	SeverityError Severity = "error"
+	// SeverityWarning reports a column imported with a fallback, to be reviewed by hand.
+	SeverityWarning Severity = "warning"
+	// SeveritySkipped reports a relation left out because one of its sides was not imported.
+	SeveritySkipped Severity = "skipped"
+)
+
+// Diagnostic is an issue met while importing a table, or one of its columns.
+type Diagnostic struct {
+	Severity Severity
+	// Table is the name of the table, qualified with its schema when several schemas are imported.
+	// It is empty for the issues that are not related to a table.
+	Table string
+	// Column is the name of the column, if any.
+	Column  string
+	Message string
+}
+
+// String returns the diagnostic as "error: users.avatar: unsupported type ...".
+func (d Diagnostic) String() string {
+	item := d.Table
+	if d.Column != "" {
+		item += "." + d.Column
+	}
+	if item == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Severity, item, d.Message)
+}
+
+// Report collects the diagnostics of an import. The tables having an error are not written,
+// unless the error is on a column, which is then left out of the schema.
+type Report struct {
+	// Tables is the number of tables and views imported.
+	Tables      int
+	Diagnostics []Diagnostic
+	// table is the table being imported, for the diagnostics reported by the field functions.
+	table string
+}
+
+// HasErrors reports if the import met an error.
+func (r *Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Count returns the number of diagnostics of the given severity.
+func (r *Report) Count(s Severity) int {
+	n := 0
+	for _, d := range r.Diagnostics {
+		if d.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Print writes the summary of the import followed by its diagnostics.
+func (r *Report) Print(w io.Writer) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "entimport: %d tables imported, %d errors, %d warnings, %d skipped\n",
+		r.Tables, r.Count(SeverityError), r.Count(SeverityWarning), r.Count(SeveritySkipped))
+	for _, d := range r.Diagnostics {
+		fmt.Fprintf(w, "  %s\n", d)
+	}
+}
+
+func (r *Report) add(s Severity, table, column, msg string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{
+		Severity: s,
+		Table:    table,
+		Column:   column,
+		Message:  strings.TrimPrefix(msg, "entimport: "),
+	})
+}
+
+// fail records the error of a table, or of one of its columns if given, and returns nil, so that the
+// import goes on with the other tables. The error is returned as is in strict mode.
+func (i *ImportOptions) fail(table, column string, err error) error {
+	if i.strict {
+		return err
+	}
+	i.diagnostics().add(SeverityError, table, column, err.Error())
+	return nil
+}
+
+// warnf records a warning on a column of the table being imported.
+func (i *ImportOptions) warnf(column, format string, args ...any) {
+	i.diagnostics().add(SeverityWarning, i.diagnostics().table, column, fmt.Sprintf(format, args...))
+}
+
+// skipf records a relation, or another item of a table, left out of the import.
+func (i *ImportOptions) skipf(table, format string, args ...any) {
+	i.diagnostics().add(SeveritySkipped, table, "", fmt.Sprintf(format, args...))
+}
+
+// diagnostics returns the report of the import, created on first use.
+func (i *ImportOptions) diagnostics() *Report {
+	if i.report == nil {
+		i.report = &Report{}
+	}
+	return i.report
+}
@@ -0,0 +1,449 @@
+package entimport
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	entschema "entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+
+	"github.com/go-openapi/inflect"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// builderCall is a field builder method call that schemast is not able to print, e.g. UpdateDefault.
+type builderCall struct {
+	method  string
+	args    []ast.Expr
+	imports []string
+}
+
+// builderCalls is a field annotation carrying the builder calls of a field. It is stripped before schemast
+// prints the field and the calls are appended to the printed field afterward.
+type builderCalls []builderCall
+
+// Name implements the schema.Annotation interface.
+func (builderCalls) Name() string {
+	return "EntImportBuilderCalls"
+}
+
+// newBuilderCalls returns a builderCalls annotation holding a single builder method call.
+func newBuilderCalls(method, importPath string, args ...ast.Expr) builderCalls {
+	c := builderCall{method: method, args: args}
+	if importPath != "" {
+		c.imports = append(c.imports, importPath)
+	}
+	return builderCalls{c}
+}
+
+// appendBuilderCall adds a builder method call to the given field descriptor.
+func appendBuilderCall(desc *field.Descriptor, method, importPath string, args ...ast.Expr) {
+	desc.Annotations = append(desc.Annotations, newBuilderCalls(method, importPath, args...))
+}
+
+// splitBuilderCalls separates the builder calls from the other annotations.
+func splitBuilderCalls(annots []entschema.Annotation) ([]entschema.Annotation, []builderCall) {
+	var (
+		others []entschema.Annotation
+		calls  []builderCall
+	)
+	for _, annot := range annots {
+		if c, ok := annot.(builderCalls); ok {
+			calls = append(calls, c...)
+			continue
+		}
+		others = append(others, annot)
+	}
+	return others, calls
+}
+
+// otherField returns a field.Other of the given type. schemast does not print this field type, so the field
+// is printed as a string field by upsertSchema and its constructor is replaced afterward.
+func otherField(name, pkgPath, typeName string) ent.Field {
+	f := field.String(name)
+	pkgName := path.Base(pkgPath)
+	f.Descriptor().Info = &field.TypeInfo{
+		Type:    field.TypeOther,
+		Ident:   pkgName + "." + typeName,
+		PkgPath: pkgPath,
+		PkgName: pkgName,
+	}
+	return f
+}
+
+// schemaAnnotation is a schema annotation that schemast is not able to print, e.g. field.ID. It is stripped
+// before schemast prints the schema and its expression is appended to the printed annotations afterward.
+type schemaAnnotation struct {
+	expr    ast.Expr
+	imports []string
+}
+
+// Name implements the schema.Annotation interface.
+func (schemaAnnotation) Name() string {
+	return "EntImportSchemaAnnotation"
+}
+
+// upsertSchema wraps a schemast.UpsertSchema and applies the builder calls of its fields.
+type upsertSchema struct {
+	*schemast.UpsertSchema
+	// pkg is the package the schema is written to, relative to the schema path.
+	pkg string
+}
+
+// Mutate implements schemast.Mutator.
+func (u *upsertSchema) Mutate(ctx *schemast.Context) error {
+	var (
+		hasCalls   bool
+		fieldCalls = make(map[string][]builderCall)
+		edgeCalls  = make([][]builderCall, len(u.Edges))
+		indexCalls = make([][]builderCall, len(u.Indexes))
+		otherTypes = make(map[string]*field.TypeInfo)
+	)
+	for _, f := range u.Fields {
+		desc := f.Descriptor()
+		desc.Annotations, fieldCalls[desc.Name] = splitBuilderCalls(desc.Annotations)
+		hasCalls = hasCalls || len(fieldCalls[desc.Name]) > 0
+		if desc.Info.Type == field.TypeOther {
+			otherTypes[desc.Name] = desc.Info
+			desc.Info = &field.TypeInfo{Type: field.TypeString}
+			hasCalls = true
+		}
+	}
+	var (
+		mixins      schemaMixins
+		schemaAnnot []schemaAnnotation
+		view        bool
+	)
+	annotations := u.Annotations[:0:0]
+	for _, annot := range u.Annotations {
+		switch a := annot.(type) {
+		case schemaMixins:
+			mixins = append(mixins, a...)
+		case schemaAnnotation:
+			schemaAnnot = append(schemaAnnot, a)
+		case viewAnnotation:
+			view = true
+		default:
+			annotations = append(annotations, annot)
+		}
+	}
+	u.Annotations = annotations
+	hasCalls = hasCalls || len(mixins) > 0 || len(schemaAnnot) > 0 || view
+	defer func() {
+		for _, f := range u.Fields {
+			if info, ok := otherTypes[f.Descriptor().Name]; ok {
+				f.Descriptor().Info = info
+			}
+		}
+	}()
+	for i, e := range u.Edges {
+		desc := e.Descriptor()
+		desc.Annotations, edgeCalls[i] = splitBuilderCalls(desc.Annotations)
+		hasCalls = hasCalls || len(edgeCalls[i]) > 0
+	}
+	for i, idx := range u.Indexes {
+		desc := idx.Descriptor()
+		desc.Annotations, indexCalls[i] = splitBuilderCalls(desc.Annotations)
+		hasCalls = hasCalls || len(indexCalls[i]) > 0
+	}
+	if !hasCalls {
+		return u.UpsertSchema.Mutate(ctx)
+	}
+	file, err := typeFile(ctx, u.Name)
+	if err != nil {
+		return err
+	}
+	if err = u.UpsertSchema.Mutate(ctx); err != nil {
+		return err
+	}
+	if len(u.Fields) > 0 {
+		fields, err := returnedItems(file, u.Name, "Fields")
+		if err != nil {
+			return err
+		}
+		for i, item := range fields.Elts {
+			expr, ok := item.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			name, ok := fieldName(expr)
+			if !ok {
+				continue
+			}
+			if info, ok := otherTypes[name]; ok {
+				setOtherType(ctx, file, expr, info)
+			}
+			fields.Elts[i] = applyBuilderCalls(ctx, file, expr, fieldCalls[name])
+		}
+	}
+	if len(u.Edges) > 0 {
+		edges, err := returnedItems(file, u.Name, "Edges")
+		if err != nil {
+			return err
+		}
+		// schemast appends the edges in order, after resetting the method.
+		for i, item := range edges.Elts {
+			if i < len(edgeCalls) {
+				edges.Elts[i] = applyBuilderCalls(ctx, file, item, edgeCalls[i])
+			}
+		}
+	}
+	if len(schemaAnnot) > 0 {
+		annots, err := returnedItems(file, u.Name, "Annotations")
+		if err != nil {
+			return err
+		}
+		for _, a := range schemaAnnot {
+			annots.Elts = append(annots.Elts, a.expr)
+			for _, path := range a.imports {
+				astutil.AddImport(ctx.SchemaPackage.Fset, file, path)
+			}
+		}
+	}
+	if len(mixins) > 0 {
+		setMixins(ctx, file, u.Name, mixins)
+	}
+	if view {
+		setView(file, u.Name)
+	}
+	if len(u.Indexes) > 0 {
+		indexes, err := returnedItems(file, u.Name, "Indexes")
+		if err != nil {
+			return err
+		}
+		// schemast appends the indexes in order, after resetting the method.
+		for i, item := range indexes.Elts {
+			if i < len(indexCalls) {
+				indexes.Elts[i] = applyBuilderCalls(ctx, file, item, indexCalls[i])
+			}
+		}
+	}
+	return nil
+}
+
+// applyBuilderCalls chains the given builder calls to a builder expression.
+func applyBuilderCalls(ctx *schemast.Context, file *ast.File, expr ast.Expr, calls []builderCall) ast.Expr {
+	for _, c := range calls {
+		expr = &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: expr, Sel: ast.NewIdent(c.method)},
+			Args: c.args,
+		}
+		for _, path := range c.imports {
+			astutil.AddImport(ctx.SchemaPackage.Fset, file, path)
+		}
+	}
+	return expr
+}
+
+// setMixins sets the items returned by the Mixin method of a type, and adds the method before
+// the Fields method if it does not exist yet.
+func setMixins(ctx *schemast.Context, file *ast.File, typeName string, mixins []Mixin) {
+	items := make([]ast.Expr, 0, len(mixins))
+	for _, m := range mixins {
+		pkg, name, _ := strings.Cut(m.Type, ".")
+		items = append(items, &ast.CompositeLit{Type: selector(pkg, name)})
+		if pkg == path.Base(m.Import) {
+			astutil.AddImport(ctx.SchemaPackage.Fset, file, m.Import)
+		} else {
+			astutil.AddNamedImport(ctx.SchemaPackage.Fset, file, pkg, m.Import)
+		}
+	}
+	if lit, err := returnedItems(file, typeName, "Mixin"); err == nil {
+		lit.Elts = items
+		return
+	}
+	mixinType := func() ast.Expr {
+		return &ast.ArrayType{Elt: selector("ent", "Mixin")}
+	}
+	decl := &ast.FuncDecl{
+		Recv: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent(typeName)}}},
+		Name: ast.NewIdent("Mixin"),
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: mixinType()}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{&ast.CompositeLit{Type: mixinType(), Elts: items}}},
+		}},
+	}
+	for i, d := range file.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Name.Name == "Fields" && fd.Recv != nil {
+			file.Decls = append(file.Decls[:i], append([]ast.Decl{decl}, file.Decls[i:]...)...)
+			return
+		}
+	}
+	file.Decls = append(file.Decls, decl)
+}
+
+// setOtherType replaces the field.String("name") constructor of a builder chain with field.Other("name", &T{}).
+func setOtherType(ctx *schemast.Context, file *ast.File, expr *ast.CallExpr, info *field.TypeInfo) {
+	for {
+		sel, ok := expr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			sel.Sel = ast.NewIdent("Other")
+			typ := strings.TrimPrefix(info.Ident, info.PkgName+".")
+			expr.Args = append(expr.Args, &ast.UnaryExpr{
+				Op: token.AND,
+				X:  &ast.CompositeLit{Type: selector(info.PkgName, typ)},
+			})
+			astutil.AddImport(ctx.SchemaPackage.Fset, file, info.PkgPath)
+			return
+		}
+		expr = inner
+	}
+}
+
+// typeFile returns the file declaring the given type. Types that don't exist yet are added to the
+// schema package, the same way schemast adds them, so that their AST is reachable after the mutation.
+func typeFile(ctx *schemast.Context, typeName string) (*ast.File, error) {
+	for _, file := range ctx.SchemaPackage.Syntax {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || len(gd.Specs) == 0 {
+				continue
+			}
+			if ts, ok := gd.Specs[0].(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+				return file, nil
+			}
+		}
+	}
+	if ctx.HasType(typeName) {
+		return nil, fmt.Errorf("entimport: type %q was added outside of the schema package", typeName)
+	}
+	body := fmt.Sprintf(`package schema
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+)
+type %s struct {
+	ent.Schema
+}
+func (%s) Fields() []ent.Field {
+	return nil
+}
+func (%s) Edges() []ent.Edge {
+	return nil
+}
+func (%s) Annotations() []schema.Annotation {
+	return nil
+}
+`, typeName, typeName, typeName, typeName)
+	file, err := parser.ParseFile(ctx.SchemaPackage.Fset, inflect.Underscore(typeName)+".go", body, 0)
+	if err != nil {
+		return nil, err
+	}
+	ctx.SchemaPackage.Syntax = append(ctx.SchemaPackage.Syntax, file)
+	return file, nil
+}
+
+// returnedItems returns the composite literal returned by the given method of a type.
+func returnedItems(file *ast.File, typeName, method string) (*ast.CompositeLit, error) {
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != method || fd.Recv == nil || len(fd.Recv.List) != 1 {
+			continue
+		}
+		if id, ok := fd.Recv.List[0].Type.(*ast.Ident); !ok || id.Name != typeName {
+			continue
+		}
+		if len(fd.Body.List) != 1 {
+			break
+		}
+		ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			break
+		}
+		switch r := ret.Results[0].(type) {
+		case *ast.CompositeLit:
+			return r, nil
+		case *ast.Ident:
+			// A method returning nil, e.g. the Annotations of a view, is made to return an empty list.
+			// The type of the list is rebuilt without the positions of the signature, which the printer
+			// would take for a line break.
+			if at, ok := resultType(fd); r.Name == "nil" && ok {
+				lit := &ast.CompositeLit{Type: at}
+				ret.Results[0] = lit
+				return lit, nil
+			}
+		}
+		break
+	}
+	return nil, fmt.Errorf("entimport: could not find the items returned by %s.%s()", typeName, method)
+}
+
+// resultType returns a copy, without positions, of the slice type returned by a method, e.g. []ent.Field.
+func resultType(fd *ast.FuncDecl) (*ast.ArrayType, bool) {
+	if fd.Type.Results == nil || len(fd.Type.Results.List) != 1 {
+		return nil, false
+	}
+	at, ok := fd.Type.Results.List[0].Type.(*ast.ArrayType)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := at.Elt.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	return &ast.ArrayType{Elt: selector(x.Name, sel.Sel.Name)}, true
+}
+
+// fieldName extracts the field name from a field.<Type>("name") builder chain.
+func fieldName(expr *ast.CallExpr) (string, bool) {
+	sel, ok := expr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if inner, ok := sel.X.(*ast.CallExpr); ok {
+		return fieldName(inner)
+	}
+	if len(expr.Args) == 0 {
+		return "", false
+	}
+	lit, ok := expr.Args[0].(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	return name, err == nil
+}
+
+// upsertSchemas wraps the given mutators so the builder calls of their fields are applied.
+// The packages map the keys of the mutators to the package they are written to.
+func upsertSchemas(mutations map[string]schemast.Mutator, packages map[string]string) []schemast.Mutator {
+	ml := make([]schemast.Mutator, 0, len(mutations))
+	for key, mutator := range mutations {
+		if u, ok := mutator.(*schemast.UpsertSchema); ok {
+			mutator = &upsertSchema{UpsertSchema: u, pkg: packages[key]}
+		}
+		ml = append(ml, mutator)
+	}
+	return ml
+}
+
+// schemaPackages groups the given mutators by the package they are written to.
+func schemaPackages(mutations []schemast.Mutator) map[string][]schemast.Mutator {
+	packages := make(map[string][]schemast.Mutator)
+	for _, mutator := range mutations {
+		var pkg string
+		if u, ok := mutator.(*upsertSchema); ok {
+			pkg = u.pkg
+		}
+		packages[pkg] = append(packages[pkg], mutator)
+	}
+	return packages
+}
@@ -0,0 +1,108 @@
+package entimport
+
+import (
+	"context"
+	"encoding/json"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sInt     = "int"     // INT - 8 bytes signed integer, same as INTEGER.
+	sInteger = "integer" // INTEGER - 8 bytes signed integer.
+)
+
+// SQLite implements SchemaImporter for SQLite databases.
+type SQLite struct {
+	*ImportOptions
+}
+
+// NewSQLite - returns a new *SQLite.
+func NewSQLite(i *ImportOptions) (SchemaImporter, error) {
+	return &SQLite{
+		ImportOptions: i,
+	}, nil
+}
+
+// SchemaMutations implements SchemaImporter.
+func (s *SQLite) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	tables, views, err := s.inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemaMutations(s.field, tables, views)
+}
+
+// inspect implements inspector.
+func (s *SQLite) inspect(ctx context.Context) ([]*schema.Table, []*schema.View, error) {
+	inspectOptions := &schema.InspectOptions{
+		Tables: s.filter.Names(),
+	}
+	sc, err := s.driver.InspectSchema(ctx, s.driver.SchemaName, inspectOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables := s.filterTables(sc.Tables)
+	views, err := s.inspectViews(ctx, s.driver.SchemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tables, views, nil
+}
+
+func (s *SQLite) field(column *schema.Column) (f ent.Field, err error) {
+	name := column.Name
+	switch typ := column.Type.Type.(type) {
+	case *schema.BinaryType:
+		f = field.Bytes(name)
+	case *schema.BoolType:
+		f = field.Bool(name)
+	case *schema.DecimalType:
+		f = s.decimalField(dialect.SQLite, typ, name)
+	case *schema.EnumType:
+		f = field.Enum(name).Values(typ.Values...)
+	case *schema.FloatType:
+		// SQLite stores every floating point value as an 8-byte IEEE floating point number.
+		f = field.Float(name)
+	case *schema.IntegerType:
+		f = s.convertInteger(typ, name)
+	case *schema.JSONType:
+		f = field.JSON(name, json.RawMessage{})
+	case *schema.StringType:
+		f = field.String(name)
+	case *schema.TimeType:
+		f = field.Time(name)
+	case *schema.UUIDType:
+		f = field.UUID(name, uuid.New())
+	default:
+		return nil, unsupportedType(column)
+	}
+	applyColumnAttributes(dialect.SQLite, f, column)
+	return f, err
+}
+
+// SQLite has a single INTEGER storage class, but keeps the declared type name,
+// so the narrower declarations are mapped the same way as in MySQL.
+func (s *SQLite) convertInteger(typ *schema.IntegerType, name string) (f ent.Field) {
+	switch typ.T {
+	case mTinyInt:
+		f = field.Int8(name)
+	case mSmallInt:
+		f = field.Int16(name)
+	case mMediumInt:
+		f = field.Int32(name)
+	case sInt, sInteger, mBigInt:
+		// Int64 is not used on purpose.
+		f = field.Int(name)
+	default:
+		f = field.Int(name)
+	}
+	return f
+}
@@ -0,0 +1,392 @@
+package entimport_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"go/parser"
+	"go/printer"
+	"go/token"
+
+	"entgo.io/ent/dialect"
+	"github.com/go-openapi/inflect"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
+)
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		entities       []string
+		ddl            []string
+		expectedFields map[string]string
+		expectedEdges  map[string]string
+		// expectedIndexes is only checked for the entities it holds.
+		expectedIndexes map[string]string
+		// expectedMixins is only checked for the entities it holds.
+		expectedMixins map[string]string
+		// expectedAnnotations is only checked for the entities it holds.
+		expectedAnnotations map[string]string
+		options             []entimport.ImportOption
+	}{
+		{
+			name: "single_table_fields",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, age smallint NOT NULL, name varchar(255) NOT NULL, score real NULL, active boolean NOT NULL, created_at datetime NOT NULL, avatar blob NULL)",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int16("age").SchemaType(map[string]string{"sqlite3": "smallint"}), field.String("name").SchemaType(map[string]string{"sqlite3": "varchar(255)"}).MaxLen(255).NotEmpty(), field.Float("score").Optional(), field.Bool("active"), field.Time("created_at"), field.Bytes("avatar").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return nil
+}`,
+			},
+			entities: []string{"user"},
+		},
+		{
+			name: "fields_with_defaults",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, age integer NOT NULL DEFAULT 18, name text NOT NULL DEFAULT 'anonymous', active boolean NOT NULL DEFAULT true, score real NOT NULL DEFAULT 1.5, created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, nickname text NULL DEFAULT (lower('X')))",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("age").Default(18), field.String("name").Default("anonymous"), field.Bool("active").Default(true), field.Float("score").Default(1.5), field.Time("created_at").Default(time.Now), field.String("nickname").Optional().Annotations(entsql.DefaultExpr("lower('X')"))}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return nil
+}`,
+			},
+			entities: []string{"user"},
+		},
+		{
+			name: "fields_with_indexes",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, first_name text NOT NULL, last_name text NOT NULL, email text NOT NULL, age integer NULL, deleted_at datetime NULL, UNIQUE (first_name, last_name))",
+				"CREATE INDEX users_age ON users (age)",
+				"CREATE INDEX by_name ON users (last_name, first_name DESC)",
+				"CREATE UNIQUE INDEX users_email ON users (email) WHERE deleted_at IS NULL",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("first_name").NotEmpty(), field.String("last_name").NotEmpty(), field.String("email").NotEmpty(), field.Int("age").Optional(), field.Time("deleted_at").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return nil
+}`,
+			},
+			expectedIndexes: map[string]string{
+				"user": `func (User) Indexes() []ent.Index {
+	return []ent.Index{index.Fields("first_name", "last_name").Unique(), index.Fields("age"), index.Fields("last_name", "first_name").StorageKey("by_name").Annotations(entsql.DescColumns("first_name")), index.Fields("email").Unique().Annotations(entsql.IndexWhere("deleted_at IS NULL"))}
+}`,
+			},
+			entities: []string{"user"},
+		},
+		{
+			name: "fields_with_mixins",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, name text NOT NULL, tenant_id integer NULL, created_at datetime NULL, updated_at datetime NULL, deleted_at datetime NULL)",
+				"CREATE TABLE pets (id integer PRIMARY KEY, name text NOT NULL, created_at datetime NULL, user_pets integer NULL REFERENCES users (id))",
+			},
+			options: []entimport.ImportOption{entimport.WithMixins([]entimport.Mixin{
+				{Type: "mixin.AutoIncrementId", Import: "github.com/tx7do/go-utils/entgo/mixin", Columns: []string{"id"}},
+				{Type: "mixin.TimeAt", Import: "github.com/tx7do/go-utils/entgo/mixin", Columns: []string{"created_at", "updated_at", "deleted_at"}},
+				{Type: "tenant.ID", Import: "example.com/schema/mixin", Columns: []string{"tenant_id"}},
+			})},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.String("name").NotEmpty()}
+}`,
+				"pet": `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.String("name").NotEmpty(), field.Time("created_at").Optional(), field.Int("user_pets").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", Pet.Type)}
+}`,
+				"pet": `func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", User.Type).Ref("pets").Unique().Field("user_pets")}
+}`,
+			},
+			expectedMixins: map[string]string{
+				"user": `func (User) Mixin() []ent.Mixin {
+	return []ent.Mixin{mixin.AutoIncrementId{}, mixin.TimeAt{}, tenant.ID{}}
+}`,
+				"pet": `func (Pet) Mixin() []ent.Mixin {
+	return []ent.Mixin{mixin.AutoIncrementId{}}
+}`,
+			},
+			entities: []string{"user", "pet"},
+		},
+		{
+			name: "naming_strategy",
+			ddl: []string{
+				"CREATE TABLE sys_users (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE sys_user_data (id integer PRIMARY KEY, value text NOT NULL, owner_id integer NULL REFERENCES sys_users (id))",
+			},
+			options: []entimport.ImportOption{entimport.WithNaming(naming.New(naming.Config{
+				TablePrefixes: []string{"sys_"},
+				Irregulars:    map[string]string{"data": "data"},
+				TypeNames:     map[string]string{"sys_users": "Admin"},
+			}))},
+			expectedFields: map[string]string{
+				"admin": `func (Admin) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+				"user_data": `func (UserData) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("value").NotEmpty(), field.Int("owner_id").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"admin": `func (Admin) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("user_data", UserData.Type)}
+}`,
+				"user_data": `func (UserData) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("admin", Admin.Type).Ref("user_data").Unique().Field("owner_id")}
+}`,
+			},
+			expectedAnnotations: map[string]string{
+				"admin": `func (Admin) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "sys_users"}}
+}`,
+				"user_data": `func (UserData) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "sys_user_data"}}
+}`,
+			},
+			entities: []string{"admin", "user_data"},
+		},
+		{
+			name: "relation_o2m_two_types",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE pets (id integer PRIMARY KEY, name text NOT NULL, user_pets integer NULL REFERENCES users (id))",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+				"pet": `func (Pet) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty(), field.Int("user_pets").Optional()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("pets", Pet.Type)}
+}`,
+				"pet": `func (Pet) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("user", User.Type).Ref("pets").Unique().Field("user_pets")}
+}`,
+			},
+			entities: []string{"user", "pet"},
+		},
+		{
+			name: "relation_m2m_edge_schema",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE groups (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE group_users (group_id integer NOT NULL REFERENCES groups (id), user_id integer NOT NULL REFERENCES users (id), role text NOT NULL, PRIMARY KEY (group_id, user_id))",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+				"group": `func (Group) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+				"group_user": `func (GroupUser) Fields() []ent.Field {
+	return []ent.Field{field.Int("group_id"), field.Int("user_id"), field.String("role").NotEmpty()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("groups", Group.Type).Through("group_users", GroupUser.Type)}
+}`,
+				"group": `func (Group) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("users", User.Type).Ref("groups").Through("group_users", GroupUser.Type)}
+}`,
+				"group_user": `func (GroupUser) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("user", User.Type).Required().Unique().Field("user_id"), edge.To("group", Group.Type).Required().Unique().Field("group_id")}
+}`,
+			},
+			expectedAnnotations: map[string]string{
+				"group_user": `func (GroupUser) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "group_users"}, field.ID("user_id", "group_id")}
+}`,
+			},
+			entities: []string{"user", "group", "group_user"},
+		},
+		{
+			name: "relation_m2m_edge_schema_with_id",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE friendships (id integer PRIMARY KEY, user_id integer NOT NULL REFERENCES users (id), friend_id integer NOT NULL REFERENCES users (id), created_at datetime NOT NULL)",
+				"CREATE UNIQUE INDEX friendships_user_id_friend_id ON friendships (user_id, friend_id)",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+				"friendship": `func (Friendship) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.Int("user_id"), field.Int("friend_id"), field.Time("created_at")}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("child_users", User.Type).StorageKey(edge.Table("friendships"), edge.Columns("friend_id", "user_id")).Through("child_friendships", Friendship.Type), edge.From("parent_users", User.Type).Ref("child_users").Through("parent_friendships", Friendship.Type)}
+}`,
+				"friendship": `func (Friendship) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("friend", User.Type).Required().Unique().Field("friend_id"), edge.To("user", User.Type).Required().Unique().Field("user_id")}
+}`,
+			},
+			entities: []string{"user", "friendship"},
+		},
+		{
+			name: "relation_m2m_two_types",
+			ddl: []string{
+				"CREATE TABLE users (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE groups (id integer PRIMARY KEY, name text NOT NULL)",
+				"CREATE TABLE group_users (group_id integer NOT NULL REFERENCES groups (id), user_id integer NOT NULL REFERENCES users (id), PRIMARY KEY (group_id, user_id))",
+			},
+			expectedFields: map[string]string{
+				"user": `func (User) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+				"group": `func (Group) Fields() []ent.Field {
+	return []ent.Field{field.Int("id"), field.String("name").NotEmpty()}
+}`,
+			},
+			expectedEdges: map[string]string{
+				"user": `func (User) Edges() []ent.Edge {
+	return []ent.Edge{edge.To("groups", Group.Type)}
+}`,
+				"group": `func (Group) Edges() []ent.Edge {
+	return []ent.Edge{edge.From("users", User.Type).Ref("groups")}
+}`,
+			},
+			entities: []string{"user", "group"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			path := createSQLiteDB(t, tt.ddl...)
+			drv, err := mux.Default.OpenImport("sqlite://" + path)
+			r.NoError(err)
+			r.Equal(dialect.SQLite, drv.Dialect)
+			importer, err := entimport.NewImport(
+				append([]entimport.ImportOption{entimport.WithDriver(drv)}, tt.options...)...,
+			)
+			r.NoError(err)
+			schemas := createTempDir(t)
+			mutations, err := importer.SchemaMutations(ctx)
+			r.NoError(err)
+			err = entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas))
+			r.NoError(err)
+			actualFiles := readDir(t, schemas)
+			r.EqualValues(len(tt.entities), len(actualFiles))
+			for _, e := range tt.entities {
+				f, err := parser.ParseFile(token.NewFileSet(), "", actualFiles[e+".go"], 0)
+				r.NoError(err)
+				typeName := inflect.Camelize(e)
+				fieldMethod := lookupMethod(f, typeName, "Fields")
+				r.NotNil(fieldMethod)
+				var actualFields bytes.Buffer
+				err = printer.Fprint(&actualFields, token.NewFileSet(), fieldMethod)
+				r.NoError(err)
+				r.EqualValues(tt.expectedFields[e], actualFields.String())
+				edgeMethod := lookupMethod(f, typeName, "Edges")
+				r.NotNil(edgeMethod)
+				var actualEdges bytes.Buffer
+				err = printer.Fprint(&actualEdges, token.NewFileSet(), edgeMethod)
+				r.NoError(err)
+				r.EqualValues(tt.expectedEdges[e], actualEdges.String())
+				if expected, ok := tt.expectedIndexes[e]; ok {
+					indexMethod := lookupMethod(f, typeName, "Indexes")
+					r.NotNil(indexMethod)
+					var actualIndexes bytes.Buffer
+					err = printer.Fprint(&actualIndexes, token.NewFileSet(), indexMethod)
+					r.NoError(err)
+					r.EqualValues(expected, actualIndexes.String())
+				}
+				if expected, ok := tt.expectedAnnotations[e]; ok {
+					annotationMethod := lookupMethod(f, typeName, "Annotations")
+					r.NotNil(annotationMethod)
+					var actualAnnotations bytes.Buffer
+					err = printer.Fprint(&actualAnnotations, token.NewFileSet(), annotationMethod)
+					r.NoError(err)
+					r.EqualValues(expected, actualAnnotations.String())
+				}
+				if expected, ok := tt.expectedMixins[e]; ok {
+					mixinMethod := lookupMethod(f, typeName, "Mixin")
+					r.NotNil(mixinMethod)
+					var actualMixins bytes.Buffer
+					err = printer.Fprint(&actualMixins, token.NewFileSet(), mixinMethod)
+					r.NoError(err)
+					r.EqualValues(expected, actualMixins.String())
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteMissingFile(t *testing.T) {
+	_, err := mux.Default.OpenImport("sqlite://" + filepath.Join(t.TempDir(), "missing.db"))
+	require.Error(t, err)
+}
+
+// createSQLiteDB creates a temporary SQLite database file and executes the given statements on it.
+func TestSQLiteView(t *testing.T) {
+	r := require.New(t)
+	path := createSQLiteDB(t,
+		"CREATE TABLE users (id integer PRIMARY KEY, name varchar(100) NOT NULL DEFAULT 'a', score integer NOT NULL)",
+		"CREATE VIEW user_scores AS SELECT id, name, score FROM users WHERE score > 0",
+	)
+	schemas := createTempDir(t)
+	importSchema := func() {
+		drv, err := mux.Default.OpenImport("sqlite://" + path)
+		r.NoError(err)
+		importer, err := entimport.NewImport(entimport.WithDriver(drv))
+		r.NoError(err)
+		mutations, err := importer.SchemaMutations(context.Background())
+		r.NoError(err)
+		r.NoError(entimport.WriteSchema(mutations, entimport.WithSchemaPath(schemas)))
+	}
+	importSchema()
+	files := readDir(t, schemas)
+	r.Len(files, 2)
+	view := files["user_score.go"]
+	r.Contains(view, "type UserScore struct {\n\tent.View\n}")
+	r.Contains(view, `return []ent.Field{field.Int("id").Optional(), field.String("name").Optional().SchemaType(map[string]string{"sqlite3": "varchar(100)"}), field.Int("score").Optional()}`)
+	r.Contains(view, `entsql.Annotation{Table: "user_scores", ViewFor: map[string]string{"sqlite3": "SELECT id, name, score FROM users WHERE score > 0"}}`)
+	r.NotContains(view, "Edges()")
+
+	// The view is kept as is by a new import.
+	importSchema()
+	r.Equal(view, readDir(t, schemas)["user_score.go"])
+}
+
+func createSQLiteDB(t *testing.T, stmts ...string) string {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open(dialect.SQLite, "file:"+path+"?_fk=1")
+	require.NoError(t, err)
+	defer db.Close()
+	for _, stmt := range stmts {
+		_, err = db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	return path
+}
@@ -6,10 +6,16 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/google/uuid"
 	ddlparser "github.com/tx7do/go-utils/ddl_parser"
 
+	"github.com/tx7do/go-wind-toolkit/generators/atlashcl"
+	"github.com/tx7do/go-wind-toolkit/generators/dbml"
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
+	"github.com/tx7do/go-wind-toolkit/generators/prisma"
+
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
@@ -172,39 +178,68 @@ func (t *Text) InspectSchema(ctx context.Context, sqlContent string, opts *schem
 
 // SchemaMutations 实现 SchemaImporter 接口，用于解析 SQL 文本
 func (t *Text) SchemaMutations(ctx context.Context) ([]schemast.Mutator, error) {
+	s, dlct, err := t.loadSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.schemaMutations(t.dialectField(dlct), t.filterTables(s.Tables), nil)
+}
+
+// inspect 实现 inspector 接口，SQL 文本没有视图
+func (t *Text) inspect(ctx context.Context) ([]*schema.Table, []*schema.View, error) {
+	s, _, err := t.loadSchema(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t.filterTables(s.Tables), nil, nil
+}
+
+// loadSchema 加载表结构及其方言：migrations:// 目录按版本顺序回放其中的迁移文件，dbml://、prisma:// 与 hcl:// 由各自的解析器读取，否则解析 SQL 文本。
+// 方言未知时返回空字符串
+func (t *Text) loadSchema(ctx context.Context) (*schema.Schema, string, error) {
+	switch scheme, path, _ := strings.Cut(t.schemaPath, "://"); scheme {
+	case migrations.Scheme:
+		s, err := migrations.Load(path)
+		return s, "", err
+	case dbml.Scheme:
+		return dbml.LoadDialect(path)
+	case prisma.Scheme:
+		return prisma.LoadDialect(path)
+	case atlashcl.Scheme:
+		return atlashcl.LoadDialect(path)
+	}
+
 	// 加载 SQL 文本
 	sqlText := t.loadSQLFromFile()
 	if sqlText == "" {
-		return nil, fmt.Errorf("无法加载 SQL 文件: %v", t.schemaPath)
+		return nil, "", fmt.Errorf("无法加载 SQL 文件: %v", t.schemaPath)
 	}
 
 	inspectOptions := &schema.InspectOptions{
-		Tables: t.tables,
+		Tables: t.filter.Names(),
 	}
 
 	var s schema.Schema
 
-	_, err := t.InspectSchema(ctx, sqlText, inspectOptions, &s)
-	if err != nil {
-		return nil, err
+	if _, err := t.InspectSchema(ctx, sqlText, inspectOptions, &s); err != nil {
+		return nil, "", err
 	}
+	return &s, "", nil
+}
 
-	tables := s.Tables
-	if t.excludedTables != nil {
-		tables = nil
-		excludedTableNames := make(map[string]bool)
-		for _, t := range t.excludedTables {
-			excludedTableNames[t] = true
-		}
-		// filter out tables that are in excludedTables:
-		for _, t := range s.Tables {
-			if !excludedTableNames[t.Name] {
-				tables = append(tables, t)
-			}
-		}
+// dialectField 返回方言对应的字段转换函数，使设计文件中的方言类型（如 PostgreSQL 的 text[]）按该方言导入；方言未知时使用 Text.field
+func (t *Text) dialectField(dlct string) func(*schema.Column) (ent.Field, error) {
+	switch dlct {
+	case "mysql":
+		return (&MySQL{ImportOptions: t.ImportOptions}).field
+	case "postgres":
+		return (&Postgres{ImportOptions: t.ImportOptions}).field
+	case "sqlite":
+		return (&SQLite{ImportOptions: t.ImportOptions}).field
 	}
-
-	return schemaMutations(t.field, tables)
+	return t.field
 }
 
 func (t *Text) field(column *schema.Column) (f ent.Field, err error) {
@@ -215,7 +250,7 @@ func (t *Text) field(column *schema.Column) (f ent.Field, err error) {
 	case *schema.BoolType:
 		f = field.Bool(name)
 	case *schema.DecimalType:
-		f = field.Float(name)
+		f = t.decimalField("", typ, name)
 	case *schema.EnumType:
 		f = field.Enum(name).Values(typ.Values...)
 	case *schema.FloatType:
@@ -235,9 +270,9 @@ func (t *Text) field(column *schema.Column) (f ent.Field, err error) {
 		f = field.UUID(name, uuid.New())
 
 	default:
-		return nil, fmt.Errorf("entimport: unsupported type %q for column %v", typ, column.Name)
+		return nil, unsupportedType(column)
 	}
-	applyColumnAttributes(f, column)
+	applyColumnAttributes("", f, column)
 	return f, err
 }
 
@@ -2,10 +2,22 @@ package entimport
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/entc/load"
+	"entgo.io/ent/schema/field"
+
 	"github.com/stretchr/testify/assert"
+
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
 )
 
 func TestText(t *testing.T) {
@@ -40,3 +52,248 @@ CREATE TABLE users1 (
 		log.Fatalf("entimport: schema writing failed - %v", err)
 	}
 }
+
+func TestTextDecimalType(t *testing.T) {
+	column := &schema.Column{
+		Name: "amount",
+		Type: &schema.ColumnType{
+			Type: &schema.DecimalType{T: "decimal", Precision: 12, Scale: 4},
+			Raw:  "decimal(12,4)",
+		},
+	}
+
+	text, err := NewText(&ImportOptions{})
+	assert.Nil(t, err)
+	f, err := text.field(column)
+	assert.Nil(t, err)
+	assert.False(t, isDecimal(f.Descriptor()))
+	assert.Empty(t, f.Descriptor().SchemaType)
+
+	text, err = NewText(&ImportOptions{decimalType: DecimalShopspring})
+	assert.Nil(t, err)
+	f, err = text.field(column)
+	assert.Nil(t, err)
+	assert.True(t, isDecimal(f.Descriptor()))
+	assert.Equal(t, "decimal(12,4)", f.Descriptor().SchemaType[dialect.MySQL])
+	assert.Equal(t, "decimal(12,4)", f.Descriptor().SchemaType[dialect.Postgres])
+}
+
+func TestTextMigrations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"1_create_users.up.sql":   "CREATE TABLE users (id BIGINT PRIMARY KEY, name VARCHAR(64), legacy TEXT);",
+		"1_create_users.down.sql": "DROP TABLE users;",
+		"2_create_pets.up.sql": `
+CREATE TABLE pets (id BIGINT PRIMARY KEY, owner_id BIGINT NOT NULL);
+ALTER TABLE pets ADD CONSTRAINT fk_owner FOREIGN KEY (owner_id) REFERENCES users (id);
+ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL, DROP COLUMN legacy;
+CREATE UNIQUE INDEX uk_email ON users (email);`,
+	}
+	for name, content := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	// A folder is read as a migrations:// source.
+	tables, dlct, err := InspectTables(context.Background(), dir, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "text", dlct)
+	assert.Len(t, tables, 2)
+
+	email, ok := tables[0].Column("email")
+	assert.True(t, ok)
+	assert.False(t, email.Type.Null)
+	_, ok = tables[0].Column("legacy")
+	assert.False(t, ok)
+	assert.Len(t, tables[0].Indexes, 1)
+	assert.Len(t, tables[1].ForeignKeys, 1)
+	assert.Equal(t, tables[0], tables[1].ForeignKeys[0].RefTable)
+}
+
+func TestTextDesignFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"shop.dbml": `
+Table users {
+  id bigint [pk, increment]
+  email varchar(255) [not null, unique, note: 'login email']
+}
+
+Table pets {
+  id bigint [pk]
+  owner_id bigint [not null, ref: > users.id]
+}`,
+		"schema.prisma": `
+model User {
+  id    Int    @id @default(autoincrement())
+  /// login email
+  email String @unique
+  pets  Pet[]
+}
+
+model Pet {
+  id      Int  @id
+  owner   User @relation(fields: [ownerId], references: [id])
+  ownerId Int  @map("owner_id")
+}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+
+		// A .dbml or .prisma file is read as a dbml:// or prisma:// source.
+		tables, dlct, err := InspectTables(context.Background(), path, nil, nil)
+		assert.Nil(t, err, name)
+		assert.Equal(t, "text", dlct)
+		assert.Len(t, tables, 2, name)
+
+		email, ok := tables[0].Column("email")
+		assert.True(t, ok, name)
+		assert.False(t, email.Type.Null, name)
+		assert.Len(t, email.Indexes, 1, name)
+		assert.Contains(t, email.Attrs, &schema.Comment{Text: "login email"}, name)
+		assert.Len(t, tables[1].ForeignKeys, 1, name)
+		assert.Equal(t, tables[0], tables[1].ForeignKeys[0].RefTable, name)
+		assert.Equal(t, "owner_id", tables[1].ForeignKeys[0].Columns[0].Name, name)
+	}
+}
+
+func TestTextCommentAnnotations(t *testing.T) {
+	sql := `
+CREATE TABLE accounts (
+	id bigint NOT NULL,
+	status int NOT NULL DEFAULT '1' COMMENT '账号状态 @enum(1:ACTIVE,2:DISABLED)',
+	kind varchar(16) COMMENT '类型 @enum(PERSONAL,COMPANY) @json(account_kind)',
+	password varchar(64) NOT NULL COMMENT '密码 @sensitive @minlen(8)',
+	nickname varchar(32) COMMENT '昵称 @notempty @pattern(^[a-z]+$)',
+	age int COMMENT '年龄 @range(0,150)',
+	PRIMARY KEY (id)
+);
+`
+	text, err := NewText(&ImportOptions{schemaPath: sql})
+	assert.Nil(t, err)
+
+	mutations, err := text.SchemaMutations(context.Background())
+	assert.Nil(t, err)
+
+	schemaPath := t.TempDir()
+	assert.Nil(t, WriteSchema(mutations, WithSchemaPath(schemaPath)))
+	buf, err := os.ReadFile(filepath.Join(schemaPath, "account.go"))
+	assert.Nil(t, err)
+	content := string(buf)
+	for _, want := range []string{
+		`field.Enum("status").Comment("账号状态").Default("1").NamedValues("ACTIVE", "1", "DISABLED", "2")`,
+		`field.Enum("kind").Optional().Comment("类型").StructTag("json:\"account_kind,omitempty\"").Values("PERSONAL", "COMPANY")`,
+		`field.String("password").Sensitive().Comment("密码").MinLen(8)`,
+		"field.String(\"nickname\").Optional().Comment(\"昵称\").NotEmpty().Match(regexp.MustCompile(`^[a-z]+$`))",
+		`.Comment("年龄").Range(0, 150)`,
+	} {
+		assert.Contains(t, content, want)
+	}
+
+	// With a dialect, the enum keeps the integer column the numbers are stored in.
+	column := schema.NewIntColumn("status", "int").SetComment("状态 @enum(1:ACTIVE,2:DISABLED)")
+	f := field.Int32("status")
+	applyColumnAttributes(dialect.MySQL, f, column)
+	assert.Equal(t, field.TypeEnum, f.Descriptor().Info.Type)
+	assert.Equal(t, map[string]string{dialect.MySQL: "int"}, f.Descriptor().SchemaType)
+	assert.Equal(t, "状态", f.Descriptor().Comment)
+}
+
+func TestTextUniqueDefault(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "1_create_users.up.sql"), []byte(`
+CREATE TABLE users (id BIGINT PRIMARY KEY, email VARCHAR(64) NOT NULL DEFAULT '', code INT NOT NULL DEFAULT 0);
+CREATE UNIQUE INDEX users_email_key ON users (email);
+CREATE UNIQUE INDEX users_code_key ON users (code);`), 0o644))
+
+	text, err := NewText(&ImportOptions{schemaPath: migrations.Scheme + "://" + dir})
+	assert.Nil(t, err)
+
+	mutations, err := text.SchemaMutations(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, mutations, 1)
+	upsert, ok := mutations[0].(*upsertSchema)
+	assert.True(t, ok)
+
+	// entc rejects the unique fields having a default value, so the defaults are kept in the database.
+	s := &load.Schema{Name: upsert.Name}
+	for _, f := range upsert.Fields {
+		sf, err := load.NewField(f.Descriptor())
+		assert.Nil(t, err)
+		s.Fields = append(s.Fields, sf)
+	}
+	_, err = gen.NewGraph(&gen.Config{Package: "entimport/ent"}, s)
+	assert.Nil(t, err)
+
+	schemaPath := t.TempDir()
+	assert.Nil(t, WriteSchema(mutations, WithSchemaPath(schemaPath)))
+	buf, err := os.ReadFile(filepath.Join(schemaPath, "user.go"))
+	assert.Nil(t, err)
+	content := string(buf)
+	assert.Contains(t, content, `field.String("email").Unique().MaxLen(64).Annotations(entsql.DefaultExpr("''"))`)
+	assert.Contains(t, content, `field.Int32("code").Unique().Annotations(entsql.DefaultExpr("0"))`)
+}
+
+func TestTextDesignFileDialect(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"schema.hcl": `
+schema "public" {}
+
+table "posts" {
+  schema = schema.public
+  column "id" {
+    type = bigint
+  }
+  column "tags" {
+    type = sql("text[]")
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}`,
+		"shop.dbml": `
+Project shop {
+  database_type: 'PostgreSQL'
+}
+
+Table posts {
+  id bigint [pk]
+  tags "text[]"
+}`,
+		"schema.prisma": `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+model Post {
+  id   BigInt   @id
+  tags String[]
+  @@map("posts")
+}`,
+	}
+	schemes := map[string]string{
+		"schema.hcl":    "hcl://%s?dialect=postgres",
+		"shop.dbml":     "dbml://%s",
+		"schema.prisma": "prisma://%s",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+
+		// The PostgreSQL arrays of a design file are imported with the types of its dialect.
+		text, err := NewText(&ImportOptions{schemaPath: fmt.Sprintf(schemes[name], path)})
+		assert.Nil(t, err, name)
+		mutations, err := text.SchemaMutations(context.Background())
+		assert.Nil(t, err, name)
+		assert.Len(t, mutations, 1, name)
+		upsert, ok := mutations[0].(*upsertSchema)
+		assert.True(t, ok, name)
+		var fields []string
+		for _, f := range upsert.Fields {
+			fields = append(fields, f.Descriptor().Name)
+		}
+		assert.Equal(t, []string{"id", "tags"}, fields, name)
+	}
+}
@@ -0,0 +1,256 @@
+package entimport
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+)
+
+// integerBits holds the size in bits of the integer column types.
+var integerBits = map[string]int{
+	"tinyint":   8,
+	"int2":      16,
+	"smallint":  16,
+	"mediumint": 24,
+	"int":       32,
+	"int4":      32,
+	"integer":   32,
+	"int8":      64,
+	"bigint":    64,
+}
+
+var (
+	// reCheckCast matches the PostgreSQL type casts and the MySQL charset introducers of a CHECK expression.
+	reCheckCast = regexp.MustCompile(`::[a-z]+(?: varying| precision| with time zone| without time zone)?(?:\[\])?|_[a-z0-9]+'`)
+	// reCheckParens matches a parenthesized identifier, e.g. (name), that is not a function argument.
+	reCheckParens = regexp.MustCompile(`(^|[^\w])\(([\w"` + "`" + `]+)\)`)
+	// reCheckColumn matches the column a simple CHECK expression is about.
+	reCheckColumn = regexp.MustCompile(`^\(?(?i:(?:char_length|character_length|length|regexp_like)\()?["` + "`" + `]?(\w+)["` + "`" + `]?`)
+)
+
+// checkValidators maps the simple CHECK expressions to their validators. The column name is replaced
+// by "c" before matching, and the submatches are passed to the validator function.
+var checkValidators = []struct {
+	re    *regexp.Regexp
+	apply func(*field.Descriptor, []string)
+}{
+	{
+		re:    regexp.MustCompile(`^c\s*>\s*0$`),
+		apply: func(d *field.Descriptor, _ []string) { appendNumericValidator(d, "Positive") },
+	},
+	{
+		re:    regexp.MustCompile(`^c\s*>=\s*0$`),
+		apply: func(d *field.Descriptor, _ []string) { appendNumericValidator(d, "NonNegative") },
+	},
+	{
+		re: regexp.MustCompile(`^(?i:c\s+between\s+(-?\d+)\s+and\s+(-?\d+)|\(?c\s*>=\s*(-?\d+)\)?\s+and\s+\(?c\s*<=\s*(-?\d+)\)?)$`),
+		apply: func(d *field.Descriptor, m []string) {
+			lower, upper := m[1]+m[3], m[2]+m[4]
+			appendNumericValidator(d, "Range", numLit(lower), numLit(upper))
+		},
+	},
+	{
+		re:    regexp.MustCompile(`^(?i:(?:char_length|character_length|length)\(c\)\s*(?:>\s*0|>=\s*1)|c\s*(?:<>|!=)\s*'')$`),
+		apply: func(d *field.Descriptor, _ []string) { appendStringValidator(d, "NotEmpty") },
+	},
+	{
+		re: regexp.MustCompile(`^(?i:(?:char_length|character_length|length)\(c\)\s*<=\s*(\d+))$`),
+		apply: func(d *field.Descriptor, m []string) {
+			appendStringValidator(d, "MaxLen", numLit(m[1]))
+		},
+	},
+	{
+		re: regexp.MustCompile(`^(?i:c\s*(?:~|regexp|rlike)\s*'(.*)'|regexp_like\(c,\s*'(.*)'\))$`),
+		apply: func(d *field.Descriptor, m []string) {
+			expr := strings.ReplaceAll(m[1]+m[2], "''", "'")
+			if _, err := regexp.Compile(expr); err != nil {
+				return
+			}
+			appendStringValidator(d, "Match", call(selector("regexp", "MustCompile"), rawStrLit(expr)))
+		},
+	},
+}
+
+// applyValidators adds the validators derived from the column definition, i.e. the size of the
+// string columns, NOT NULL string columns without a default and the range of the integer columns.
+func applyValidators(dlct string, desc *field.Descriptor, col *schema.Column) {
+	switch typ := col.Type.Type.(type) {
+	case *schema.StringType:
+		if desc.Info.Type != field.TypeString {
+			return
+		}
+		if typ.Size > 0 {
+			appendStringValidator(desc, "MaxLen", intLit(typ.Size))
+		}
+		if !col.Type.Null && col.Default == nil {
+			appendStringValidator(desc, "NotEmpty")
+		}
+	case *schema.IntegerType:
+		applyIntegerRange(dlct, desc, typ)
+	}
+}
+
+// applyIntegerRange limits the values of an integer field when its Go type is wider than the column.
+func applyIntegerRange(dlct string, desc *field.Descriptor, typ *schema.IntegerType) {
+	bits, ok := integerBits[strings.ToLower(typ.T)]
+	// SQLite stores all integers as 8 bytes, whatever the declared type.
+	if !ok || dlct == dialect.SQLite {
+		bits = 64
+	}
+	var (
+		minValue = int64(-1) << (bits - 1)
+		maxValue = uint64(1)<<(bits-1) - 1
+	)
+	if typ.Unsigned {
+		minValue, maxValue = 0, uint64(1)<<(bits-1)<<1-1
+	}
+	fieldBits := 64
+	switch desc.Info.Type {
+	case field.TypeInt8, field.TypeUint8:
+		fieldBits = 8
+	case field.TypeInt16, field.TypeUint16:
+		fieldBits = 16
+	case field.TypeInt32, field.TypeUint32:
+		fieldBits = 32
+	}
+	switch t := desc.Info.Type; {
+	case t >= field.TypeUint8 && t <= field.TypeUint64:
+		fieldMax := uint64(1)<<(fieldBits-1)<<1 - 1
+		if typ.Unsigned && maxValue < fieldMax {
+			appendValidator(desc, "Max", "", uintLit(maxValue))
+		}
+	case t.Integer():
+		fieldMin, fieldMax := int64(-1)<<(fieldBits-1), uint64(1)<<(fieldBits-1)-1
+		switch {
+		case minValue == 0 && maxValue >= fieldMax:
+			appendValidator(desc, "NonNegative", "")
+		case minValue > fieldMin || maxValue < fieldMax:
+			appendValidator(desc, "Range", "", numLit(strconv.FormatInt(minValue, 10)), uintLit(maxValue))
+		}
+	}
+}
+
+// applyChecks adds the validators derived from the simple CHECK constraints of a table.
+func applyChecks(table *schema.Table, fields map[string]ent.Field) {
+	for _, attr := range table.Attrs {
+		check, ok := attr.(*schema.Check)
+		if !ok {
+			continue
+		}
+		expr := normalizeCheck(check.Expr)
+		m := reCheckColumn.FindStringSubmatch(expr)
+		if m == nil {
+			continue
+		}
+		f, ok := fields[m[1]]
+		if !ok {
+			continue
+		}
+		// Replace the column with a placeholder, so the expressions can be matched regardless of the name.
+		re := regexp.MustCompile(`["` + "`" + `]?\b` + regexp.QuoteMeta(m[1]) + `\b["` + "`" + `]?`)
+		parts := strings.Split(expr, "'")
+		for i := 0; i < len(parts); i += 2 {
+			parts[i] = re.ReplaceAllString(parts[i], "c")
+		}
+		expr = strings.Join(parts, "'")
+		for _, v := range checkValidators {
+			if sm := v.re.FindStringSubmatch(expr); sm != nil {
+				v.apply(f.Descriptor(), sm)
+				break
+			}
+		}
+	}
+}
+
+// normalizeCheck strips the casts and the redundant parentheses the databases add to CHECK expressions.
+func normalizeCheck(expr string) string {
+	expr = strings.ReplaceAll(expr, `\'`, "'")
+	expr = strings.TrimSpace(reCheckCast.ReplaceAllStringFunc(expr, func(s string) string {
+		if strings.HasSuffix(s, "'") {
+			return "'"
+		}
+		return ""
+	}))
+	for {
+		stripped := reCheckParens.ReplaceAllString(expr, "$1$2")
+		if len(stripped) > 1 && stripped[0] == '(' && stripped[len(stripped)-1] == ')' && balanced(stripped[1:len(stripped)-1]) {
+			stripped = strings.TrimSpace(stripped[1 : len(stripped)-1])
+		}
+		if stripped == expr {
+			return expr
+		}
+		expr = stripped
+	}
+}
+
+// balanced reports if the parentheses of the given expression are balanced.
+func balanced(expr string) bool {
+	depth := 0
+	for _, r := range expr {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// appendNumericValidator adds a validator to a signed numeric field.
+func appendNumericValidator(desc *field.Descriptor, method string, args ...ast.Expr) {
+	if t := desc.Info.Type; (t.Integer() && t < field.TypeUint8) || (t.Float() && !isDecimal(desc)) {
+		appendValidator(desc, method, "", args...)
+	}
+}
+
+// appendStringValidator adds a validator to a string field.
+func appendStringValidator(desc *field.Descriptor, method string, args ...ast.Expr) {
+	if desc.Info.Type != field.TypeString {
+		return
+	}
+	importPath := ""
+	if method == "Match" {
+		importPath = "regexp"
+	}
+	appendValidator(desc, method, importPath, args...)
+}
+
+// appendValidator adds a validator call to the field, unless the field already has one with the same method.
+func appendValidator(desc *field.Descriptor, method, importPath string, args ...ast.Expr) {
+	for _, annot := range desc.Annotations {
+		if calls, ok := annot.(builderCalls); ok {
+			for _, c := range calls {
+				if c.method == method {
+					return
+				}
+			}
+		}
+	}
+	appendBuilderCall(desc, method, importPath, args...)
+}
+
+func numLit(s string) ast.Expr {
+	return &ast.BasicLit{Kind: token.INT, Value: s}
+}
+
+func uintLit(u uint64) ast.Expr {
+	return numLit(strconv.FormatUint(u, 10))
+}
+
+func rawStrLit(s string) ast.Expr {
+	if !strings.Contains(s, "`") {
+		return &ast.BasicLit{Kind: token.STRING, Value: "`" + s + "`"}
+	}
+	return strLit(s)
+}
@@ -0,0 +1,191 @@
+package entimport
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"entgo.io/contrib/schemast"
+	"entgo.io/ent/dialect"
+	entschema "entgo.io/ent/schema"
+
+	"github.com/tx7do/go-wind-toolkit/generators/dbview"
+)
+
+// inspectViews returns the views of the given database schema with their columns. The included and
+// excluded tables apply to the views as well. The views that cannot be inspected are reported and left out.
+func (i *ImportOptions) inspectViews(ctx context.Context, schemaName string) ([]*schema.View, error) {
+	if i.driver.DB == nil {
+		return nil, nil
+	}
+	dbViews, err := dbview.Views(ctx, i.driver.DB, i.driver.Dialect, schemaName)
+	if err != nil {
+		return nil, i.fail("", "", fmt.Errorf("entimport: inspect views: %w", err))
+	}
+	var views []*schema.View
+	for _, dv := range dbViews {
+		if !i.importedTable(schemaName, dv.Name) {
+			continue
+		}
+		v := schema.NewView(dv.Name, dv.Def).SetSchema(schema.New(schemaName))
+		if err = i.inspectViewColumns(ctx, v); err != nil {
+			if err = i.fail(i.qualify(v.Schema, v.Name), "", err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+// inspectViewColumns adds the columns of a view, with their type parsed by the Atlas driver of the dialect.
+func (i *ImportOptions) inspectViewColumns(ctx context.Context, v *schema.View) error {
+	columns, err := dbview.Columns(ctx, i.driver.DB, i.driver.Dialect, v.Schema.Name, v.Name)
+	if err != nil {
+		return fmt.Errorf("entimport: inspect columns of view %v: %w", v.Name, err)
+	}
+	for _, c := range columns {
+		var typ schema.Type
+		switch i.driver.Dialect {
+		case dialect.MySQL:
+			typ, err = mysql.ParseType(c.Type)
+		case dialect.Postgres:
+			typ, err = postgres.ParseType(c.Type)
+		default:
+			typ, err = sqlite.ParseType(strings.ToLower(c.Type))
+		}
+		if err != nil {
+			return fmt.Errorf("entimport: column %v of view %v: %w", c.Name, v.Name, err)
+		}
+		v.AddColumns(&schema.Column{
+			Name: c.Name,
+			Type: &schema.ColumnType{Type: typ, Raw: c.Type, Null: c.Null},
+		})
+	}
+	return nil
+}
+
+// importedTable reports if a table or a view is imported, given the included and excluded tables.
+// The tables are matched by their name, or by their name qualified with the schema, e.g. "auth.users".
+func (i *ImportOptions) importedTable(schemaName, name string) bool {
+	return i.filter.Match(schemaName, name)
+}
+
+// filterTables returns the inspected tables that are imported.
+func (i *ImportOptions) filterTables(tables []*schema.Table) []*schema.Table {
+	var imported []*schema.Table
+	for _, t := range tables {
+		schemaName := ""
+		if t.Schema != nil {
+			schemaName = t.Schema.Name
+		}
+		if i.importedTable(schemaName, t.Name) {
+			imported = append(imported, t)
+		}
+	}
+	return imported
+}
+
+// upsertView handles the creation of a read-only ent view from a given database view. The view keeps
+// its definition for the dialect, so that the migrations are able to create it.
+func (i *ImportOptions) upsertView(field fieldFunc, view *schema.View) (*schemast.UpsertSchema, error) {
+	upsert := &schemast.UpsertSchema{
+		Name: i.typeName(i.qualify(view.Schema, view.Name)),
+		Annotations: []entschema.Annotation{
+			viewAnnotation{},
+			schemaAnnotation{
+				expr:    viewFor(view.Name, i.driver.Dialect, view.Def),
+				imports: []string{"entgo.io/ent/dialect/entsql"},
+			},
+		},
+	}
+	if i.multiSchema() && view.Schema != nil {
+		upsert.Annotations = append(upsert.Annotations, schemaAnnotation{
+			expr:    call(selector("entsql", "Schema"), strLit(view.Schema.Name)),
+			imports: []string{"entgo.io/ent/dialect/entsql"},
+		})
+	}
+	for _, column := range view.Columns {
+		f, err := field(column)
+		if err != nil {
+			return nil, err
+		}
+		// The views are read-only: the defaults and the validators of the fields do not apply.
+		desc := f.Descriptor()
+		desc.Default, desc.UpdateDefault, desc.Unique, desc.Validators = nil, nil, false, nil
+		desc.Annotations, _ = splitBuilderCalls(desc.Annotations)
+		upsert.Fields = append(upsert.Fields, f)
+	}
+	return upsert, nil
+}
+
+// viewFor returns the entsql annotation naming a view and holding its definition for the given dialect.
+func viewFor(name, dlct, def string) ast.Expr {
+	viewFor := &ast.CompositeLit{
+		Type: &ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("string")},
+		Elts: []ast.Expr{&ast.KeyValueExpr{Key: strLit(dlct), Value: strLit(def)}},
+	}
+	return &ast.CompositeLit{
+		Type: selector("entsql", "Annotation"),
+		Elts: []ast.Expr{
+			&ast.KeyValueExpr{Key: ast.NewIdent("Table"), Value: strLit(name)},
+			&ast.KeyValueExpr{Key: ast.NewIdent("ViewFor"), Value: viewFor},
+		},
+	}
+}
+
+// viewAnnotation marks the schemas generated for views. schemast prints them as ent.Schema types,
+// so upsertSchema turns them into ent.View types afterward.
+type viewAnnotation struct{}
+
+// Name implements the schema.Annotation interface.
+func (viewAnnotation) Name() string {
+	return "EntImportView"
+}
+
+// setView makes the given type embed ent.View instead of ent.Schema, and removes its edges.
+func setView(file *ast.File, typeName string) {
+	decls := file.Decls[:0]
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != typeName {
+					continue
+				}
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					for _, fd := range st.Fields.List {
+						if sel, ok := fd.Type.(*ast.SelectorExpr); ok && len(fd.Names) == 0 && sel.Sel.Name == "Schema" {
+							sel.Sel.Name = "View"
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Name.Name == "Edges" && receiverName(d) == typeName {
+				continue
+			}
+		}
+		decls = append(decls, decl)
+	}
+	file.Decls = decls
+}
+
+// receiverName returns the type name of the receiver of a method.
+func receiverName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) != 1 {
+		return ""
+	}
+	if id, ok := fd.Recv.List[0].Type.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
@@ -0,0 +1,225 @@
+// Package entmigrate writes the versioned migrations of an ent schema, and reports the drift of a database from
+// it, both computed by Atlas.
+package entmigrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqltool"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	entschema "entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/entc"
+	"entgo.io/ent/entc/gen"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
+)
+
+// Formats of the migration directories.
+const (
+	FormatAtlas         = "atlas"
+	FormatGolangMigrate = "golang-migrate"
+	FormatGoose         = "goose"
+)
+
+// DefaultDevURL is the dev database of the diff: an in-memory SQLite database.
+const DefaultDevURL = "sqlite://file:dev?mode=memory&_fk=1"
+
+// Options are the settings of Diff.
+type Options struct {
+	// SchemaPath is the directory of the ent schema package.
+	SchemaPath string
+	// Dir is the migration directory.
+	Dir string
+	// Format is the format of the migration directory: "atlas" (default), "golang-migrate" or "goose".
+	Format string
+	// DevURL is the dev database computing the diff, which must be clean, DefaultDevURL if empty.
+	DevURL string
+	// Baseline is the DDL file of the schema the migration directory starts from, e.g. the imported schema,
+	// loaded in the dev database before the migrations.
+	Baseline string
+}
+
+// Diff writes the migration named name that brings the schema of the migration directory to the ent schema.
+// The current schema is computed by replaying the migration directory, after the baseline if any, on the
+// dev database. The atlas.sum file of the directory is written with the migration. No migration is written
+// when the schemas are the same.
+func Diff(ctx context.Context, name string, o Options) error {
+	if o.SchemaPath == "" {
+		return errors.New("entmigrate: ent schema path is empty")
+	}
+	tables, err := LoadTables(o.SchemaPath)
+	if err != nil {
+		return err
+	}
+	return DiffTables(ctx, name, tables, o)
+}
+
+// DiffTables writes the migration named name that brings the schema of the migration directory to the tables,
+// see Diff. The SchemaPath of the options is not used.
+func DiffTables(ctx context.Context, name string, tables []*entschema.Table, o Options) error {
+	if name == "" {
+		return errors.New("entmigrate: migration name is empty")
+	}
+	if o.Dir == "" {
+		return errors.New("entmigrate: migration directory is empty")
+	}
+	if o.DevURL == "" {
+		o.DevURL = DefaultDevURL
+	}
+
+	if err := os.MkdirAll(o.Dir, os.ModePerm); err != nil {
+		return fmt.Errorf("entmigrate: create migration directory: %w", err)
+	}
+	dir, formatter, err := openDir(o.Dir, o.Format)
+	if err != nil {
+		return err
+	}
+
+	drv, err := openDatabase(ctx, o.DevURL)
+	if err != nil {
+		return fmt.Errorf("entmigrate: open dev database: %w", err)
+	}
+	defer drv.Close()
+
+	opts := []entschema.MigrateOption{
+		entschema.WithDir(dir),
+		entschema.WithFormatter(formatter),
+		entschema.WithDialect(drv.Dialect),
+		entschema.WithDropColumn(true),
+		entschema.WithDropIndex(true),
+		entschema.WithMigrationMode(entschema.ModeReplay),
+	}
+	if o.Baseline != "" {
+		// ent replays the migrations on a clean database only, so the baseline and the migrations are
+		// applied here, and the current schema is inspected.
+		if err = applyBaseline(ctx, drv, o.Baseline, dir); err != nil {
+			return err
+		}
+		opts[len(opts)-1] = entschema.WithMigrationMode(entschema.ModeInspect)
+	}
+
+	m, err := entschema.NewMigrate(entsql.OpenDB(drv.Dialect, drv.DB), opts...)
+	if err != nil {
+		return fmt.Errorf("entmigrate: %w", err)
+	}
+	if err = m.NamedDiff(ctx, name, tables...); err != nil {
+		return fmt.Errorf("entmigrate: diff: %w", err)
+	}
+	return nil
+}
+
+// LoadTables loads the ent schema package of the directory and returns its tables.
+func LoadTables(schemaPath string) ([]*entschema.Table, error) {
+	graph, err := entc.LoadGraph(schemaPath, &gen.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("entmigrate: load ent schema: %w", err)
+	}
+	tables, err := graph.Tables()
+	if err != nil {
+		return nil, fmt.Errorf("entmigrate: ent schema tables: %w", err)
+	}
+	return tables, nil
+}
+
+// openDir opens the migration directory of the format, with the formatter of its files.
+func openDir(path, format string) (migrate.Dir, migrate.Formatter, error) {
+	var (
+		dir       migrate.Dir
+		formatter migrate.Formatter
+		err       error
+	)
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatAtlas:
+		dir, err = migrate.NewLocalDir(path)
+		formatter = migrate.DefaultFormatter
+	case FormatGolangMigrate:
+		dir, err = sqltool.NewGolangMigrateDir(path)
+		formatter = sqltool.GolangMigrateFormatter
+	case FormatGoose:
+		dir, err = sqltool.NewGooseDir(path)
+		formatter = sqltool.GooseFormatter
+	default:
+		return nil, nil, fmt.Errorf("entmigrate: unsupported migration format %q", format)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("entmigrate: open migration directory: %w", err)
+	}
+	return dir, formatter, nil
+}
+
+// openDatabase opens the database of the DSN for ent. The DSN must be a MySQL, PostgreSQL or SQLite one.
+func openDatabase(ctx context.Context, dsn string) (*mux.ImportDriver, error) {
+	drv, err := mux.Default.OpenImport(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if drv.DB == nil {
+		if drv.Closer != nil {
+			_ = drv.Close()
+		}
+		return nil, fmt.Errorf("%q is not a database", dsn)
+	}
+	if drv.Dialect == dialect.SQLite {
+		// Each connection to an in-memory SQLite database opens its own database, and ent requires
+		// the foreign keys to be enforced.
+		drv.DB.SetMaxOpenConns(1)
+		if _, err = drv.DB.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+			_ = drv.Close()
+			return nil, err
+		}
+	}
+	return drv, nil
+}
+
+// execDDL executes the statements of a DDL file on the database.
+func execDDL(ctx context.Context, drv *mux.ImportDriver, path string) error {
+	ddl, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return execStmts(ctx, drv, filepath.Base(path), ddl)
+}
+
+// execStmts executes the statements of a DDL on the database, named after its file.
+func execStmts(ctx context.Context, drv *mux.ImportDriver, name string, ddl []byte) error {
+	stmts, err := migrate.NewLocalFile(name, ddl).Stmts()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err = drv.DB.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyBaseline loads the baseline DDL in the dev database, then replays the migration directory on it.
+func applyBaseline(ctx context.Context, drv *mux.ImportDriver, baseline string, dir migrate.Dir) error {
+	if err := execDDL(ctx, drv, baseline); err != nil {
+		return fmt.Errorf("entmigrate: apply baseline: %w", err)
+	}
+
+	atDriver, ok := drv.Inspector.(migrate.Driver)
+	if !ok {
+		return fmt.Errorf("entmigrate: dev database of dialect %q cannot replay migrations", drv.Dialect)
+	}
+	if err := migrate.Validate(dir); err != nil {
+		return fmt.Errorf("entmigrate: validate migration directory: %w", err)
+	}
+	ex, err := migrate.NewExecutor(atDriver, dir, &migrate.NopRevisionReadWriter{}, migrate.WithAllowDirty(true))
+	if err != nil {
+		return fmt.Errorf("entmigrate: %w", err)
+	}
+	if err = ex.ExecuteN(ctx, 0); err != nil && !errors.Is(err, migrate.ErrNoPendingFiles) {
+		return fmt.Errorf("entmigrate: replay migrations: %w", err)
+	}
+	return nil
+}
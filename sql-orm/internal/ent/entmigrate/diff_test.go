@@ -0,0 +1,100 @@
+package entmigrate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	entschema "entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
+	"github.com/stretchr/testify/require"
+)
+
+// tables returns the tables of the users and their pets.
+func tables() []*entschema.Table {
+	userID := &entschema.Column{Name: "id", Type: field.TypeInt, Increment: true}
+	users := entschema.NewTable("users").
+		AddPrimary(userID).
+		AddColumn(&entschema.Column{Name: "name", Type: field.TypeString, Size: 64}).
+		AddColumn(&entschema.Column{Name: "email", Type: field.TypeString, Unique: true, Nullable: true})
+	ownerID := &entschema.Column{Name: "user_pets", Type: field.TypeInt, Nullable: true}
+	pets := entschema.NewTable("pets").
+		AddPrimary(&entschema.Column{Name: "id", Type: field.TypeInt, Increment: true}).
+		AddColumn(&entschema.Column{Name: "name", Type: field.TypeString}).
+		AddColumn(ownerID).
+		AddForeignKey(&entschema.ForeignKey{
+			Symbol:     "pets_users_pets",
+			Columns:    []*entschema.Column{ownerID},
+			RefTable:   users,
+			RefColumns: []*entschema.Column{userID},
+			OnDelete:   entschema.SetNull,
+		})
+	return []*entschema.Table{users, pets}
+}
+
+func TestDiff(t *testing.T) {
+	r := require.New(t)
+	dir := filepath.Join(t.TempDir(), "migrations")
+	ctx := context.Background()
+
+	r.NoError(DiffTables(ctx, "init", tables(), Options{Dir: dir}))
+	files, err := os.ReadDir(dir)
+	r.NoError(err)
+	r.Len(files, 2)
+	r.Equal("atlas.sum", files[1].Name())
+	r.Regexp(`^\d{14}_init\.sql$`, files[0].Name())
+	b, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	r.NoError(err)
+	r.Contains(string(b), "CREATE TABLE `users`")
+	r.Contains(string(b), "CREATE TABLE `pets`")
+
+	// The migrations are replayed, so the schema is up to date.
+	r.NoError(DiffTables(ctx, "noop", tables(), Options{Dir: dir}))
+	files, err = os.ReadDir(dir)
+	r.NoError(err)
+	r.Len(files, 2)
+}
+
+func TestDiffBaseline(t *testing.T) {
+	r := require.New(t)
+	tmp := t.TempDir()
+	baseline := filepath.Join(tmp, "baseline.sql")
+	r.NoError(os.WriteFile(baseline, []byte("CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` text NOT NULL);\n"), 0o644))
+	dir := filepath.Join(tmp, "migrations")
+
+	r.NoError(DiffTables(context.Background(), "add_pets", tables(), Options{
+		Dir:      dir,
+		Format:   FormatGolangMigrate,
+		Baseline: baseline,
+	}))
+	files, err := os.ReadDir(dir)
+	r.NoError(err)
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	r.Len(names, 3)
+	r.Regexp(`^\d{14}_add_pets\.down\.sql$`, names[0])
+	r.Regexp(`^\d{14}_add_pets\.up\.sql$`, names[1])
+	r.Equal("atlas.sum", names[2])
+	b, err := os.ReadFile(filepath.Join(dir, names[1]))
+	r.NoError(err)
+	r.Contains(string(b), "CREATE TABLE `pets`")
+	r.NotContains(string(b), "CREATE TABLE `users`")
+
+	// The migrations are replayed after the baseline.
+	r.NoError(DiffTables(context.Background(), "noop", tables(), Options{
+		Dir:      dir,
+		Format:   FormatGolangMigrate,
+		Baseline: baseline,
+	}))
+	files, err = os.ReadDir(dir)
+	r.NoError(err)
+	r.Len(files, 3)
+}
+
+func TestOpenDir(t *testing.T) {
+	_, _, err := openDir(t.TempDir(), "flyway")
+	require.EqualError(t, err, `entmigrate: unsupported migration format "flyway"`)
+}
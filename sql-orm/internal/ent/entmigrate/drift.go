@@ -0,0 +1,374 @@
+package entmigrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	entschema "entgo.io/ent/dialect/sql/schema"
+
+	"github.com/tx7do/go-wind-toolkit/generators/tablefilter"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/mux"
+)
+
+// Kinds of the drift changes, from the point of view of the database.
+const (
+	// Added is an object of the database missing from the ent schema.
+	Added = "added"
+	// Removed is an object of the ent schema missing from the database.
+	Removed = "removed"
+	// Changed is an object that differs between the database and the ent schema.
+	Changed = "changed"
+)
+
+// Objects of the drift changes.
+const (
+	ObjectTable      = "table"
+	ObjectColumn     = "column"
+	ObjectIndex      = "index"
+	ObjectPrimaryKey = "primary_key"
+	ObjectForeignKey = "foreign_key"
+	ObjectCheck      = "check"
+)
+
+// migrationTables are the revision tables of the migration tools, which are not in the ent schema.
+var migrationTables = []string{"atlas_schema_revisions", "schema_migrations", "goose_db_version", "flyway_schema_history"}
+
+// DriftOptions are the settings of Drift.
+type DriftOptions struct {
+	// SchemaPath is the directory of the ent schema package.
+	SchemaPath string
+	// DSN is the database to compare, or a schema dump, given as a DDL file, e.g. "file://dump.sql", or
+	// as "text://<sql>", which is loaded in the dev database.
+	DSN string
+	// DevURL is the dev database loading a schema dump, DefaultDevURL if empty.
+	DevURL string
+	// IncludeTables and ExcludeTables select the tables to compare, see tablefilter.Filter. The revision
+	// tables of the migration tools are always left out.
+	IncludeTables, ExcludeTables []string
+}
+
+// DriftChange is a difference between the database and the ent schema.
+type DriftChange struct {
+	// Kind is Added, Removed or Changed.
+	Kind string `json:"kind"`
+	// Object is the kind of the object: table, column, index, primary_key, foreign_key or check.
+	Object string `json:"object"`
+	Table  string `json:"table"`
+	// Name is the name of the object, but for the tables and primary keys.
+	Name string `json:"name,omitempty"`
+	// Detail describes the change of a changed object, from the database to the ent schema.
+	Detail string `json:"detail,omitempty"`
+}
+
+// DriftReport lists the differences between the database and the ent schema.
+type DriftReport struct {
+	Changes []DriftChange `json:"changes"`
+}
+
+// HasDrift reports if the database differs from the ent schema.
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Changes) > 0
+}
+
+// WriteText writes the report for humans, the changes grouped by table: "+" for the objects of the
+// database only, "-" for those of the ent schema only and "~" for the changed ones.
+func (r *DriftReport) WriteText(w io.Writer) error {
+	if !r.HasDrift() {
+		_, err := fmt.Fprintln(w, "entmigrate: no drift between the database and the ent schema")
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "entmigrate: %d differences between the database (+) and the ent schema (-)\n", len(r.Changes))
+	table := ""
+	for _, c := range r.Changes {
+		if c.Table != table {
+			table = c.Table
+			b.WriteString(table + ":\n")
+		}
+		sign := map[string]string{Added: "+", Removed: "-", Changed: "~"}[c.Kind]
+		line := "  " + sign + " " + strings.ReplaceAll(c.Object, "_", " ")
+		if c.Name != "" {
+			line += " " + c.Name
+		}
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		b.WriteString(line + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the report as JSON.
+func (r *DriftReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Drift   bool          `json:"drift"`
+		Changes []DriftChange `json:"changes"`
+	}{r.HasDrift(), append([]DriftChange{}, r.Changes...)})
+}
+
+// Drift compares the tables of the database with those of the ent schema.
+func Drift(ctx context.Context, o DriftOptions) (*DriftReport, error) {
+	if o.SchemaPath == "" {
+		return nil, errors.New("entmigrate: ent schema path is empty")
+	}
+	tables, err := LoadTables(o.SchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return DriftTables(ctx, tables, o)
+}
+
+// DriftTables compares the tables of the database with the tables, see Drift. The SchemaPath of the
+// options is not used.
+func DriftTables(ctx context.Context, tables []*entschema.Table, o DriftOptions) (*DriftReport, error) {
+	if o.DSN == "" {
+		return nil, errors.New("entmigrate: dsn is empty")
+	}
+	filter, err := tablefilter.New(o.IncludeTables, append(o.ExcludeTables, migrationTables...))
+	if err != nil {
+		return nil, fmt.Errorf("entmigrate: %w", err)
+	}
+
+	drv, err := openDatabase(ctx, o.DSN)
+	if err != nil {
+		// A schema dump is loaded in the dev database.
+		if !isDump(o.DSN) {
+			return nil, fmt.Errorf("entmigrate: open database: %w", err)
+		}
+		if o.DevURL == "" {
+			o.DevURL = DefaultDevURL
+		}
+		if drv, err = openDatabase(ctx, o.DevURL); err != nil {
+			return nil, fmt.Errorf("entmigrate: open dev database: %w", err)
+		}
+		defer drv.Close()
+		if err = loadDump(ctx, drv, o.DSN); err != nil {
+			return nil, fmt.Errorf("entmigrate: load schema dump: %w", err)
+		}
+	} else {
+		defer drv.Close()
+	}
+
+	var changes []schema.Change
+	m, err := entschema.NewMigrate(entsql.OpenDB(drv.Dialect, drv.DB),
+		entschema.WithDir(&migrate.MemDir{}),
+		entschema.WithDialect(drv.Dialect),
+		entschema.WithDropColumn(true),
+		entschema.WithDropIndex(true),
+		entschema.WithMigrationMode(entschema.ModeInspect),
+		entschema.WithDiffHook(func(next entschema.Differ) entschema.Differ {
+			return entschema.DiffFunc(func(current, desired *schema.Schema) ([]schema.Change, error) {
+				selectTables(current, filter)
+				selectTables(desired, filter)
+				var err error
+				if changes, err = next.Diff(current, desired); err != nil {
+					return nil, err
+				}
+				// The changes are reported, not planned.
+				return nil, nil
+			})
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("entmigrate: %w", err)
+	}
+	if err = m.NamedDiff(ctx, "drift", tables...); err != nil {
+		return nil, fmt.Errorf("entmigrate: diff: %w", err)
+	}
+
+	// ent inspects the tables of its schema only, the others are added to the database.
+	s, err := drv.InspectSchema(ctx, "", &schema.InspectOptions{Mode: schema.InspectTables})
+	if err != nil {
+		return nil, fmt.Errorf("entmigrate: inspect database: %w", err)
+	}
+	selectTables(s, filter)
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t.Name] = true
+	}
+	for _, t := range s.Tables {
+		if !known[t.Name] {
+			changes = append(changes, &schema.DropTable{T: t})
+		}
+	}
+	return newDriftReport(changes, drv.Dialect), nil
+}
+
+// isDump reports if the DSN is a schema dump: "file://dump.sql" or "text://<sql>".
+func isDump(dsn string) bool {
+	return strings.HasPrefix(dsn, "file://") || strings.HasPrefix(dsn, "text://")
+}
+
+// loadDump executes the statements of a schema dump DSN on the database.
+func loadDump(ctx context.Context, drv *mux.ImportDriver, dsn string) error {
+	if text, ok := strings.CutPrefix(dsn, "text://"); ok {
+		return execStmts(ctx, drv, "dump.sql", []byte(text))
+	}
+	return execDDL(ctx, drv, strings.TrimPrefix(dsn, "file://"))
+}
+
+// selectTables keeps the tables of the schema selected by the filter.
+func selectTables(s *schema.Schema, filter *tablefilter.Filter) {
+	tables := s.Tables[:0]
+	for _, t := range s.Tables {
+		if filter.Match(s.Name, t.Name) {
+			tables = append(tables, t)
+		}
+	}
+	s.Tables = tables
+}
+
+// newDriftReport returns the report of the changes bringing the database to the ent schema.
+func newDriftReport(changes []schema.Change, dialectName string) *DriftReport {
+	r := &DriftReport{}
+	add := func(kind, object, table, name, detail string) {
+		r.Changes = append(r.Changes, DriftChange{Kind: kind, Object: object, Table: table, Name: name, Detail: detail})
+	}
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			add(Removed, ObjectTable, c.T.Name, "", "")
+		case *schema.DropTable:
+			add(Added, ObjectTable, c.T.Name, "", "")
+		case *schema.ModifyTable:
+			table := c.T.Name
+			for _, c := range c.Changes {
+				switch c := c.(type) {
+				case *schema.AddColumn:
+					add(Removed, ObjectColumn, table, c.C.Name, "")
+				case *schema.DropColumn:
+					add(Added, ObjectColumn, table, c.C.Name, "")
+				case *schema.ModifyColumn:
+					add(Changed, ObjectColumn, table, c.From.Name, columnDetail(c, dialectName))
+				case *schema.AddIndex:
+					add(Removed, ObjectIndex, table, c.I.Name, "")
+				case *schema.DropIndex:
+					add(Added, ObjectIndex, table, c.I.Name, "")
+				case *schema.ModifyIndex:
+					add(Changed, ObjectIndex, table, c.From.Name, changeKinds(c.Change))
+				case *schema.AddPrimaryKey:
+					add(Removed, ObjectPrimaryKey, table, "", "")
+				case *schema.DropPrimaryKey:
+					add(Added, ObjectPrimaryKey, table, "", "")
+				case *schema.ModifyPrimaryKey:
+					add(Changed, ObjectPrimaryKey, table, "", changeKinds(c.Change))
+				case *schema.AddForeignKey:
+					add(Removed, ObjectForeignKey, table, c.F.Symbol, "")
+				case *schema.DropForeignKey:
+					add(Added, ObjectForeignKey, table, c.F.Symbol, "")
+				case *schema.ModifyForeignKey:
+					add(Changed, ObjectForeignKey, table, c.From.Symbol, changeKinds(c.Change))
+				case *schema.AddCheck:
+					add(Removed, ObjectCheck, table, c.C.Name, "")
+				case *schema.DropCheck:
+					add(Added, ObjectCheck, table, c.C.Name, "")
+				case *schema.ModifyCheck:
+					add(Changed, ObjectCheck, table, c.From.Name, "")
+				case *schema.AddAttr, *schema.DropAttr, *schema.ModifyAttr:
+					add(Changed, ObjectTable, table, "", "attributes")
+				}
+			}
+		}
+	}
+	sort.SliceStable(r.Changes, func(i, j int) bool { return r.Changes[i].Table < r.Changes[j].Table })
+	return r
+}
+
+// columnDetail describes the change of a column, from the database to the ent schema.
+func columnDetail(c *schema.ModifyColumn, dialectName string) string {
+	var details []string
+	if c.Change.Is(schema.ChangeType) {
+		details = append(details, "type "+formatType(c.From.Type, dialectName)+" -> "+formatType(c.To.Type, dialectName))
+	}
+	if c.Change.Is(schema.ChangeNull) {
+		details = append(details, fmt.Sprintf("nullable %t -> %t", c.From.Type.Null, c.To.Type.Null))
+	}
+	if c.Change.Is(schema.ChangeDefault) {
+		details = append(details, "default "+formatDefault(c.From.Default)+" -> "+formatDefault(c.To.Default))
+	}
+	if other := c.Change &^ (schema.ChangeType | schema.ChangeNull | schema.ChangeDefault); other != schema.NoChange {
+		details = append(details, changeKinds(other))
+	}
+	return strings.Join(details, ", ")
+}
+
+// changeKinds lists the kinds of a change, e.g. "comment, unique".
+func changeKinds(k schema.ChangeKind) string {
+	names := map[schema.ChangeKind]string{
+		schema.ChangeAttr:         "attributes",
+		schema.ChangeCharset:      "charset",
+		schema.ChangeCollate:      "collation",
+		schema.ChangeComment:      "comment",
+		schema.ChangeNull:         "nullable",
+		schema.ChangeType:         "type",
+		schema.ChangeDefault:      "default",
+		schema.ChangeGenerated:    "generated",
+		schema.ChangeUnique:       "unique",
+		schema.ChangeParts:        "parts",
+		schema.ChangeColumn:       "columns",
+		schema.ChangeRefColumn:    "referenced columns",
+		schema.ChangeRefTable:     "referenced table",
+		schema.ChangeUpdateAction: "on update",
+		schema.ChangeDeleteAction: "on delete",
+	}
+	var kinds []string
+	for bit := schema.ChangeAttr; bit <= schema.ChangeDeleteAction; bit <<= 1 {
+		if k.Is(bit) {
+			kinds = append(kinds, names[bit])
+		}
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// formatType returns the SQL of a column type in the dialect.
+func formatType(t *schema.ColumnType, dialectName string) string {
+	if t == nil || t.Type == nil {
+		return "?"
+	}
+	var (
+		s   string
+		err error
+	)
+	switch dialectName {
+	case dialect.MySQL:
+		s, err = mysql.FormatType(t.Type)
+	case dialect.Postgres:
+		s, err = postgres.FormatType(t.Type)
+	case dialect.SQLite:
+		s, err = sqlite.FormatType(t.Type)
+	default:
+		err = errors.ErrUnsupported
+	}
+	if err != nil {
+		return t.Raw
+	}
+	return s
+}
+
+// formatDefault returns the SQL of a column default.
+func formatDefault(x schema.Expr) string {
+	switch x := x.(type) {
+	case *schema.Literal:
+		return x.V
+	case *schema.RawExpr:
+		return x.X
+	case nil:
+		return "none"
+	}
+	return "?"
+}
@@ -0,0 +1,85 @@
+package entmigrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftTables(t *testing.T) {
+	r := require.New(t)
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "test.db")
+	db, err := sql.Open("sqlite3", path)
+	r.NoError(err)
+	for _, ddl := range []string{
+		"CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` integer NOT NULL, `nickname` text NULL)",
+		"CREATE TABLE `audits` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT)",
+		"CREATE TABLE `schema_migrations` (`version` integer NOT NULL PRIMARY KEY)",
+	} {
+		_, err = db.Exec(ddl)
+		r.NoError(err)
+	}
+	r.NoError(db.Close())
+
+	report, err := DriftTables(context.Background(), tables(), DriftOptions{DSN: "sqlite://" + path})
+	r.NoError(err)
+	r.True(report.HasDrift())
+	r.Equal([]DriftChange{
+		{Kind: Added, Object: ObjectTable, Table: "audits"},
+		{Kind: Removed, Object: ObjectTable, Table: "pets"},
+		{Kind: Changed, Object: ObjectColumn, Table: "users", Name: "name", Detail: "type integer -> text"},
+		{Kind: Added, Object: ObjectColumn, Table: "users", Name: "nickname"},
+		{Kind: Removed, Object: ObjectColumn, Table: "users", Name: "email"},
+		{Kind: Removed, Object: ObjectIndex, Table: "users", Name: "users_email_key"},
+	}, report.Changes)
+
+	var text bytes.Buffer
+	r.NoError(report.WriteText(&text))
+	r.Contains(text.String(), "users:\n  ~ column name: type integer -> text\n  + column nickname\n")
+
+	var js bytes.Buffer
+	r.NoError(report.WriteJSON(&js))
+	r.Contains(js.String(), `"drift": true`)
+	r.Contains(js.String(), `"kind": "removed",`)
+}
+
+func TestDriftTablesDump(t *testing.T) {
+	r := require.New(t)
+	dump := filepath.Join(t.TempDir(), "dump.sql")
+	r.NoError(os.WriteFile(dump, []byte(
+		"CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` varchar(64) NOT NULL, `email` varchar(255) NULL);\n"+
+			"CREATE UNIQUE INDEX `users_email_key` ON `users` (`email`);\n",
+	), 0o644))
+
+	report, err := DriftTables(context.Background(), tables(), DriftOptions{
+		DSN:           "file://" + dump,
+		ExcludeTables: []string{"pets"},
+	})
+	r.NoError(err)
+	r.False(report.HasDrift(), "%+v", report.Changes)
+
+	var text bytes.Buffer
+	r.NoError(report.WriteText(&text))
+	r.Equal("entmigrate: no drift between the database and the ent schema\n", text.String())
+}
+
+func TestDriftTablesTextDump(t *testing.T) {
+	r := require.New(t)
+	report, err := DriftTables(context.Background(), tables(), DriftOptions{
+		DSN: "text://CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` varchar(64) NOT NULL);\n" +
+			"CREATE TABLE `audits` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT);\n",
+		ExcludeTables: []string{"pets"},
+	})
+	r.NoError(err)
+	r.Equal([]DriftChange{
+		{Kind: Added, Object: ObjectTable, Table: "audits"},
+		{Kind: Removed, Object: ObjectColumn, Table: "users", Name: "email"},
+		{Kind: Removed, Object: ObjectIndex, Table: "users", Name: "users_email_key"},
+	}, report.Changes)
+}
@@ -1,6 +1,7 @@
 package mux
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
 	"strings"
@@ -23,6 +24,12 @@ type (
 		schema.Inspector
 		Dialect    string
 		SchemaName string
+		// DB is the connection inspected by the driver, used to read what Atlas does not inspect, e.g. views.
+		// It is nil for the text driver.
+		DB *sql.DB
+		// Schemas lists the schemas to import when several are given, e.g. by a PostgreSQL search_path
+		// of "auth,billing,audit". SchemaName is the first of them.
+		Schemas []string
 	}
 )
 
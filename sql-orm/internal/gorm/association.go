@@ -0,0 +1,136 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/go-openapi/inflect"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/relation"
+)
+
+// association is an association field of a model, derived from a foreign key.
+type association struct {
+	kind  field.RelationshipType
+	name  string
+	table string
+	tag   field.GormTag
+}
+
+// relationships are the gen relationships of the association kinds.
+var relationships = map[relation.Kind]field.RelationshipType{
+	relation.BelongsTo:  field.BelongsTo,
+	relation.HasOne:     field.HasOne,
+	relation.HasMany:    field.HasMany,
+	relation.ManyToMany: field.Many2Many,
+}
+
+// associations returns the association fields of the models of the selected tables, by table name, with
+// their foreignKey and references tags, see relation.Associations. The models are named by the naming
+// strategy if set, otherwise by the naming of gorm.
+func associations(ctx context.Context, db *gorm.DB, selected func(string) bool, names *naming.Strategy) (map[string][]association, error) {
+	tables, err := inspectTables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	ns := db.NamingStrategy
+	rn := relation.Naming{
+		Field:  ns.SchemaName,
+		Type:   ns.SchemaName,
+		Plural: inflect.Pluralize,
+	}
+	if names != nil {
+		rn.Type, rn.Plural = names.TypeName, names.PluralTypeName
+	}
+	related := relation.Associations(tables, func(t *schema.Table) bool { return selected(t.Name) }, rn)
+
+	assocs := make(map[string][]association)
+	for t, list := range related {
+		for _, a := range list {
+			tag := field.GormTag{
+				"foreignKey": {ns.SchemaName(a.ForeignKey.Column)},
+				"references": {ns.SchemaName(a.ForeignKey.RefColumn)},
+			}
+			if a.Kind == relation.ManyToMany {
+				tag = field.GormTag{
+					"many2many":      {a.Join().Name},
+					"foreignKey":     {ns.SchemaName(a.ForeignKey.RefColumn)},
+					"joinForeignKey": {ns.SchemaName(a.ForeignKey.Column)},
+					"references":     {ns.SchemaName(a.JoinForeignKey.RefColumn)},
+					"joinReferences": {ns.SchemaName(a.JoinForeignKey.Column)},
+				}
+			}
+			assocs[t.Name] = append(assocs[t.Name], association{
+				kind:  relationships[a.Kind],
+				name:  a.Name,
+				table: a.Table.Name,
+				tag:   tag,
+			})
+		}
+	}
+	return assocs, nil
+}
+
+// inspectTables returns the tables of the database with their foreign keys, or none when the dialect of
+// the database cannot be inspected.
+func inspectTables(ctx context.Context, db *gorm.DB) ([]*schema.Table, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	var drv migrate.Driver
+	switch db.Dialector.Name() {
+	case "mysql":
+		drv, err = mysql.Open(sqlDB)
+	case "postgres":
+		drv, err = postgres.Open(sqlDB)
+	case "sqlite":
+		drv, err = sqlite.Open(sqlDB)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gormimport: open inspector: %w", err)
+	}
+	s, err := drv.InspectSchema(ctx, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("gormimport: inspect foreign keys: %w", err)
+	}
+	return s.Tables, nil
+}
+
+// relateFunc returns the option of an association field to the model of a table.
+type relateFunc func(kind field.RelationshipType, name string, config *field.RelateConfig) gen.ModelOpt
+
+// relateOptions returns the options of the association fields of a model, given the relateFunc of the
+// related tables.
+func (c *Config) relateOptions(assocs []association, related map[string]relateFunc) []gen.ModelOpt {
+	jsonTag, _ := c.jsonTagNS()
+	var opts []gen.ModelOpt
+	for _, a := range assocs {
+		relate, ok := related[a.table]
+		if !ok {
+			continue
+		}
+		config := &field.RelateConfig{
+			RelateSlice:   a.kind == field.HasMany || a.kind == field.Many2Many,
+			RelatePointer: a.kind == field.BelongsTo || a.kind == field.HasOne,
+			GORMTag:       a.tag,
+		}
+		if jsonTag != nil {
+			config.JSONTag = jsonTag(inflect.Underscore(a.name))
+		}
+		opts = append(opts, relate(a.kind, a.name, config))
+	}
+	return opts
+}
@@ -0,0 +1,245 @@
+package gorm
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/inflect"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+)
+
+// Config holds the settings of the gorm/gen generator.
+type Config struct {
+	// Mode lists the generate modes: "default_query", "query_interface" and "without_context".
+	Mode []string `yaml:"mode"`
+	// UnitTest generates the unit tests of the query code.
+	UnitTest bool `yaml:"unit_test"`
+	// FieldNullable generates a pointer for the nullable columns.
+	FieldNullable bool `yaml:"field_nullable"`
+	// FieldCoverable generates a pointer for the columns having a default value.
+	FieldCoverable bool `yaml:"field_coverable"`
+	// FieldSignable generates the unsigned integer types of the unsigned columns.
+	FieldSignable bool `yaml:"field_signable"`
+	// FieldWithIndexTag generates the gorm index tags.
+	FieldWithIndexTag bool `yaml:"field_with_index_tag"`
+	// FieldWithTypeTag generates the gorm column type tags.
+	FieldWithTypeTag bool `yaml:"field_with_type_tag"`
+	// JSONTag names the JSON tags after the columns: "snake" (default, the column name), "camel",
+	// "pascal", or "none" to skip the fields in JSON.
+	JSONTag string `yaml:"json_tag"`
+	// DataTypes maps the SQL types to Go types, e.g. "json: datatypes.JSON" or "tinyint(1): bool".
+	// A type with arguments is matched against the full column type, and takes precedence over its
+	// base type.
+	DataTypes map[string]string `yaml:"data_types"`
+	// Associations generates the BelongsTo, HasOne, HasMany and Many2Many fields of the foreign keys.
+	Associations bool `yaml:"associations"`
+	// Imports are the import paths of the packages of DataTypes. The paths of the datatypes, decimal and
+	// uuid packages are added for their types.
+	Imports []string `yaml:"imports"`
+	// Naming, if set, names the models and their files after the tables, as the ent, bun and sqlc code
+	// is named, instead of the naming of gorm.
+	Naming *naming.Strategy `yaml:"-"`
+}
+
+// Option modifies the Config of the generator.
+type Option func(*Config)
+
+// DefaultConfig returns the settings used when no configuration is given.
+func DefaultConfig() *Config {
+	return &Config{
+		Mode:              []string{"default_query", "query_interface", "without_context"},
+		FieldNullable:     true,
+		FieldCoverable:    true,
+		FieldSignable:     true,
+		FieldWithIndexTag: true,
+		FieldWithTypeTag:  true,
+		Associations:      true,
+	}
+}
+
+// LoadConfig reads the settings from a YAML file, for example:
+//
+//	mode: [default_query, query_interface]
+//	field_nullable: false
+//	json_tag: camel
+//	data_types:
+//	  json: datatypes.JSON
+//	  tinyint(1): bool
+//
+// The settings missing from the file keep their default value. An empty path returns DefaultConfig.
+func LoadConfig(path string) (*Config, error) {
+	c := DefaultConfig()
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gormimport: read config: %w", err)
+	}
+	if err = yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("gormimport: parse config %q: %w", path, err)
+	}
+	return c, nil
+}
+
+var genModes = map[string]gen.GenerateMode{
+	"default_query":   gen.WithDefaultQuery,
+	"query_interface": gen.WithQueryInterface,
+	"without_context": gen.WithoutContext,
+}
+
+// knownImports are the import paths of the packages commonly used in DataTypes.
+var knownImports = map[string]string{
+	"datatypes": "gorm.io/datatypes",
+	"decimal":   "github.com/shopspring/decimal",
+	"uuid":      "github.com/google/uuid",
+}
+
+// genConfig returns the gen.Config of the settings, writing the models to modelPath and the query code to outPath.
+func (c *Config) genConfig(outPath, modelPath string) (*gen.Config, error) {
+	cfg := &gen.Config{
+		OutPath:           outPath,
+		ModelPkgPath:      modelPath,
+		WithUnitTest:      c.UnitTest,
+		FieldNullable:     c.FieldNullable,
+		FieldCoverable:    c.FieldCoverable,
+		FieldSignable:     c.FieldSignable,
+		FieldWithIndexTag: c.FieldWithIndexTag,
+		FieldWithTypeTag:  c.FieldWithTypeTag,
+	}
+	for _, m := range c.Mode {
+		mode, ok := genModes[strings.TrimSpace(m)]
+		if !ok {
+			return nil, fmt.Errorf("gormimport: unknown mode %q, expected default_query, query_interface or without_context", m)
+		}
+		cfg.Mode |= mode
+	}
+
+	jsonTag, err := c.jsonTagNS()
+	if err != nil {
+		return nil, err
+	}
+	if jsonTag != nil {
+		cfg.WithJSONTagNameStrategy(jsonTag)
+	}
+
+	if c.Naming != nil {
+		cfg.WithModelNameStrategy(c.Naming.TypeName)
+		cfg.WithFileNameStrategy(c.Naming.Name)
+	}
+
+	if len(c.DataTypes) > 0 {
+		cfg.WithDataTypeMap(c.dataTypeMap())
+		cfg.WithImportPkgPath(c.importPaths()...)
+	}
+	return cfg, nil
+}
+
+// jsonTagNS returns the naming of the JSON tags after the column names, or nil to keep the column names.
+func (c *Config) jsonTagNS() (func(string) string, error) {
+	switch c.JSONTag {
+	case "", "snake":
+		return nil, nil
+	case "camel":
+		return inflect.CamelizeDownFirst, nil
+	case "pascal":
+		return inflect.Camelize, nil
+	case "none":
+		return func(string) string { return "-" }, nil
+	default:
+		return nil, fmt.Errorf("gormimport: unknown json tag %q, expected snake, camel, pascal or none", c.JSONTag)
+	}
+}
+
+// dataTypeMap returns the gen data type map of DataTypes, keyed by the base SQL type.
+func (c *Config) dataTypeMap() map[string]func(gorm.ColumnType) string {
+	types := make(map[string]map[string]string)
+	for sqlType, goType := range c.DataTypes {
+		sqlType = strings.ToLower(strings.TrimSpace(sqlType))
+		base, _, _ := strings.Cut(sqlType, "(")
+		base = strings.TrimSpace(base)
+		if types[base] == nil {
+			types[base] = make(map[string]string)
+		}
+		types[base][sqlType] = goType
+	}
+
+	m := make(map[string]func(gorm.ColumnType) string, len(types))
+	for base, mapping := range types {
+		m[base] = func(ct gorm.ColumnType) string {
+			full, _ := ct.ColumnType()
+			full = strings.ToLower(strings.TrimSpace(full))
+			// The longest matching type wins, e.g. "tinyint(1) unsigned" over "tinyint(1)".
+			var best string
+			for sqlType := range mapping {
+				if sqlType != base && strings.HasPrefix(full, sqlType) && len(sqlType) > len(best) {
+					best = sqlType
+				}
+			}
+			if best != "" {
+				return mapping[best]
+			}
+			if goType, ok := mapping[base]; ok {
+				return goType
+			}
+			return defaultGoType(base, full)
+		}
+	}
+	return m
+}
+
+// importPaths returns the import paths of the Go types of DataTypes.
+func (c *Config) importPaths() []string {
+	paths := make(map[string]bool)
+	for _, p := range c.Imports {
+		paths[p] = true
+	}
+	for _, goType := range c.DataTypes {
+		pkg, _, ok := strings.Cut(strings.TrimLeft(goType, "*[]"), ".")
+		if path, known := knownImports[pkg]; ok && known {
+			paths[path] = true
+		}
+	}
+	var list []string
+	for p := range paths {
+		list = append(list, p)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// defaultGoType returns the Go type gorm/gen generates for a SQL type, for the columns of a base type
+// of DataTypes that none of its types with arguments matches.
+func defaultGoType(base, full string) string {
+	switch base {
+	case "numeric", "integer", "int", "smallint", "mediumint", "year":
+		return "int32"
+	case "tinyint":
+		if strings.HasPrefix(full, "tinyint(1)") {
+			return "bool"
+		}
+		return "int32"
+	case "bigint":
+		return "int64"
+	case "float":
+		return "float32"
+	case "real", "double", "decimal":
+		return "float64"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "[]byte"
+	case "bit":
+		return "[]uint8"
+	case "time", "date", "datetime", "timestamp":
+		return "time.Time"
+	case "boolean":
+		return "bool"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	r := require.New(t)
+	path := filepath.Join(t.TempDir(), "gorm.yaml")
+	r.NoError(os.WriteFile(path, []byte(`
+mode: [default_query]
+field_nullable: false
+json_tag: camel
+data_types:
+  json: datatypes.JSON
+  tinyint(1): bool
+`), 0o644))
+
+	c, err := LoadConfig(path)
+	r.NoError(err)
+	r.Equal([]string{"default_query"}, c.Mode)
+	r.False(c.FieldNullable)
+	r.True(c.FieldWithTypeTag, "the settings missing from the file keep their default value")
+	r.Equal([]string{"gorm.io/datatypes"}, c.importPaths())
+
+	c.Mode = []string{"with_context"}
+	_, err = c.genConfig("daos", "models")
+	r.ErrorContains(err, `unknown mode "with_context"`)
+}
+
+func TestImporterConfig(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	drv := "mysql"
+	dsn := `text://CREATE TABLE settings (
+	id BIGINT NOT NULL,
+	enabled TINYINT(1) NOT NULL,
+	level TINYINT NOT NULL,
+	display_name VARCHAR(64),
+	payload JSON NOT NULL,
+	PRIMARY KEY (id)
+);`
+	schemaPath := filepath.Join(dir, "models")
+	daoPath := filepath.Join(dir, "daos")
+	config := DefaultConfig()
+	config.FieldNullable = false
+	config.JSONTag = "camel"
+	config.UnitTest = true
+	config.DataTypes = map[string]string{"json": "datatypes.JSON", "tinyint(1)": "bool"}
+
+	r.NoError(Importer(context.Background(), &drv, &dsn, &schemaPath, &daoPath, nil, nil, config))
+
+	model, err := os.ReadFile(filepath.Join(schemaPath, "settings.gen.go"))
+	r.NoError(err)
+	r.Contains(string(model), `"gorm.io/datatypes"`)
+	r.Regexp(`Enabled\s+bool\s`, string(model))
+	r.Regexp(`Level\s+int32\s`, string(model))
+	r.Regexp(`DisplayName\s+string\s.*json:"displayName"`, string(model))
+	r.Regexp(`Payload\s+datatypes.JSON\s`, string(model))
+	r.FileExists(filepath.Join(daoPath, "settings.gen_test.go"))
+}
@@ -0,0 +1,437 @@
+package gorm
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+	ddlparser "github.com/tx7do/go-utils/ddl_parser"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+)
+
+// ddlSource returns the DDL of an offline source, given as "text://<sql>", "file://<path>" or the
+// path of a .sql file, and reports if the DSN is such a source.
+func ddlSource(dsn string) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(dsn, "text://"):
+		return strings.TrimPrefix(dsn, "text://"), true, nil
+
+	case strings.HasPrefix(dsn, "file://"):
+		dsn = strings.TrimPrefix(dsn, "file://")
+
+	case !strings.HasSuffix(strings.ToLower(dsn), ".sql"):
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(dsn)
+	if err != nil {
+		return "", true, fmt.Errorf("gormimport: read ddl file: %w", err)
+	}
+	return string(content), true, nil
+}
+
+// openDDL creates the tables of the DDL in an in-memory SQLite database, so that the models can be
+// generated without the database. The DDL may be written for MySQL or PostgreSQL: the column types are
+// kept, which SQLite accepts as declared, and only the clauses that SQLite does not support are dropped.
+// The foreign keys between the tables of the DDL are kept for the associations of the models.
+func openDDL(ddl string) (*gorm.DB, error) {
+	tables, err := ddlparser.ParseCreateTables(ddl)
+	if err != nil {
+		return nil, fmt.Errorf("gormimport: parse ddl: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("gormimport: no CREATE TABLE statement in the ddl")
+	}
+	ddlUnsigned(ddl, tables)
+	return openTables(tables, ddlForeignKeys(ddl, tables))
+}
+
+// openSchema creates the tables of an Atlas schema, e.g. evaluated from Atlas HCL files, in an in-memory
+// SQLite database, the same way as openDDL.
+func openSchema(s *schema.Schema) (*gorm.DB, error) {
+	if len(s.Tables) == 0 {
+		return nil, errors.New("gormimport: no table in the schema")
+	}
+	var (
+		tables []*ddlparser.TableDef
+		fks    = make(map[string][]ddlForeignKey)
+	)
+	for _, t := range s.Tables {
+		def := &ddlparser.TableDef{Name: t.Name, Comment: schemaComment(t.Attrs)}
+		for _, c := range t.Columns {
+			cd := ddlparser.ColumnDef{Name: c.Name, Type: c.Type.Raw, Nullable: c.Type.Null, Comment: schemaComment(c.Attrs)}
+			if it, ok := c.Type.Type.(*schema.IntegerType); ok && it.Unsigned && !ddlUnsignedType.MatchString(cd.Type) {
+				cd.Type += " unsigned"
+			}
+			if t.PrimaryKey != nil {
+				cd.PrimaryKey = slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == c })
+			}
+			cd.Unique = slices.ContainsFunc(t.Indexes, func(idx *schema.Index) bool {
+				return idx.Unique && len(idx.Parts) == 1 && idx.Parts[0].C == c
+			})
+			switch d := c.Default.(type) {
+			case *schema.Literal:
+				cd.Default = d.V
+			case *schema.RawExpr:
+				cd.Default = d.X
+			}
+			def.Columns = append(def.Columns, cd)
+		}
+		tables = append(tables, def)
+		for _, fk := range t.ForeignKeys {
+			k := ddlForeignKey{refTable: fk.RefTable.Name}
+			for _, c := range fk.Columns {
+				k.columns = append(k.columns, c.Name)
+			}
+			for _, c := range fk.RefColumns {
+				k.refColumns = append(k.refColumns, c.Name)
+			}
+			fks[t.Name] = append(fks[t.Name], k)
+		}
+	}
+	return openTables(tables, fks)
+}
+
+// schemaComment returns the comment of a table or a column of an Atlas schema.
+func schemaComment(attrs []schema.Attr) string {
+	for _, attr := range attrs {
+		if c, ok := attr.(*schema.Comment); ok {
+			return c.Text
+		}
+	}
+	return ""
+}
+
+// openTables creates the tables in an in-memory SQLite database, with their foreign keys by table name.
+func openTables(tables []*ddlparser.TableDef, fks map[string][]ddlForeignKey) (*gorm.DB, error) {
+	defs := make(map[string]*ddlparser.TableDef, len(tables))
+	for _, t := range tables {
+		defs[ddlName(t.Name)] = t
+	}
+	db, err := gorm.Open(ddlDialector{Dialector: sqlite.Open("file::memory:"), tables: defs}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gormimport: open in-memory database: %w", err)
+	}
+	// The in-memory database lives as long as its connection.
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, t := range tables {
+		if err = db.Exec(sqliteCreateTable(t, fks[ddlName(t.Name)])).Error; err != nil {
+			return nil, fmt.Errorf("gormimport: create table %s: %w", t.Name, err)
+		}
+	}
+	return db, nil
+}
+
+var (
+	// ddlTypeArgs matches the arguments of a column type that are not sizes, e.g. those of ENUM('a', 'b').
+	ddlTypeArgs = regexp.MustCompile(`\(.*[^0-9,\s].*\)`)
+	// ddlLiteral matches the default values that SQLite accepts.
+	ddlLiteral = regexp.MustCompile(`(?i)^(-?[0-9.]+|'[^']*'|NULL|TRUE|FALSE|CURRENT_TIMESTAMP|CURRENT_DATE|CURRENT_TIME)$`)
+	// ddlCreate matches the beginning of a CREATE TABLE statement, up to the parenthesis of its definitions.
+	ddlCreate = regexp.MustCompile(`(?is)\bCREATE\s+(?:TEMP(?:ORARY)?\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)\s*\(`)
+	// ddlTableFK matches a FOREIGN KEY constraint of a table.
+	ddlTableFK = regexp.MustCompile(`(?is)^(?:CONSTRAINT\s+\S+\s+)?FOREIGN\s+KEY\s*(?:[^\s(]+\s*)?\(([^)]*)\)\s*REFERENCES\s+([^\s(]+)\s*(?:\(([^)]*)\))?`)
+	// ddlColumnFK matches the REFERENCES clause of a column.
+	ddlColumnFK = regexp.MustCompile(`(?is)\bREFERENCES\s+([^\s(]+)\s*(?:\(([^)]*)\))?`)
+	// ddlUnsignedType matches the UNSIGNED attribute of an integer column.
+	ddlUnsignedType = regexp.MustCompile(`(?i)\bUNSIGNED\b`)
+	// ddlConstraint matches the definitions of a table that are not columns.
+	ddlConstraint = regexp.MustCompile(`(?i)^(CONSTRAINT|PRIMARY|UNIQUE|KEY|INDEX|FOREIGN|CHECK|FULLTEXT|SPATIAL|EXCLUDE)\b`)
+)
+
+// ddlTypes maps the PostgreSQL type names and aliases to the names known by gorm/gen.
+var ddlTypes = map[string]string{
+	"character varying":           "varchar",
+	"character":                   "char",
+	"double precision":            "double",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamp",
+	"timestamptz":                 "timestamp",
+	"time without time zone":      "time",
+	"time with time zone":         "time",
+	"serial":                      "int",
+	"bigserial":                   "bigint",
+	"smallserial":                 "smallint",
+	"bool":                        "boolean",
+	"int4":                        "int",
+	"int8":                        "bigint",
+	"int2":                        "smallint",
+	"float8":                      "double",
+	"float4":                      "float",
+	"jsonb":                       "json",
+}
+
+// sqliteType returns the declared type of a column in the SQLite DDL, which does not take UNSIGNED.
+func sqliteType(typ string) string {
+	typ = strings.Join(strings.Fields(ddlUnsignedType.ReplaceAllString(typ, "")), " ")
+	typ = strings.TrimSpace(strings.TrimSuffix(typ, "[]"))
+	typ = ddlTypeArgs.ReplaceAllString(typ, "")
+	name, size := typ, ""
+	if i := strings.IndexByte(typ, '('); i >= 0 {
+		name, size = strings.TrimSpace(typ[:i]), typ[i:]
+	}
+	if mapped, ok := ddlTypes[strings.ToLower(name)]; ok {
+		name = mapped
+	}
+	if name == "" {
+		return "text"
+	}
+	return name + size
+}
+
+// ddlForeignKey is a foreign key of the DDL, which the DDL parser leaves out.
+type ddlForeignKey struct {
+	columns    []string
+	refTable   string
+	refColumns []string
+}
+
+// ddlForeignKeys returns the foreign keys of the CREATE TABLE statements of the DDL, by table name. The
+// foreign keys referencing a table missing from the DDL are left out, and those that do not name the
+// referenced columns get the primary key of the referenced table.
+func ddlForeignKeys(ddl string, tables []*ddlparser.TableDef) map[string][]ddlForeignKey {
+	keys := make(map[string][]string)
+	for _, t := range tables {
+		var pk []string
+		for _, c := range t.Columns {
+			if c.PrimaryKey {
+				pk = append(pk, ddlName(c.Name))
+			}
+		}
+		keys[ddlName(t.Name)] = pk
+	}
+
+	fks := make(map[string][]ddlForeignKey)
+	for _, m := range ddlCreate.FindAllStringSubmatchIndex(ddl, -1) {
+		table := ddlName(ddl[m[2]:m[3]])
+		for _, def := range ddlDefinitions(ddl[m[1]:]) {
+			var fk ddlForeignKey
+			if sub := ddlTableFK.FindStringSubmatch(def); sub != nil {
+				fk = ddlForeignKey{columns: ddlNames(sub[1]), refTable: ddlName(sub[2]), refColumns: ddlNames(sub[3])}
+			} else if ddlConstraint.MatchString(def) {
+				continue
+			} else if sub = ddlColumnFK.FindStringSubmatch(def); sub != nil {
+				fk = ddlForeignKey{columns: ddlNames(strings.Fields(def)[0]), refTable: ddlName(sub[1]), refColumns: ddlNames(sub[2])}
+			} else {
+				continue
+			}
+			pk, ok := keys[fk.refTable]
+			if !ok {
+				continue
+			}
+			if len(fk.refColumns) == 0 {
+				fk.refColumns = pk
+			}
+			if len(fk.columns) == 0 || len(fk.columns) != len(fk.refColumns) {
+				continue
+			}
+			fks[table] = append(fks[table], fk)
+		}
+	}
+	return fks
+}
+
+// ddlUnsigned restores the UNSIGNED attribute of the column types, which the DDL parser leaves out,
+// e.g. "int(10) unsigned" for INT(10) UNSIGNED.
+func ddlUnsigned(ddl string, tables []*ddlparser.TableDef) {
+	defs := make(map[string]*ddlparser.TableDef, len(tables))
+	for _, t := range tables {
+		defs[ddlName(t.Name)] = t
+	}
+	for _, m := range ddlCreate.FindAllStringSubmatchIndex(ddl, -1) {
+		t, ok := defs[ddlName(ddl[m[2]:m[3]])]
+		if !ok {
+			continue
+		}
+		for _, def := range ddlDefinitions(ddl[m[1]:]) {
+			if ddlConstraint.MatchString(def) || !ddlUnsignedType.MatchString(def) {
+				continue
+			}
+			name := ddlName(strings.Fields(def)[0])
+			for i, c := range t.Columns {
+				if ddlName(c.Name) == name && !ddlUnsignedType.MatchString(c.Type) {
+					t.Columns[i].Type += " unsigned"
+				}
+			}
+		}
+	}
+}
+
+// ddlDefinitions splits the definitions of a CREATE TABLE statement, given from its opening parenthesis on,
+// at the commas outside parentheses and quotes.
+func ddlDefinitions(body string) []string {
+	var (
+		defs  []string
+		depth = 1
+		quote rune
+		start int
+	)
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return append(defs, strings.TrimSpace(body[start:i]))
+			}
+		case r == ',' && depth == 1:
+			defs = append(defs, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return defs
+}
+
+// ddlName returns a table or column name of the DDL without its quotes nor schema qualifier.
+func ddlName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, "`\"[]")
+}
+
+// ddlNames returns the names of a comma-separated list of the DDL.
+func ddlNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = ddlName(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// sqliteCreateTable returns the CREATE TABLE statement of a parsed table for SQLite.
+func sqliteCreateTable(t *ddlparser.TableDef, fks []ddlForeignKey) string {
+	var (
+		defs []string
+		keys []string
+	)
+	for _, c := range t.Columns {
+		if c.PrimaryKey {
+			keys = append(keys, quoteIdent(c.Name))
+		}
+	}
+	for _, c := range t.Columns {
+		def := quoteIdent(c.Name) + " " + sqliteType(c.Type)
+		if !c.Nullable {
+			def += " NOT NULL"
+		}
+		if c.PrimaryKey && len(keys) == 1 {
+			def += " PRIMARY KEY"
+		}
+		if c.Unique && !c.PrimaryKey {
+			def += " UNIQUE"
+		}
+		if d := strings.TrimSpace(c.Default); d != "" && ddlLiteral.MatchString(d) {
+			def += " DEFAULT " + d
+		}
+		defs = append(defs, def)
+	}
+	if len(keys) > 1 {
+		defs = append(defs, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+	}
+	for _, fk := range fks {
+		defs = append(defs, "FOREIGN KEY ("+quoteIdents(fk.columns)+") REFERENCES "+quoteIdent(fk.refTable)+" ("+quoteIdents(fk.refColumns)+")")
+	}
+	return "CREATE TABLE " + quoteIdent(ddlName(t.Name)) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"
+}
+
+func quoteIdent(name string) string {
+	name = strings.Trim(name, "`\"[]")
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// ddlDialector is the SQLite dialector of an offline source, whose migrator reports the column types and
+// comments of the source. SQLite keeps neither the comments nor, for gorm, the types having several words
+// or arguments, e.g. DECIMAL(10,2) or INT UNSIGNED, which gorm/gen needs for the type tags and the
+// unsigned integers of the models.
+type ddlDialector struct {
+	gorm.Dialector
+	tables map[string]*ddlparser.TableDef
+}
+
+func (d ddlDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return ddlMigrator{Migrator: d.Dialector.Migrator(db), tables: d.tables}
+}
+
+type ddlMigrator struct {
+	gorm.Migrator
+	tables map[string]*ddlparser.TableDef
+}
+
+// TableType returns the comment of a table of the source.
+func (m ddlMigrator) TableType(value any) (gorm.TableType, error) {
+	name, _ := value.(string)
+	t, ok := m.tables[name]
+	if !ok {
+		return m.Migrator.TableType(value)
+	}
+	return migrator.TableType{
+		NameValue:    name,
+		TypeValue:    "BASE TABLE",
+		CommentValue: sql.NullString{String: t.Comment, Valid: t.Comment != ""},
+	}, nil
+}
+
+// ColumnTypes returns the column types of a table, with the types and comments of the source.
+func (m ddlMigrator) ColumnTypes(value any) ([]gorm.ColumnType, error) {
+	types, err := m.Migrator.ColumnTypes(value)
+	name, _ := value.(string)
+	t, ok := m.tables[name]
+	if err != nil || !ok {
+		return types, err
+	}
+	for i, ct := range types {
+		c, ok := ct.(migrator.ColumnType)
+		if !ok {
+			continue
+		}
+		for _, def := range t.Columns {
+			if ddlName(def.Name) != c.Name() {
+				continue
+			}
+			typ := strings.ToLower(sqliteType(def.Type))
+			if ddlUnsignedType.MatchString(def.Type) {
+				typ += " unsigned"
+			}
+			c.ColumnTypeValue = sql.NullString{String: typ, Valid: true}
+			if def.Comment != "" {
+				c.CommentValue = sql.NullString{String: def.Comment, Valid: true}
+			}
+			types[i] = c
+		}
+	}
+	return types, nil
+}
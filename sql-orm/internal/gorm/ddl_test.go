@@ -0,0 +1,284 @@
+package gorm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ddlparser "github.com/tx7do/go-utils/ddl_parser"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+)
+
+const testDDL = `
+CREATE TABLE users (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	name VARCHAR(64) NOT NULL DEFAULT '',
+	role ENUM('admin', 'user') NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE user_logs (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	payload JSONB,
+	logged_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+`
+
+func TestImporterDDL(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	drv := "mysql"
+	dsn := "text://" + testDDL
+	schemaPath := filepath.Join(dir, "models")
+	daoPath := filepath.Join(dir, "daos")
+
+	err := Importer(context.Background(), &drv, &dsn, &schemaPath, &daoPath, nil, []string{"*_logs"}, nil)
+	r.NoError(err)
+
+	user, err := os.ReadFile(filepath.Join(schemaPath, "users.gen.go"))
+	r.NoError(err)
+	r.Contains(string(user), "ID        int64")
+	r.Contains(string(user), `gorm:"column:name;type:varchar(64);not null"`)
+	r.Contains(string(user), "CreatedAt *time.Time")
+	r.NoFileExists(filepath.Join(schemaPath, "user_logs.gen.go"))
+	r.FileExists(filepath.Join(daoPath, "users.gen.go"))
+}
+
+func TestSQLiteCreateTable(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(dsn, []byte(testDDL), 0o644))
+	ddl, offline, err := ddlSource(dsn)
+	require.NoError(t, err)
+	require.True(t, offline)
+	db, err := openDDL(ddl)
+	require.NoError(t, err)
+	columns, err := db.Migrator().ColumnTypes("user_logs")
+	require.NoError(t, err)
+	types := make(map[string]string)
+	for _, c := range columns {
+		types[c.Name()] = c.DatabaseTypeName()
+	}
+	require.Equal(t, map[string]string{
+		"id":        "bigint",
+		"user_id":   "bigint",
+		"payload":   "json",
+		"logged_at": "timestamp",
+	}, types)
+
+	_, offline, err = ddlSource("postgres://localhost:5432/test")
+	require.NoError(t, err)
+	require.False(t, offline)
+}
+
+const testTypesDDL = `
+CREATE TABLE orders (
+	id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'order id',
+	amount DECIMAL(10,2) NOT NULL COMMENT 'the amount, in yuan',
+	qty INT(10) UNSIGNED NOT NULL COMMENT '数量',
+	PRIMARY KEY (id)
+) ENGINE=InnoDB COMMENT='订单';
+`
+
+func TestImporterDDLTypes(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	drv := "mysql"
+	dsn := "text://" + testTypesDDL
+	schemaPath := filepath.Join(dir, "models")
+	daoPath := filepath.Join(dir, "daos")
+
+	r.NoError(Importer(context.Background(), &drv, &dsn, &schemaPath, &daoPath, nil, nil, nil))
+
+	b, err := os.ReadFile(filepath.Join(schemaPath, "orders.gen.go"))
+	r.NoError(err)
+	order := string(b)
+	// The types having arguments or several words are kept whole, with the comments.
+	r.Contains(order, "ID     uint64  `gorm:\"column:id;type:bigint unsigned;primaryKey;comment:order id\" json:\"id\"`")
+	r.Contains(order, "Amount float64 `gorm:\"column:amount;type:decimal(10,2);not null;comment:the amount, in yuan\" json:\"amount\"`")
+	r.Contains(order, "Qty    uint32  `gorm:\"column:qty;type:int(10) unsigned;not null;comment:数量\" json:\"qty\"`")
+	r.Contains(order, "// the amount, in yuan\n")
+	r.Contains(order, "// Order 订单")
+}
+
+const testRelationDDL = `
+CREATE TABLE users (
+	id BIGINT PRIMARY KEY,
+	name VARCHAR(64) NOT NULL
+);
+
+CREATE TABLE profiles (
+	id BIGINT PRIMARY KEY,
+	user_id BIGINT NOT NULL UNIQUE REFERENCES users (id),
+	bio TEXT
+);
+
+CREATE TABLE posts (
+	id BIGINT PRIMARY KEY,
+	author_id BIGINT NOT NULL,
+	editor_id BIGINT,
+	parent_id BIGINT,
+	CONSTRAINT fk_posts_author FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE,
+	FOREIGN KEY (editor_id) REFERENCES users,
+	FOREIGN KEY (parent_id) REFERENCES posts (id)
+);
+
+CREATE TABLE groups (
+	id BIGINT PRIMARY KEY,
+	name VARCHAR(64) NOT NULL
+);
+
+CREATE TABLE user_groups (
+	user_id BIGINT NOT NULL REFERENCES users (id),
+	group_id BIGINT NOT NULL REFERENCES groups (id),
+	PRIMARY KEY (user_id, group_id)
+);
+`
+
+func TestImporterAssociations(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	drv := "mysql"
+	dsn := "text://" + testRelationDDL
+	schemaPath := filepath.Join(dir, "models")
+	daoPath := filepath.Join(dir, "daos")
+
+	err := Importer(context.Background(), &drv, &dsn, &schemaPath, &daoPath, nil, []string{"user_groups"}, nil)
+	r.NoError(err)
+
+	model := func(table string) string {
+		b, err := os.ReadFile(filepath.Join(schemaPath, table+".gen.go"))
+		r.NoError(err)
+		return string(b)
+	}
+	user := model("users")
+	r.Contains(user, "AuthorPosts []Post   `gorm:\"foreignKey:AuthorID;references:ID\" json:\"author_posts\"`")
+	r.Contains(user, "EditorPosts []Post   `gorm:\"foreignKey:EditorID;references:ID\" json:\"editor_posts\"`")
+	r.Contains(user, "Profile     *Profile `gorm:\"foreignKey:UserID;references:ID\" json:\"profile\"`")
+	r.Contains(user, "Groups      []Group  `gorm:\"foreignKey:ID;joinForeignKey:UserID;joinReferences:GroupID;many2many:user_groups;references:ID\" json:\"groups\"`")
+
+	post := model("posts")
+	r.Contains(post, "Author   *User  `gorm:\"foreignKey:AuthorID;references:ID\" json:\"author\"`")
+	r.Contains(post, "Editor   *User  `gorm:\"foreignKey:EditorID;references:ID\" json:\"editor\"`")
+	r.Contains(post, "Parent   *Post  `gorm:\"foreignKey:ParentID;references:ID\" json:\"parent\"`")
+	r.Contains(post, "Posts    []Post `gorm:\"foreignKey:ParentID;references:ID\" json:\"posts\"`")
+
+	r.Contains(model("profiles"), "User   *User   `gorm:\"foreignKey:UserID;references:ID\" json:\"user\"`")
+	r.Contains(model("groups"), "Users []User `gorm:\"foreignKey:ID;joinForeignKey:GroupID;joinReferences:UserID;many2many:user_groups;references:ID\" json:\"users\"`")
+	r.NoFileExists(filepath.Join(schemaPath, "user_groups.gen.go"))
+}
+
+const testNamingDDL = `
+CREATE TABLE sys_users (
+	id BIGINT PRIMARY KEY,
+	name VARCHAR(64) NOT NULL
+);
+
+CREATE TABLE sys_user_data (
+	id BIGINT PRIMARY KEY,
+	user_id BIGINT NOT NULL REFERENCES sys_users (id),
+	value TEXT
+);
+`
+
+func TestImporterNaming(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	drv := "mysql"
+	dsn := "text://" + testNamingDDL
+	schemaPath := filepath.Join(dir, "models")
+	daoPath := filepath.Join(dir, "daos")
+	config := DefaultConfig()
+	config.Naming = naming.New(naming.Config{
+		TablePrefixes: []string{"sys_"},
+		Irregulars:    map[string]string{"data": "data"},
+	})
+
+	err := Importer(context.Background(), &drv, &dsn, &schemaPath, &daoPath, nil, nil, config)
+	r.NoError(err)
+
+	user, err := os.ReadFile(filepath.Join(schemaPath, "user.gen.go"))
+	r.NoError(err)
+	r.Contains(string(user), "type User struct {")
+	r.Contains(string(user), "UserData []UserData `gorm:\"foreignKey:UserID;references:ID\" json:\"user_data\"`")
+	r.Contains(string(user), "func (*User) TableName() string {\n\treturn TableNameUser\n}")
+	data, err := os.ReadFile(filepath.Join(schemaPath, "user_data.gen.go"))
+	r.NoError(err)
+	r.Contains(string(data), "type UserData struct {")
+	r.Contains(string(data), "User   *User   `gorm:\"foreignKey:UserID;references:ID\" json:\"user\"`")
+	r.NoFileExists(filepath.Join(schemaPath, "sys_users.gen.go"))
+}
+
+func TestDDLForeignKeys(t *testing.T) {
+	tables, err := ddlparser.ParseCreateTables(testRelationDDL)
+	require.NoError(t, err)
+	fks := ddlForeignKeys(testRelationDDL, tables)
+	require.Equal(t, []ddlForeignKey{
+		{columns: []string{"author_id"}, refTable: "users", refColumns: []string{"id"}},
+		{columns: []string{"editor_id"}, refTable: "users", refColumns: []string{"id"}},
+		{columns: []string{"parent_id"}, refTable: "posts", refColumns: []string{"id"}},
+	}, fks["posts"])
+	require.Equal(t, []ddlForeignKey{
+		{columns: []string{"user_id"}, refTable: "users", refColumns: []string{"id"}},
+		{columns: []string{"group_id"}, refTable: "groups", refColumns: []string{"id"}},
+	}, fks["user_groups"])
+	require.Empty(t, fks["users"])
+}
+
+const testHCL = `
+schema "public" {}
+
+table "users" {
+  schema = schema.public
+  column "id" {
+    type = bigserial
+  }
+  column "name" {
+    type = character_varying(64)
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+
+table "posts" {
+  schema = schema.public
+  column "id" {
+    type = bigint
+  }
+  column "user_id" {
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "posts_user_id_fkey" {
+    columns     = [column.user_id]
+    ref_columns = [table.users.column.id]
+  }
+}
+`
+
+func TestImporterHCL(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	drv := "postgres"
+	dsn := filepath.Join(dir, "schema.hcl")
+	r.NoError(os.WriteFile(dsn, []byte(testHCL), 0o644))
+	schemaPath := filepath.Join(dir, "models")
+	daoPath := filepath.Join(dir, "daos")
+
+	err := Importer(context.Background(), &drv, &dsn, &schemaPath, &daoPath, nil, nil, nil)
+	r.NoError(err)
+
+	user, err := os.ReadFile(filepath.Join(schemaPath, "users.gen.go"))
+	r.NoError(err)
+	r.Contains(string(user), `gorm:"column:name;type:varchar(64);not null"`)
+	post, err := os.ReadFile(filepath.Join(schemaPath, "posts.gen.go"))
+	r.NoError(err)
+	r.Contains(string(post), `gorm:"foreignKey:UserID;references:ID"`)
+}
@@ -3,12 +3,23 @@ package gorm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+
+	"github.com/tx7do/go-wind-toolkit/generators/atlashcl"
+	"github.com/tx7do/go-wind-toolkit/generators/tablefilter"
 )
 
-func Importer(_ context.Context, drv, dsn, schemaPath, daoPath *string, tables, _ []string) error {
+// Importer generates the gorm models and DAOs of the tables of the database given by the driver and DSN.
+// The DSN may be an offline DDL source instead: "text://<sql>", "file://<path>" or the path of a .sql file,
+// or Atlas HCL files: "hcl://<path>" or the path of a .hcl file.
+// A nil config generates the code with DefaultConfig.
+func Importer(ctx context.Context, drv, dsn, schemaPath, daoPath *string, includeTables, excludeTables []string, config *Config) error {
 	if schemaPath == nil {
 		return errors.New("gormimport: schema path is nil")
 	}
@@ -22,34 +33,66 @@ func Importer(_ context.Context, drv, dsn, schemaPath, daoPath *string, tables,
 		return errors.New("gormimport: drv is nil")
 	}
 
-	_ = os.MkdirAll(*schemaPath, os.ModePerm)
-	_ = os.MkdirAll(*daoPath, os.ModePerm)
+	filter, err := tablefilter.New(includeTables, excludeTables)
+	if err != nil {
+		return err
+	}
 
-	db := NewGormClient(*drv, *dsn)
+	if config == nil {
+		config = DefaultConfig()
+	}
+	cfg, err := config.genConfig(*daoPath, *schemaPath)
+	if err != nil {
+		return err
+	}
 
-	g := gen.NewGenerator(gen.Config{
-		OutPath:      *daoPath,    // 生成的代码路径
-		ModelPkgPath: *schemaPath, // 模型包路径
+	_ = os.MkdirAll(*schemaPath, os.ModePerm)
+	_ = os.MkdirAll(*daoPath, os.ModePerm)
 
-		Mode: gen.WithoutContext | gen.WithDefaultQuery | gen.WithQueryInterface, // 不生成上下文
+	db, err := openDB(*drv, *dsn)
+	if err != nil {
+		return err
+	}
 
-		WithUnitTest:      false, // 生成单元测试
-		FieldNullable:     true,  // 指针类型字段允许为 nil
-		FieldCoverable:    true,  // 生成可覆盖字段
-		FieldSignable:     true,  // 有符号数和无符号数自动转换
-		FieldWithIndexTag: true,  // 生成索引标签
-		FieldWithTypeTag:  true,  // 生成类型标签
-	})
+	g := gen.NewGenerator(*cfg)
 
 	g.UseDB(db) // 设置数据库连接
 
-	var models []any
-	if len(tables) > 0 {
-		for _, t := range tables {
-			models = append(models, g.GenerateModel(t))
+	// 按包含和排除的表过滤，表可以使用表名、通配符或正则表达式
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("gormimport: get tables failed: %w", err)
+	}
+	selected := func(table string) bool { return filter.Match("", table) }
+
+	// 根据外键生成模型的关联字段
+	var assocs map[string][]association
+	if config.Associations {
+		if assocs, err = associations(ctx, db, selected, config.Naming); err != nil {
+			return err
+		}
+	}
+	var (
+		names   []string
+		models  []any
+		related = make(map[string]relateFunc)
+	)
+	for _, t := range tables {
+		if !selected(t) {
+			continue
+		}
+		meta := g.GenerateModel(t)
+		related[t] = func(kind field.RelationshipType, name string, config *field.RelateConfig) gen.ModelOpt {
+			return gen.FieldRelate(kind, name, meta, config)
+		}
+		names = append(names, t)
+		models = append(models, meta)
+	}
+	// 关联字段引用其他表的模型，所以在所有模型生成后重新生成带关联字段的模型
+	for i, t := range names {
+		if opts := config.relateOptions(assocs[t], related); len(opts) > 0 {
+			models[i] = g.GenerateModel(t, opts...)
 		}
-	} else {
-		models = g.GenerateAllTable()
 	}
 
 	// 生成所有表的模型和 DAO
@@ -60,3 +103,31 @@ func Importer(_ context.Context, drv, dsn, schemaPath, daoPath *string, tables,
 
 	return nil
 }
+
+// openDB opens the database of the DSN, or an in-memory database holding the tables of an offline DDL source
+// or of Atlas HCL files, given as "hcl://<path>" or the path of a .hcl file.
+func openDB(drv, dsn string) (*gorm.DB, error) {
+	path, ok := strings.CutPrefix(dsn, atlashcl.Scheme+"://")
+	if !ok && strings.HasSuffix(strings.ToLower(dsn), ".hcl") {
+		path, ok = dsn, true
+	}
+	if ok {
+		s, err := atlashcl.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return openSchema(s)
+	}
+	ddl, offline, err := ddlSource(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if offline {
+		return openDDL(ddl)
+	}
+	db := NewGormClient(drv, dsn)
+	if db == nil {
+		return nil, errors.New("gormimport: failed to open database")
+	}
+	return db, nil
+}
@@ -14,5 +14,5 @@ func TestImporter(t *testing.T) {
 	daoPath := "./daos/"
 	var includeTables []string
 	var excludeTables []string
-	_ = Importer(ctx, &drv, &dsn, &schemaPath, &daoPath, includeTables, excludeTables)
+	_ = Importer(ctx, &drv, &dsn, &schemaPath, &daoPath, includeTables, excludeTables, nil)
 }
@@ -11,4 +11,25 @@ type Options struct {
 
 	SchemaPath string `json:"schema_path"` // Path to save the generated schema files
 	DaoPath    string `json:"dao_path"`    // Path to save the generated DAO code (for gorm)
+
+	DecimalType string `json:"decimal_type"` // Go type of the DECIMAL/NUMERIC columns (for ent), e.g., "float", "decimal"
+	MixinsFile  string `json:"mixins_file"`  // YAML catalogue of the mixins replacing the common columns (for ent)
+	NamingFile  string `json:"naming_file"`  // YAML file of the naming rules shared with sql2proto and sql2kratos
+	Merge       bool   `json:"merge"`        // Merge into the existing schema, keeping the hand-written code (for ent)
+
+	SchemaPackages bool `json:"schema_packages"` // Write each PostgreSQL schema to its own package (for ent)
+	Strict         bool `json:"strict"`          // Fail on the first table or column that cannot be imported (for ent)
+
+	GormConfigFile string            `json:"gorm_config_file"` // YAML file of the gorm/gen settings (for gorm)
+	GormMode       []string          `json:"gorm_mode"`        // Generate modes, e.g., "default_query", "query_interface", "without_context" (for gorm)
+	FieldNullable  bool              `json:"field_nullable"`   // Generate a pointer for the nullable columns (for gorm)
+	JSONTag        string            `json:"json_tag"`         // JSON tag naming strategy, e.g., "snake", "camel", "pascal", "none" (for gorm)
+	UnitTest       bool              `json:"unit_test"`        // Generate the unit tests of the query code (for gorm)
+	DataTypes      map[string]string `json:"data_types"`       // SQL types mapped to Go types, e.g., "json=datatypes.JSON" (for gorm)
+
+	MigrationDir    string `json:"migration_dir"`    // Directory of the versioned migrations (for migrate diff)
+	MigrationFormat string `json:"migration_format"` // Format of the migrations, e.g., "atlas", "golang-migrate", "goose" (for migrate diff)
+	DevURL          string `json:"dev_url"`          // Dev database computing the migrations, or loading a schema dump (for migrate diff and drift)
+	Baseline        string `json:"baseline"`         // DDL file of the schema the migrations start from (for migrate diff)
+	JSON            bool   `json:"json"`             // Print the report as JSON (for drift)
 }
@@ -0,0 +1,171 @@
+package relation
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// Kind is the kind of an association.
+type Kind uint8
+
+const (
+	// BelongsTo associates the table holding a foreign key with the referenced table.
+	BelongsTo Kind = iota
+	// HasOne associates a table with the table of a unique foreign key referencing it.
+	HasOne
+	// HasMany associates a table with the table of a foreign key referencing it.
+	HasMany
+	// ManyToMany associates the two tables of a join table.
+	ManyToMany
+)
+
+// Association is an association field of the model of a table.
+type Association struct {
+	Kind Kind
+	// Name is the name of the field.
+	Name string
+	// Table is the associated table.
+	Table *schema.Table
+	// ForeignKey is the foreign key of the association: the foreign key of the table of the model for
+	// BelongsTo, of the associated table for HasOne and HasMany, and of the join table to the table of
+	// the model for ManyToMany.
+	ForeignKey ForeignKey
+	// JoinForeignKey is the foreign key of the join table to the associated table, for ManyToMany.
+	JoinForeignKey ForeignKey
+}
+
+// Join returns the join table of a ManyToMany association.
+func (a Association) Join() *schema.Table {
+	return a.ForeignKey.Table
+}
+
+// Naming names the models and their fields for the associations.
+type Naming struct {
+	// Field returns the name of the field of a column, e.g. "UserID" for "user_id".
+	Field func(column string) string
+	// Type returns the name of the model of a table, e.g. "User" for "users".
+	Type func(table string) string
+	// Plural returns the plural of a name, e.g. "Users" for "User".
+	Plural func(name string) string
+}
+
+// Associations returns the association fields of the models of the tables, by table: a foreign key makes
+// its table belong to the referenced table, which has one (the foreign key column is unique) or many rows
+// of the table, and a pure join table associates its two tables many to many. The associations with the
+// tables that are not selected are left out, and the field names do not collide with those of the columns.
+func Associations(tables []*schema.Table, selected func(*schema.Table) bool, naming Naming) map[*schema.Table][]Association {
+	tables = append([]*schema.Table(nil), tables...)
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+
+	names := make(map[*schema.Table]map[string]bool)
+	for _, t := range tables {
+		names[t] = make(map[string]bool)
+		for _, c := range t.Columns {
+			names[t][naming.Field(c.Name)] = true
+		}
+	}
+	// fieldName returns the first candidate name not used by a field of the model of the table.
+	fieldName := func(t *schema.Table, candidates ...string) string {
+		name := candidates[0]
+		for _, c := range candidates {
+			if c != "" && !names[t][c] {
+				name = c
+				break
+			}
+		}
+		for i := 2; names[t][name]; i++ {
+			name = candidates[0] + strconv.Itoa(i)
+		}
+		names[t][name] = true
+		return name
+	}
+	// baseName returns the name of the field of a foreign key column, without its "ID" suffix.
+	baseName := func(column string) string {
+		return strings.TrimSuffix(naming.Field(column), "ID")
+	}
+
+	assocs := make(map[*schema.Table][]Association)
+	add := func(t *schema.Table, a Association) {
+		assocs[t] = append(assocs[t], a)
+	}
+	for _, t := range tables {
+		fks := ForeignKeys(t)
+		sort.SliceStable(fks, func(i, j int) bool { return columnIndex(t, fks[i].Column) < columnIndex(t, fks[j].Column) })
+		refs := make(map[*schema.Table]int)
+		for _, fk := range fks {
+			refs[fk.RefTable]++
+		}
+		join := IsJoinTable(t)
+		for _, fk := range fks {
+			parent := fk.RefTable
+			if !selected(parent) || fk.RefColumn == "" {
+				continue
+			}
+			if selected(t) {
+				add(t, Association{
+					Kind:       BelongsTo,
+					Name:       fieldName(t, baseName(fk.Column), naming.Type(parent.Name)),
+					Table:      parent,
+					ForeignKey: fk,
+				})
+			}
+			// The rows of a join table are reached through the many to many associations.
+			if join || !selected(t) {
+				continue
+			}
+			model := naming.Type(t.Name)
+			kind, name := HasMany, naming.Plural(model)
+			if fk.Unique {
+				kind, name = HasOne, model
+			}
+			// The foreign keys of a table to the same table are told apart by their column.
+			if refs[parent] > 1 {
+				name = baseName(fk.Column) + name
+			}
+			add(parent, Association{
+				Kind:       kind,
+				Name:       fieldName(parent, name),
+				Table:      t,
+				ForeignKey: fk,
+			})
+		}
+
+		// The join tables associate the selected tables even when they are not selected themselves.
+		a, b, ok := JoinTable(t)
+		if !ok || !selected(a.RefTable) || !selected(b.RefTable) {
+			continue
+		}
+		for _, fks := range [][2]ForeignKey{{a, b}, {b, a}} {
+			from, to := fks[0], fks[1]
+			if from.RefTable == to.RefTable && from.Column > to.Column {
+				// A table joined with itself gets a single association.
+				continue
+			}
+			name := naming.Plural(naming.Type(to.RefTable.Name))
+			if from.RefTable == to.RefTable {
+				name = naming.Plural(baseName(to.Column))
+			}
+			add(from.RefTable, Association{
+				Kind:           ManyToMany,
+				Name:           fieldName(from.RefTable, name, name+naming.Type(t.Name)),
+				Table:          to.RefTable,
+				ForeignKey:     from,
+				JoinForeignKey: to,
+			})
+		}
+	}
+	return assocs
+}
+
+// columnIndex returns the position of a column in its table.
+func columnIndex(t *schema.Table, name string) int {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return len(t.Columns)
+}
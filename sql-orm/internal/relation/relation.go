@@ -0,0 +1,90 @@
+// Package relation derives the relations between the tables from their foreign keys, for the ORMs
+// to generate their edges or associations the same way.
+package relation
+
+import (
+	"ariga.io/atlas/sql/schema"
+)
+
+// ForeignKey is a single-column foreign key: the table holding the column belongs to the referenced table,
+// which has one (Unique) or many rows of the table.
+type ForeignKey struct {
+	Table     *schema.Table
+	Column    string
+	RefTable  *schema.Table
+	RefColumn string
+	// Unique reports if the column has a unique index, making the relation one-to-one.
+	Unique bool
+}
+
+// Recursive reports if the foreign key references its own table.
+func (fk ForeignKey) Recursive() bool {
+	return fk.Table == fk.RefTable
+}
+
+// ForeignKeys returns the single-column foreign keys of the table. The composite foreign keys are left out.
+func ForeignKeys(table *schema.Table) []ForeignKey {
+	unique := make(map[string]bool)
+	for _, idx := range table.Indexes {
+		if len(idx.Parts) == 1 && idx.Parts[0].C != nil && idx.Unique {
+			unique[idx.Parts[0].C.Name] = true
+		}
+	}
+	var fks []ForeignKey
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) != 1 || fk.RefTable == nil {
+			continue
+		}
+		column := fk.Columns[0].Name
+		fks = append(fks, ForeignKey{
+			Table:     table,
+			Column:    column,
+			RefTable:  fk.RefTable,
+			RefColumn: refColumn(fk),
+			Unique:    unique[column],
+		})
+	}
+	return fks
+}
+
+// refColumn returns the referenced column of a single-column foreign key, which is the primary key of the
+// referenced table when the foreign key does not name it.
+func refColumn(fk *schema.ForeignKey) string {
+	if len(fk.RefColumns) == 1 {
+		return fk.RefColumns[0].Name
+	}
+	if pk := fk.RefTable.PrimaryKey; pk != nil && len(pk.Parts) == 1 && pk.Parts[0].C != nil {
+		return pk.Parts[0].C.Name
+	}
+	return ""
+}
+
+// IsJoinTable reports if the table is a pure join table of a many-to-many relation: its two columns are its
+// primary key and reference the joined tables.
+func IsJoinTable(table *schema.Table) bool {
+	if table.PrimaryKey == nil || len(table.PrimaryKey.Parts) != 2 || len(table.ForeignKeys) != 2 || len(table.Columns) != 2 {
+		return false
+	}
+	// Make sure that the foreign key columns exactly match primary key column.
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) != 1 {
+			return false
+		}
+		if fk.Columns[0] != table.PrimaryKey.Parts[0].C && fk.Columns[0] != table.PrimaryKey.Parts[1].C {
+			return false
+		}
+	}
+	return true
+}
+
+// JoinTable returns the foreign keys of a pure join table to the two tables it joins, see IsJoinTable.
+func JoinTable(table *schema.Table) (a, b ForeignKey, ok bool) {
+	if !IsJoinTable(table) {
+		return a, b, false
+	}
+	fks := ForeignKeys(table)
+	if len(fks) != 2 {
+		return a, b, false
+	}
+	return fks[0], fks[1], true
+}
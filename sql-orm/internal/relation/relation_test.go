@@ -0,0 +1,53 @@
+package relation
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignKeys(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint"))
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+
+	ownerID := schema.NewIntColumn("owner_id", "bigint")
+	userID := schema.NewIntColumn("user_id", "bigint")
+	pets := schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "bigint"), ownerID, userID)
+	pets.AddIndexes(schema.NewUniqueIndex("pets_user_id").AddColumns(userID))
+	pets.AddForeignKeys(
+		schema.NewForeignKey("pets_owner").AddColumns(ownerID).SetRefTable(users).AddRefColumns(users.Columns[0]),
+		schema.NewForeignKey("pets_user").AddColumns(userID).SetRefTable(users),
+	)
+
+	require.Equal(t, []ForeignKey{
+		{Table: pets, Column: "owner_id", RefTable: users, RefColumn: "id"},
+		{Table: pets, Column: "user_id", RefTable: users, RefColumn: "id", Unique: true},
+	}, ForeignKeys(pets))
+	require.False(t, IsJoinTable(pets))
+}
+
+func TestJoinTable(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint"))
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	groups := schema.NewTable("groups").AddColumns(schema.NewIntColumn("id", "bigint"))
+	groups.SetPrimaryKey(schema.NewPrimaryKey(groups.Columns[0]))
+
+	userID := schema.NewIntColumn("user_id", "bigint")
+	groupID := schema.NewIntColumn("group_id", "bigint")
+	userGroups := schema.NewTable("user_groups").AddColumns(userID, groupID)
+	userGroups.SetPrimaryKey(schema.NewPrimaryKey(userID, groupID))
+	userGroups.AddForeignKeys(
+		schema.NewForeignKey("user_groups_user").AddColumns(userID).SetRefTable(users).AddRefColumns(users.Columns[0]),
+		schema.NewForeignKey("user_groups_group").AddColumns(groupID).SetRefTable(groups).AddRefColumns(groups.Columns[0]),
+	)
+
+	a, b, ok := JoinTable(userGroups)
+	require.True(t, ok)
+	require.Equal(t, "users", a.RefTable.Name)
+	require.Equal(t, "groups", b.RefTable.Name)
+	require.False(t, a.Recursive())
+
+	_, _, ok = JoinTable(users)
+	require.False(t, ok)
+}
@@ -0,0 +1,170 @@
+// Package sqlc generates the sqlc project of the tables of a database: the schema, the queries and the
+// configuration of sqlc.
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"entgo.io/ent/dialect"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+
+	"github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
+)
+
+const header = "-- Code generated " + "by sqlcimport, DO NOT EDIT."
+
+// Engines of sqlc.
+const (
+	EngineMySQL      = "mysql"
+	EnginePostgreSQL = "postgresql"
+	EngineSQLite     = "sqlite"
+)
+
+// Importer generates the sqlc project of the tables of the database specified by the DSN in the schemaPath:
+// the schema.sql of the tables, the queries/<table>.sql of their CRUD, paging and count queries, and the
+// sqlc.yaml generating the Go code of the queries to the db package. The DSN is inspected as for ent, so it
+// may be a DDL source as well. The queries are named by the naming strategy, which may be nil.
+func Importer(ctx context.Context, dsn, schemaPath *string, includeTables, excludeTables []string, names *naming.Strategy) error {
+	if schemaPath == nil {
+		return errors.New("sqlcimport: schema path is nil")
+	}
+	if dsn == nil {
+		return errors.New("sqlcimport: dsn is nil")
+	}
+
+	tables, dialectName, err := entimport.InspectTables(ctx, *dsn, includeTables, excludeTables)
+	if err != nil {
+		return fmt.Errorf("sqlcimport: %w", err)
+	}
+	if len(tables) == 0 {
+		return errors.New("sqlcimport: no table to import")
+	}
+	engine := engineOf(dialectName, tables)
+
+	ddl, err := schemaSQL(ctx, engine, tables)
+	if err != nil {
+		return err
+	}
+
+	queriesPath := filepath.Join(*schemaPath, "queries")
+	if err = os.MkdirAll(queriesPath, os.ModePerm); err != nil {
+		return fmt.Errorf("sqlcimport: create queries path: %w", err)
+	}
+	if err = writeFile(filepath.Join(*schemaPath, "schema.sql"), header+"\n\n"+ddl); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		if err = writeFile(filepath.Join(queriesPath, t.Name+".sql"), header+"\n\n"+queries(t, engine, names)); err != nil {
+			return err
+		}
+	}
+	return writeFile(filepath.Join(*schemaPath, "sqlc.yaml"), config(engine))
+}
+
+// engineOf returns the sqlc engine of a dialect. The engine of a DDL source is guessed from its column
+// types: PostgreSQL if one of them is a PostgreSQL type, MySQL otherwise.
+func engineOf(dialectName string, tables []*schema.Table) string {
+	switch dialectName {
+	case dialect.Postgres:
+		return EnginePostgreSQL
+	case dialect.SQLite:
+		return EngineSQLite
+	case dialect.MySQL:
+		return EngineMySQL
+	}
+	for _, t := range tables {
+		for _, c := range t.Columns {
+			switch c.Type.Type.(type) {
+			case *postgres.ArrayType, *postgres.SerialType, *postgres.NetworkType, *postgres.UserDefinedType:
+				return EnginePostgreSQL
+			}
+		}
+	}
+	return EngineMySQL
+}
+
+// schemaSQL returns the CREATE statements of the tables, as planned by Atlas for the engine.
+func schemaSQL(ctx context.Context, engine string, tables []*schema.Table) (string, error) {
+	var planner migrate.PlanApplier
+	switch engine {
+	case EnginePostgreSQL:
+		planner = postgres.DefaultPlan
+	case EngineSQLite:
+		planner = sqlite.DefaultPlan
+	default:
+		planner = mysql.DefaultPlan
+	}
+	changes := make([]schema.Change, 0, len(tables))
+	for _, t := range tables {
+		normalize(t)
+		changes = append(changes, &schema.AddTable{T: t})
+	}
+	plan, err := planner.PlanChanges(ctx, "schema", changes, func(o *migrate.PlanOptions) {
+		o.SchemaQualifier = new(string)
+		o.Indent = "  "
+	})
+	if err != nil {
+		return "", fmt.Errorf("sqlcimport: plan schema: %w", err)
+	}
+	var b strings.Builder
+	for _, c := range plan.Changes {
+		if c.Comment != "" {
+			b.WriteString("-- " + strings.ToUpper(c.Comment[:1]) + c.Comment[1:] + "\n")
+		}
+		b.WriteString(c.Cmd + ";\n\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// normalize cleans up the inspected table for its DDL: the columns of the primary key are not null, which
+// SQLite does not report for an INTEGER PRIMARY KEY, and the foreign keys SQLite numbers are left unnamed.
+func normalize(t *schema.Table) {
+	if t.PrimaryKey != nil {
+		for _, p := range t.PrimaryKey.Parts {
+			if p.C != nil {
+				p.C.Type.Null = false
+			}
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if _, err := strconv.Atoi(fk.Symbol); err == nil {
+			fk.Symbol = ""
+		}
+	}
+}
+
+// config returns the sqlc.yaml of the project.
+func config(engine string) string {
+	return `version: "2"
+sql:
+  - engine: "` + engine + `"
+    schema: "schema.sql"
+    queries: "queries"
+    gen:
+      go:
+        package: "db"
+        out: "db"
+        emit_json_tags: true
+        emit_empty_slices: true
+`
+}
+
+// writeFile writes a generated file.
+func writeFile(path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("sqlcimport: write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/schema"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+)
+
+func TestImporter(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.db")
+	db, err := sql.Open("sqlite3", path)
+	r.NoError(err)
+	for _, ddl := range []string{
+		"CREATE TABLE users (id integer PRIMARY KEY, name varchar(64) NOT NULL, email text NULL UNIQUE)",
+		"CREATE TABLE posts (id integer PRIMARY KEY AUTOINCREMENT, author_id integer NOT NULL REFERENCES users (id), body text NULL)",
+		"CREATE TABLE logs (message text NOT NULL)",
+	} {
+		_, err = db.Exec(ddl)
+		r.NoError(err)
+	}
+	r.NoError(db.Close())
+
+	dsn := "sqlite://" + path
+	schemaPath := filepath.Join(dir, "sqlc")
+	r.NoError(Importer(context.Background(), &dsn, &schemaPath, nil, nil, nil))
+
+	file := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(schemaPath, name))
+		r.NoError(err)
+		return string(b)
+	}
+	ddl := file("schema.sql")
+	r.Contains(ddl, "CREATE TABLE `users` (\n  `id` integer NOT NULL,")
+	r.Contains(ddl, "  FOREIGN KEY (`author_id`) REFERENCES `users` (`id`)")
+
+	users := file("queries/users.sql")
+	r.Contains(users, "-- name: GetUser :one\nSELECT * FROM \"users\"\nWHERE \"id\" = ? LIMIT 1;")
+	r.Contains(users, "-- name: ListUsers :many\nSELECT * FROM \"users\"\nORDER BY \"id\"\nLIMIT ? OFFSET ?;")
+	r.Contains(users, "-- name: CountUsers :one\nSELECT count(*) FROM \"users\";")
+	r.Contains(users, "-- name: CreateUser :one\nINSERT INTO \"users\" (\n  \"name\", \"email\"\n) VALUES (\n  ?, ?\n)\nRETURNING *;")
+	r.Contains(users, "-- name: UpdateUser :exec\nUPDATE \"users\"\nSET \"name\" = ?,\n    \"email\" = ?\nWHERE \"id\" = ?;")
+	r.Contains(users, "-- name: DeleteUser :exec\nDELETE FROM \"users\"\nWHERE \"id\" = ?;")
+
+	logs := file("queries/logs.sql")
+	r.Contains(logs, "-- name: ListLogs :many\nSELECT * FROM \"logs\"\nLIMIT ? OFFSET ?;")
+	r.NotContains(logs, "GetLog")
+
+	r.Contains(file("sqlc.yaml"), `engine: "sqlite"`)
+}
+
+func TestQueriesPostgres(t *testing.T) {
+	r := require.New(t)
+	users := newUsersTable()
+	sql := queries(users, EnginePostgreSQL, nil)
+	r.Contains(sql, "-- name: ListUsers :many\nSELECT * FROM \"users\"\nORDER BY \"id\"\nLIMIT $1 OFFSET $2;")
+	r.Contains(sql, "-- name: UpdateUser :exec\nUPDATE \"users\"\nSET \"name\" = $1\nWHERE \"id\" = $2;")
+
+	sql = queries(users, EngineMySQL, nil)
+	r.Contains(sql, "-- name: CreateUser :execresult\nINSERT INTO `users` (\n  `name`\n) VALUES (\n  ?\n);")
+}
+
+func TestQueriesNaming(t *testing.T) {
+	r := require.New(t)
+	staff := schema.NewTable("staves").AddColumns(schema.NewStringColumn("name", "varchar"))
+	names := naming.New(naming.Config{Irregulars: map[string]string{"staff": "staves"}})
+	sql := queries(staff, EnginePostgreSQL, names)
+	r.Contains(sql, "-- name: ListStaves :many\n")
+	r.Contains(sql, "-- name: CountStaves :one\n")
+}
+
+func newUsersTable() *schema.Table {
+	id := schema.NewIntColumn("id", "bigint").AddAttrs(&mysql.AutoIncrement{})
+	users := schema.NewTable("users").AddColumns(id, schema.NewStringColumn("name", "varchar"))
+	users.SetPrimaryKey(schema.NewPrimaryKey(id))
+	return users
+}
@@ -0,0 +1,167 @@
+package sqlc
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
+)
+
+// queries returns the sqlc queries of a table: Get, List (paged), Count, Create, Update and Delete. The
+// tables without primary key get the List, Count and Create queries only.
+func queries(t *schema.Table, engine string, names *naming.Strategy) string {
+	var (
+		q      = newQuery(engine)
+		model  = names.TypeName(t.Name)
+		plural = names.PluralTypeName(model)
+		table  = q.ident(t.Name)
+		pk     = primaryKey(t)
+	)
+	var b strings.Builder
+	add := func(name, cmd, sql string) {
+		b.WriteString("-- name: " + name + " " + cmd + "\n" + sql + ";\n\n")
+	}
+
+	if len(pk) > 0 {
+		add("Get"+model, ":one", "SELECT * FROM "+table+"\nWHERE "+q.where(pk)+" LIMIT 1")
+	}
+
+	list := "SELECT * FROM " + table
+	if len(pk) > 0 {
+		list += "\nORDER BY " + q.idents(pk)
+	}
+	q.n = 0
+	add("List"+plural, ":many", list+"\nLIMIT "+q.param()+" OFFSET "+q.param())
+	add("Count"+plural, ":one", "SELECT count(*) FROM "+table)
+
+	q.n = 0
+	var insert []string
+	for _, c := range t.Columns {
+		if !generated(t, c, engine) {
+			insert = append(insert, c.Name)
+		}
+	}
+	create := "INSERT INTO " + table + " (\n  " + q.idents(insert) + "\n) VALUES (\n  " + q.params(len(insert)) + "\n)"
+	if engine == EngineMySQL {
+		// MySQL has no RETURNING clause, the result gives the ID of the row.
+		add("Create"+model, ":execresult", create)
+	} else {
+		add("Create"+model, ":one", create+"\nRETURNING *")
+	}
+
+	if len(pk) > 0 {
+		var set []string
+		for _, c := range t.Columns {
+			if !slices.Contains(pk, c.Name) && !generated(t, c, engine) {
+				set = append(set, c.Name)
+			}
+		}
+		if len(set) > 0 {
+			q.n = 0
+			assigns := make([]string, 0, len(set))
+			for _, c := range set {
+				assigns = append(assigns, q.ident(c)+" = "+q.param())
+			}
+			add("Update"+model, ":exec", "UPDATE "+table+"\nSET "+strings.Join(assigns, ",\n    ")+"\nWHERE "+q.where(pk))
+		}
+		q.n = 0
+		add("Delete"+model, ":exec", "DELETE FROM "+table+"\nWHERE "+q.where(pk))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// query writes the identifiers and the parameters of the queries of an engine.
+type query struct {
+	engine string
+	// n is the number of the parameters of the query, for the $n parameters of PostgreSQL.
+	n int
+}
+
+func newQuery(engine string) *query {
+	return &query{engine: engine}
+}
+
+// ident quotes an identifier, which may be a keyword such as "user" or "groups".
+func (q *query) ident(name string) string {
+	if q.engine == EngineMySQL {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+// idents quotes a list of identifiers.
+func (q *query) idents(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, q.ident(n))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// param returns the next parameter of the query.
+func (q *query) param() string {
+	q.n++
+	if q.engine == EnginePostgreSQL {
+		return "$" + strconv.Itoa(q.n)
+	}
+	return "?"
+}
+
+// params returns the next n parameters of the query.
+func (q *query) params(n int) string {
+	params := make([]string, 0, n)
+	for range n {
+		params = append(params, q.param())
+	}
+	return strings.Join(params, ", ")
+}
+
+// where returns the condition on the columns of the primary key.
+func (q *query) where(pk []string) string {
+	conds := make([]string, 0, len(pk))
+	for _, c := range pk {
+		conds = append(conds, q.ident(c)+" = "+q.param())
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// primaryKey returns the columns of the primary key of a table.
+func primaryKey(t *schema.Table) []string {
+	if t.PrimaryKey == nil {
+		return nil
+	}
+	var columns []string
+	for _, p := range t.PrimaryKey.Parts {
+		if p.C != nil {
+			columns = append(columns, p.C.Name)
+		}
+	}
+	return columns
+}
+
+// generated reports if the database generates the values of a column, which are left out of the
+// INSERT and UPDATE queries: the auto-increment, serial and identity columns, and the generated columns.
+func generated(t *schema.Table, c *schema.Column, engine string) bool {
+	if _, ok := c.Type.Type.(*postgres.SerialType); ok {
+		return true
+	}
+	for _, attr := range c.Attrs {
+		switch attr.(type) {
+		case *mysql.AutoIncrement, *sqlite.AutoIncrement, *postgres.Identity, *schema.GeneratedExpr:
+			return true
+		}
+	}
+	if d, ok := c.Default.(*schema.RawExpr); ok && strings.HasPrefix(strings.ToLower(d.X), "nextval(") {
+		return true
+	}
+	// The INTEGER PRIMARY KEY of SQLite is an alias of the row ID.
+	pk := primaryKey(t)
+	typ, ok := c.Type.Type.(*schema.IntegerType)
+	return ok && engine == EngineSQLite && strings.EqualFold(typ.T, "integer") && len(pk) == 1 && pk[0] == c.Name
+}
@@ -1,8 +1,151 @@
 package sqlorm
 
+import "github.com/tx7do/go-wind-toolkit/sql-orm/internal/gorm"
+
 type OrmType string
 
 const (
 	OrmTypeEnt  OrmType = "ent"
 	OrmTypeGorm OrmType = "gorm"
+	OrmTypeBun  OrmType = "bun"
+	OrmTypeSqlc OrmType = "sqlc"
 )
+
+// GormConfig holds the settings of the gorm/gen generator.
+type GormConfig = gorm.Config
+
+// ImporterOptions holds the optional settings of Importer.
+type ImporterOptions struct {
+	// DecimalType is the Go type of the DECIMAL and NUMERIC columns for ent: "float" (default) or "decimal".
+	DecimalType string
+	// MixinsFile is the YAML catalogue of the ent mixins replacing the common columns of the tables.
+	MixinsFile string
+	// NamingFile is the YAML file of the naming rules deriving the ent, gorm, bun and sqlc type names from the table names.
+	NamingFile string
+	// Merge adds the new schemas, fields, edges and indexes to the existing ent schema instead of rewriting it.
+	Merge bool
+	// SchemaPackages writes the ent schema of each PostgreSQL schema to its own package under the schema path.
+	SchemaPackages bool
+	// Strict fails the ent import on the first table or column that cannot be imported, instead of
+	// leaving it out and reporting it.
+	Strict bool
+	// Report, if set, receives the diagnostics of the ent import.
+	Report *Report
+	// GormConfigFile is the YAML file of the gorm/gen settings, see GormConfig.
+	GormConfigFile string
+
+	// gorm modifies the gorm/gen settings read from GormConfigFile.
+	gorm []gorm.Option
+}
+
+// ImporterOption configures the optional settings of Importer.
+type ImporterOption func(*ImporterOptions)
+
+// WithDecimalType sets the Go type of the DECIMAL and NUMERIC columns for ent: "float" or "decimal",
+// which imports them as github.com/shopspring/decimal values.
+func WithDecimalType(t string) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.DecimalType = t
+	}
+}
+
+// WithMixinsFile sets the YAML catalogue of the ent mixins replacing the common columns of the tables.
+func WithMixinsFile(path string) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.MixinsFile = path
+	}
+}
+
+// WithNamingFile sets the YAML file of the naming rules deriving the type names from the table names,
+// shared with sql2proto and sql2kratos.
+func WithNamingFile(path string) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.NamingFile = path
+	}
+}
+
+// WithMerge merges the imported ent schema into the existing one, keeping the hand-written code.
+// The changes made to each schema file are printed to the standard output.
+func WithMerge(merge bool) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.Merge = merge
+	}
+}
+
+// WithSchemaPackages writes the ent schema of each imported PostgreSQL schema to its own package,
+// named after the schema. The foreign keys between the packages are kept as plain fields.
+func WithSchemaPackages(b bool) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.SchemaPackages = b
+	}
+}
+
+// WithStrict fails the ent import on the first table or column that cannot be imported.
+func WithStrict(b bool) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.Strict = b
+	}
+}
+
+// WithReport sets the report receiving the diagnostics of the ent import. The report is printed
+// to the standard output at the end of the import as well.
+func WithReport(r *Report) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.Report = r
+	}
+}
+
+// WithGormConfigFile sets the YAML file of the gorm/gen settings: generate modes, pointers of the
+// nullable fields, JSON tags, unit tests and data type map.
+func WithGormConfigFile(path string) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.GormConfigFile = path
+	}
+}
+
+// WithGormMode sets the gorm/gen generate modes: "default_query", "query_interface" and "without_context".
+func WithGormMode(modes ...string) ImporterOption {
+	return withGorm(func(c *GormConfig) {
+		c.Mode = modes
+	})
+}
+
+// WithFieldNullable generates a pointer for the nullable columns with gorm.
+func WithFieldNullable(b bool) ImporterOption {
+	return withGorm(func(c *GormConfig) {
+		c.FieldNullable = b
+	})
+}
+
+// WithJSONTag names the JSON tags of the gorm models: "snake", "camel", "pascal" or "none".
+func WithJSONTag(strategy string) ImporterOption {
+	return withGorm(func(c *GormConfig) {
+		c.JSONTag = strategy
+	})
+}
+
+// WithUnitTest generates the unit tests of the gorm query code.
+func WithUnitTest(b bool) ImporterOption {
+	return withGorm(func(c *GormConfig) {
+		c.UnitTest = b
+	})
+}
+
+// WithDataTypeMap maps SQL types to the Go types of the gorm models, e.g. "json" to "datatypes.JSON" or
+// "tinyint(1)" to "bool". The mapping is added to the data types of the gorm config file.
+func WithDataTypeMap(m map[string]string) ImporterOption {
+	return withGorm(func(c *GormConfig) {
+		if c.DataTypes == nil {
+			c.DataTypes = make(map[string]string, len(m))
+		}
+		for sqlType, goType := range m {
+			c.DataTypes[sqlType] = goType
+		}
+	})
+}
+
+func withGorm(opt gorm.Option) ImporterOption {
+	return func(o *ImporterOptions) {
+		o.gorm = append(o.gorm, opt)
+	}
+}
@@ -0,0 +1,19 @@
+package sqlorm
+
+import "github.com/tx7do/go-wind-toolkit/sql-orm/internal/ent/entimport"
+
+type (
+	// Report collects the diagnostics of an ent import: the tables and columns that could not be
+	// imported, the columns imported with a fallback type and the relations left out.
+	Report = entimport.Report
+	// Diagnostic is an issue met while importing a table, or one of its columns.
+	Diagnostic = entimport.Diagnostic
+	// Severity is the level of a Diagnostic.
+	Severity = entimport.Severity
+)
+
+const (
+	SeverityError   = entimport.SeverityError
+	SeverityWarning = entimport.SeverityWarning
+	SeveritySkipped = entimport.SeveritySkipped
+)
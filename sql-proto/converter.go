@@ -5,7 +5,8 @@ import (
 	"errors"
 	"log"
 	"os"
-	"strings"
+
+	"github.com/tx7do/go-wind-toolkit/generators/datasource"
 
 	"github.com/tx7do/go-wind-toolkit/sql-proto/internal"
 	"github.com/tx7do/go-wind-toolkit/sql-proto/internal/mux"
@@ -21,6 +22,7 @@ func Convert(
 	serviceType *string,
 	includeTables, excludeTables []string,
 	exportProto bool,
+	opts ...ConvertOption,
 ) (TableDataArray, error) {
 	if outputPath == nil {
 		return nil, errors.New("sqlproto: proto file output path is nil")
@@ -35,7 +37,7 @@ func Convert(
 	_ = os.MkdirAll(*outputPath, os.ModePerm)
 
 	// Normalize the DSN to ensure it has a valid scheme
-	normalizedDSN := normalizeDSN(*dsn)
+	normalizedDSN := datasource.Normalize(*dsn)
 
 	convertDriver, err := mux.Default.OpenConvert(normalizedDSN)
 	if err != nil {
@@ -73,6 +75,7 @@ func Convert(
 			*serviceType,
 			*moduleName, *sourceModuleName, *moduleVersion,
 			tableDatas,
+			opts...,
 		); err != nil {
 			log.Fatalf("sqlproto: schema writing failed - %v", err)
 		}
@@ -80,22 +83,3 @@ func Convert(
 
 	return tableDatas, nil
 }
-
-// normalizeDSN normalizes the DSN to ensure it has a valid scheme.
-// If the input is a file path, it will be prefixed with "file://".
-// If it's SQL text content, it will be prefixed with "text://".
-// If it already has a scheme (mysql://, postgres://, etc.), it's returned as-is.
-func normalizeDSN(dsn string) string {
-	// Check if it already has a scheme
-	if strings.Contains(dsn, "://") {
-		return dsn
-	}
-
-	// Check if it's a file path
-	if _, err := os.Stat(dsn); err == nil {
-		return "file://" + dsn
-	}
-
-	// Treat it as SQL text content
-	return "text://" + dsn
-}
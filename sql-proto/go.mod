@@ -8,6 +8,7 @@ require (
 	github.com/go-sql-driver/mysql v1.9.3
 	github.com/jinzhu/inflection v1.0.0
 	github.com/lib/pq v1.11.1
+	github.com/mattn/go-sqlite3 v1.14.28
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/pflag v1.0.10
 	github.com/stretchr/testify v1.11.1
@@ -35,6 +35,12 @@ func NewConvert(opts ...ConvertOption) (SchemaConverter, error) {
 			return nil, err
 		}
 
+	case dialect.SQLite:
+		si, err = NewSQLite(i)
+		if err != nil {
+			return nil, err
+		}
+
 	case "text":
 		si, err = NewText(i)
 		if err != nil {
@@ -135,7 +141,7 @@ func convertTable(fnc fieldTypeFunc, table *schema.Table) (*TableData, error) {
 		for _, attr := range column.Attrs {
 			switch a := attr.(type) {
 			case *schema.Comment:
-				fieldData.Comment = a.Text
+				fieldData.setComment(a.Text)
 			}
 		}
 
@@ -1,6 +1,7 @@
 package mux
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
 	"strings"
@@ -19,8 +20,14 @@ type (
 	ConvertDriver struct {
 		io.Closer
 		schema.Inspector
+		// DB is the connection of the inspected database, used to query what Atlas does not inspect,
+		// e.g. the views. It is nil for the text sources.
+		DB         *sql.DB
 		Dialect    string
 		SchemaName string
+		// Schemas lists the schemas to convert when several are given, e.g. by a PostgreSQL search_path
+		// of "auth,billing,audit". SchemaName is the first of them.
+		Schemas []string
 	}
 )
 
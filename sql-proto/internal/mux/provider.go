@@ -2,19 +2,30 @@ package mux
 
 import (
 	"database/sql"
+	"fmt"
 	"net/url"
+	"os"
+	"strings"
 
 	atlasmysql "ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/sqlite"
 
 	"entgo.io/ent/dialect"
 	"github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/tx7do/go-wind-toolkit/generators/atlashcl"
+	"github.com/tx7do/go-wind-toolkit/generators/dbml"
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
+	"github.com/tx7do/go-wind-toolkit/generators/prisma"
 )
 
 func init() {
 	Default.RegisterProvider(mysqlProvider, "mysql")
 	Default.RegisterProvider(postgresProvider, "postgres", "postgresql")
-	Default.RegisterProvider(textProvider, "text", "file")
+	Default.RegisterProvider(sqliteProvider, "sqlite", "sqlite3")
+	Default.RegisterProvider(textProvider, "text", "file", migrations.Scheme, dbml.Scheme, prisma.Scheme, atlashcl.Scheme)
 }
 
 func mysqlProvider(dsn string) (*ConvertDriver, error) {
@@ -33,6 +44,7 @@ func mysqlProvider(dsn string) (*ConvertDriver, error) {
 	}
 	return &ConvertDriver{
 		Closer:     db,
+		DB:         db,
 		Inspector:  drv,
 		Dialect:    dialect.MySQL,
 		SchemaName: cfg.DBName,
@@ -54,21 +66,74 @@ func postgresProvider(dsn string) (*ConvertDriver, error) {
 	if err != nil {
 		return nil, err
 	}
-	schemaName := "public"
-	if s := parsed.Query().Get("search_path"); s != "" {
-		schemaName = s
+	schemas := splitSchemas(parsed.Query().Get("search_path"))
+	if len(schemas) == 0 {
+		schemas = []string{"public"}
 	}
 	return &ConvertDriver{
 		Closer:     db,
+		DB:         db,
 		Inspector:  drv,
 		Dialect:    dialect.Postgres,
-		SchemaName: schemaName,
+		SchemaName: schemas[0],
+		Schemas:    schemas,
 	}, nil
 }
 
+func sqliteProvider(dsn string) (*ConvertDriver, error) {
+	// dsn example: sqlite://path/to/test.db or sqlite://file:test.db?_fk=1
+	if path := sqlitePath(dsn); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("sqlite database file %q: %w", path, err)
+		}
+	}
+	db, err := sql.Open(dialect.SQLite, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := sqlite.Open(db)
+	if err != nil {
+		return nil, err
+	}
+	return &ConvertDriver{
+		Closer:     db,
+		DB:         db,
+		Inspector:  drv,
+		Dialect:    dialect.SQLite,
+		SchemaName: "main",
+	}, nil
+}
+
+// sqlitePath returns the database file referenced by a SQLite DSN,
+// or an empty string for in-memory databases.
+func sqlitePath(dsn string) string {
+	path := strings.TrimPrefix(dsn, "file:")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" || path == ":memory:" || strings.Contains(dsn, "mode=memory") {
+		return ""
+	}
+	return path
+}
+
+// textProvider serves the offline sources: SQL text, a DDL file, a folder of migrations, a DBML file, a
+// Prisma schema or Atlas HCL files. They are all read by the text importer, which loads the migrations://,
+// dbml://, prisma:// and hcl:// sources with their own parsers instead of parsing them as SQL.
 func textProvider(dsn string) (*ConvertDriver, error) {
 	return &ConvertDriver{
 		SchemaName: dsn,
 		Dialect:    "text",
 	}, nil
 }
+
+// splitSchemas splits a comma-separated list of schemas, e.g. the search_path "auth, billing".
+func splitSchemas(list string) []string {
+	var schemas []string
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			schemas = append(schemas, s)
+		}
+	}
+	return schemas
+}
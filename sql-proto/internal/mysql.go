@@ -63,6 +63,9 @@ type MySQL struct {
 
 // NewMySQL - create aמ import structure for MySQL.
 func NewMySQL(i *ConvertOptions) (*MySQL, error) {
+	if err := i.compileFilter(); err != nil {
+		return nil, err
+	}
 	return &MySQL{
 		ConvertOptions: i,
 	}, nil
@@ -70,29 +73,24 @@ func NewMySQL(i *ConvertOptions) (*MySQL, error) {
 
 func (m *MySQL) SchemaTables(ctx context.Context) ([]*TableData, error) {
 	inspectOptions := &schema.InspectOptions{
-		Tables: m.includedTables,
+		Tables: m.filter.Names(),
 	}
 	s, err := m.driver.InspectSchema(ctx, m.driver.SchemaName, inspectOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	tables := s.Tables
-	if m.excludedTables != nil {
-		tables = nil
-		excludedTableNames := make(map[string]bool)
-		for _, t := range m.excludedTables {
-			excludedTableNames[t] = true
-		}
-		// filter out includedTables that are in excludedTables:
-		for _, t := range s.Tables {
-			if !excludedTableNames[t.Name] {
-				tables = append(tables, t)
-			}
-		}
-	}
+	tables := m.filterTables(s.Tables)
 
-	return schemaTables(MySQLFieldType, tables)
+	tableDatas, err := schemaTables(MySQLFieldType, tables)
+	if err != nil {
+		return nil, err
+	}
+	views, err := m.schemaViews(ctx, MySQLFieldType, m.driver.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+	return append(tableDatas, views...), nil
 }
 
 func MySQLFieldType(sqlType string) (f string) {
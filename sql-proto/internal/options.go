@@ -1,6 +1,10 @@
 package internal
 
 import (
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/tx7do/go-wind-toolkit/generators/tablefilter"
+
 	"github.com/tx7do/go-wind-toolkit/sql-proto/internal/mux"
 )
 
@@ -17,6 +21,9 @@ type Options struct {
 	Module       string `json:"module"`       // Module name for the generated code, e.g., "admin"
 	Version      string `json:"version"`      // Version of the module, e.g., "v1"
 	Service      string `json:"service"`      // generate service code, "rest" for REST service, "grpc" for gRPC service
+
+	NamingFile    string `json:"naming_file"`    // YAML file of the naming rules shared with sql2orm and sql2kratos
+	SchemaModules bool   `json:"schema_modules"` // Put the messages of each PostgreSQL schema into a module named after it
 }
 
 type (
@@ -24,6 +31,7 @@ type (
 	ConvertOptions struct {
 		includedTables []string
 		excludedTables []string
+		filter         *tablefilter.Filter
 
 		protoPath  string
 		schemaPath string
@@ -78,14 +86,15 @@ func WithServiceType(serviceType string) ConvertOption {
 	}
 }
 
-// WithIncludedTables limits the schema import to a set of given includedTables (by all includedTables are imported)
+// WithIncludedTables limits the schema import to a set of given includedTables (by all includedTables are imported).
+// The tables may be given by glob or regular expression patterns, see tablefilter.Filter.
 func WithIncludedTables(tables []string) ConvertOption {
 	return func(i *ConvertOptions) {
 		i.includedTables = tables
 	}
 }
 
-// WithExcludedTables supplies the set of includedTables to exclude.
+// WithExcludedTables supplies the set of includedTables to exclude, given by name or by pattern.
 func WithExcludedTables(tables []string) ConvertOption {
 	return func(i *ConvertOptions) {
 		i.excludedTables = tables
@@ -98,3 +107,24 @@ func WithDriver(drv *mux.ConvertDriver) ConvertOption {
 		i.driver = drv
 	}
 }
+
+// filterTables 返回需要转换的表
+func (c *ConvertOptions) filterTables(tables []*schema.Table) []*schema.Table {
+	var converted []*schema.Table
+	for _, t := range tables {
+		schemaName := ""
+		if t.Schema != nil {
+			schemaName = t.Schema.Name
+		}
+		if c.convertedTable(schemaName, t.Name) {
+			converted = append(converted, t)
+		}
+	}
+	return converted
+}
+
+// compileFilter 编译包含和排除的表，表可以使用表名、通配符或正则表达式
+func (c *ConvertOptions) compileFilter() (err error) {
+	c.filter, err = tablefilter.New(c.includedTables, c.excludedTables)
+	return err
+}
@@ -73,34 +73,62 @@ type Postgres struct {
 
 // NewPostgreSQL - returns a new *Postgres.
 func NewPostgreSQL(i *ConvertOptions) (SchemaConverter, error) {
+	if err := i.compileFilter(); err != nil {
+		return nil, err
+	}
 	return &Postgres{
 		ConvertOptions: i,
 	}, nil
 }
 
 func (p *Postgres) SchemaTables(ctx context.Context) ([]*TableData, error) {
+	if len(p.driver.Schemas) > 1 {
+		return p.schemasTables(ctx)
+	}
 	inspectOptions := &schema.InspectOptions{
-		Tables: p.includedTables,
+		Tables: p.filter.Names(),
 	}
 	s, err := p.driver.InspectSchema(ctx, p.driver.SchemaName, inspectOptions)
 	if err != nil {
 		return nil, err
 	}
-	tables := s.Tables
-	if p.excludedTables != nil {
-		tables = nil
-		excludedTableNames := make(map[string]bool)
-		for _, t := range p.excludedTables {
-			excludedTableNames[t] = true
+	tables := p.filterTables(s.Tables)
+	tableDatas, err := schemaTables(PostgresFieldType, tables)
+	if err != nil {
+		return nil, err
+	}
+	views, err := p.schemaViews(ctx, PostgresFieldType, p.driver.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+	return append(tableDatas, views...), nil
+}
+
+// schemasTables 转换多个模式的表，包含和排除的表可以使用表名或带模式名的表名，例如 "auth.users"
+func (p *Postgres) schemasTables(ctx context.Context) ([]*TableData, error) {
+	realm, err := p.driver.InspectRealm(ctx, &schema.InspectRealmOption{
+		Schemas: p.driver.Schemas,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tableDatas []*TableData
+	for _, s := range realm.Schemas {
+		datas, err := schemaTables(PostgresFieldType, p.filterTables(s.Tables))
+		if err != nil {
+			return nil, err
+		}
+		views, err := p.schemaViews(ctx, PostgresFieldType, s.Name)
+		if err != nil {
+			return nil, err
 		}
-		// filter out includedTables that are in excludedTables:
-		for _, t := range s.Tables {
-			if !excludedTableNames[t.Name] {
-				tables = append(tables, t)
-			}
+		datas = append(datas, views...)
+		for _, d := range datas {
+			d.Schema = s.Name
 		}
+		tableDatas = append(tableDatas, datas...)
 	}
-	return schemaTables(PostgresFieldType, tables)
+	return tableDatas, nil
 }
 
 func PostgresFieldType(sqlType string) (f string) {
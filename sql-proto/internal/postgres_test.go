@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"entgo.io/ent/dialect"
+
+	"github.com/tx7do/go-wind-toolkit/sql-proto/internal/mux"
+)
+
+// realmInspector returns the same realm for any inspection of the schemas
+type realmInspector struct {
+	schema.Inspector
+	realm *schema.Realm
+}
+
+func (r *realmInspector) InspectRealm(context.Context, *schema.InspectRealmOption) (*schema.Realm, error) {
+	return r.realm, nil
+}
+
+// TestPostgresSchemasTables tests the conversion of the tables of several schemas
+func TestPostgresSchemasTables(t *testing.T) {
+	newTable := func(name string) *schema.Table {
+		id := schema.NewIntColumn("id", "integer")
+		return schema.NewTable(name).AddColumns(id).SetPrimaryKey(schema.NewPrimaryKey(id))
+	}
+	drv := &mux.ConvertDriver{
+		Inspector: &realmInspector{realm: schema.NewRealm(
+			schema.New("auth").AddTables(newTable("users")),
+			schema.New("billing").AddTables(newTable("invoices"), newTable("users")),
+			schema.New("audit").AddTables(newTable("logs")),
+		)},
+		Dialect:    dialect.Postgres,
+		SchemaName: "auth",
+		Schemas:    []string{"auth", "billing", "audit"},
+	}
+
+	converter, err := NewConvert(
+		WithDriver(drv),
+		WithExcludedTables([]string{"audit.logs"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create PostgreSQL converter: %v", err)
+	}
+
+	tables, err := converter.SchemaTables(context.Background())
+	if err != nil {
+		t.Fatalf("failed to inspect schema tables: %v", err)
+	}
+
+	expected := []string{"auth.users", "billing.invoices", "billing.users"}
+	if len(tables) != len(expected) {
+		t.Fatalf("expected %d tables, got %d", len(expected), len(tables))
+	}
+	for i, want := range expected {
+		if got := tables[i].QualifiedName(); got != want {
+			t.Errorf("table %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
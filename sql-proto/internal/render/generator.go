@@ -31,6 +31,7 @@ func WriteGrpcServiceProto(outputPath string, data GrpcProtoTemplateData) error
 			"Model":     data.Name,
 			"ModelName": data.Comment,
 			"Fields":    data.Fields,
+			"ReadOnly":  data.ReadOnly,
 		},
 	}
 
@@ -59,6 +60,7 @@ func WriteRestServiceProto(outputPath string, data RestProtoTemplateData) error
 			"ModelName":     data.Comment,
 			"Path":          data.Path(),
 			"Model":         data.Name,
+			"ReadOnly":      data.ReadOnly,
 		},
 	}
 
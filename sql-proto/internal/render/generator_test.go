@@ -5,6 +5,8 @@ import (
 
 	"github.com/jinzhu/inflection"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
 )
 
 func TestPlural(t *testing.T) {
@@ -23,6 +25,19 @@ func TestSingular(t *testing.T) {
 	t.Log(inflection.Singular("adminLoginLogs"))
 }
 
+func TestRestProtoPath(t *testing.T) {
+	data := RestProtoTemplateData{TargetModule: "admin", Version: "v1", Name: "login_log"}
+	assert.Equal(t, "/admin/v1/login-logs", data.Path())
+
+	data.Name = "user_data"
+	data.Naming = naming.New(naming.Config{Irregulars: map[string]string{"data": "data"}})
+	assert.Equal(t, "/admin/v1/user-data", data.Path())
+
+	data.Name = "yonghu"
+	data.Naming = naming.New(naming.Config{NoInflection: true})
+	assert.Equal(t, "/admin/v1/yonghu", data.Path())
+}
+
 func TestWriteGrpcServiceProto(t *testing.T) {
 	data := GrpcProtoTemplateData{
 		Module:  "user",
@@ -3,9 +3,9 @@ package render
 import (
 	"strings"
 
-	"github.com/jinzhu/inflection"
 	"github.com/tx7do/go-utils/stringcase"
 	"github.com/tx7do/go-wind-toolkit/generators"
+	"github.com/tx7do/go-wind-toolkit/generators/naming"
 )
 
 type ProtoFieldArray []generators.ProtoField
@@ -18,6 +18,8 @@ type GrpcProtoTemplateData struct {
 	Module string // 模块名
 
 	Fields ProtoFieldArray // 字段列表
+
+	ReadOnly bool // 只读服务，只生成 List、Count 和 Get 方法，例如视图
 }
 
 func (d GrpcProtoTemplateData) PascalName() string {
@@ -38,6 +40,10 @@ type RestProtoTemplateData struct {
 
 	SourceModule string
 	TargetModule string
+
+	Naming *naming.Strategy // 命名规则，用于资源路径的复数形式
+
+	ReadOnly bool // 只读服务，只生成 List 和 Get 方法，例如视图
 }
 
 func (d RestProtoTemplateData) PascalName() string {
@@ -46,7 +52,7 @@ func (d RestProtoTemplateData) PascalName() string {
 
 func (d RestProtoTemplateData) Path() string {
 	// 比如：/admin/v1/users
-	return "/" + strings.ToLower(d.TargetModule) + "/" + d.Version + "/" + stringcase.KebabCase(inflection.Plural(d.Name))
+	return "/" + strings.ToLower(d.TargetModule) + "/" + d.Version + "/" + stringcase.KebabCase(d.Naming.Plural(d.Name))
 }
 
 func (d RestProtoTemplateData) SourceProto() string {
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// SQLite到Protobuf的类型映射
+var sqliteTypeMapping = map[string]string{
+	// 整数类型
+	"INTEGER":          "int64",
+	"INT":              "int64",
+	"TINYINT":          "int32",
+	"SMALLINT":         "int32",
+	"MEDIUMINT":        "int32",
+	"BIGINT":           "int64",
+	"INT2":             "int32",
+	"INT8":             "int64",
+	"UNSIGNED BIG INT": "uint64",
+
+	// 浮点类型
+	"REAL":             "double",
+	"DOUBLE":           "double",
+	"DOUBLE PRECISION": "double",
+	"FLOAT":            "double",
+	"NUMERIC":          "string", // Protobuf没有直接对应类型，通常用string表示
+	"DECIMAL":          "string",
+
+	// 字符串类型
+	"TEXT":              "string",
+	"CHAR":              "string",
+	"CHARACTER":         "string",
+	"VARCHAR":           "string",
+	"VARYING CHARACTER": "string",
+	"NCHAR":             "string",
+	"NATIVE CHARACTER":  "string",
+	"NVARCHAR":          "string",
+	"CLOB":              "string",
+
+	// 二进制类型
+	"BLOB": "bytes",
+
+	// 日期和时间类型
+	"DATE":      "string",
+	"TIME":      "string",
+	"DATETIME":  "string",
+	"TIMESTAMP": "string",
+
+	// 布尔类型
+	"BOOLEAN": "bool",
+	"BOOL":    "bool",
+
+	// JSON类型
+	"JSON":  "string",
+	"JSONB": "string",
+
+	// UUID类型
+	"UUID": "string",
+}
+
+// SQLite implements SchemaConverter for SQLite databases.
+type SQLite struct {
+	*ConvertOptions
+}
+
+// NewSQLite - returns a new *SQLite.
+func NewSQLite(i *ConvertOptions) (SchemaConverter, error) {
+	if err := i.compileFilter(); err != nil {
+		return nil, err
+	}
+	return &SQLite{
+		ConvertOptions: i,
+	}, nil
+}
+
+func (s *SQLite) SchemaTables(ctx context.Context) ([]*TableData, error) {
+	inspectOptions := &schema.InspectOptions{
+		Tables: s.filter.Names(),
+	}
+	sc, err := s.driver.InspectSchema(ctx, s.driver.SchemaName, inspectOptions)
+	if err != nil {
+		return nil, err
+	}
+	tables := s.filterTables(sc.Tables)
+	tableDatas, err := schemaTables(SQLiteFieldType, tables)
+	if err != nil {
+		return nil, err
+	}
+	views, err := s.schemaViews(ctx, SQLiteFieldType, s.driver.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+	return append(tableDatas, views...), nil
+}
+
+func SQLiteFieldType(sqlType string) (f string) {
+	sqlType = strings.ToUpper(sqlType)
+
+	// 去除类型声明中的括号部分，例如 "VARCHAR(255)" -> "VARCHAR"
+	baseType := strings.SplitN(sqlType, "(", 2)[0]
+	baseType = strings.TrimSpace(strings.ToUpper(baseType))
+
+	// 查找映射
+	if protoType, exists := sqliteTypeMapping[baseType]; exists {
+		return protoType
+	}
+
+	// 按照 SQLite 的类型亲和性规则推断：https://www.sqlite.org/datatype3.html
+	switch {
+	case strings.Contains(baseType, "INT"):
+		return "int64"
+	case strings.Contains(baseType, "CHAR"),
+		strings.Contains(baseType, "CLOB"),
+		strings.Contains(baseType, "TEXT"):
+		return "string"
+	case strings.Contains(baseType, "BLOB"):
+		return "bytes"
+	case strings.Contains(baseType, "REAL"),
+		strings.Contains(baseType, "FLOA"),
+		strings.Contains(baseType, "DOUB"):
+		return "double"
+	}
+
+	return ""
+}
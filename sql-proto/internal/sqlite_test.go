@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"entgo.io/ent/dialect"
+
+	"github.com/tx7do/go-wind-toolkit/sql-proto/internal/mux"
+)
+
+// TestSQLiteSchemaTables tests inspecting a SQLite database file and converting it to table data
+func TestSQLiteSchemaTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open(dialect.SQLite, path)
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE users (id integer PRIMARY KEY, name varchar(100) NOT NULL, score real NULL, active boolean NOT NULL, avatar blob NULL)`,
+		`CREATE TABLE logs (id integer PRIMARY KEY, message text NOT NULL)`,
+	} {
+		if _, err = db.Exec(stmt); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+	_ = db.Close()
+
+	drv, err := mux.Default.OpenConvert("sqlite://" + path)
+	if err != nil {
+		t.Fatalf("failed to open sqlite driver: %v", err)
+	}
+
+	converter, err := NewConvert(
+		WithDriver(drv),
+		WithExcludedTables([]string{"logs"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create SQLite converter: %v", err)
+	}
+
+	tables, err := converter.SchemaTables(context.Background())
+	if err != nil {
+		t.Fatalf("failed to inspect schema tables: %v", err)
+	}
+
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+	if tables[0].Name != "users" {
+		t.Errorf("expected table name 'users', got %q", tables[0].Name)
+	}
+
+	expected := []FieldData{
+		{Name: "id", Type: "int64"},
+		{Name: "name", Type: "string"},
+		{Name: "score", Type: "double", Null: true},
+		{Name: "active", Type: "bool"},
+		{Name: "avatar", Type: "bytes", Null: true},
+	}
+	if len(tables[0].Fields) != len(expected) {
+		t.Fatalf("expected %d fields, got %d", len(expected), len(tables[0].Fields))
+	}
+	for i, want := range expected {
+		got := tables[0].Fields[i]
+		if got.Name != want.Name || got.Type != want.Type {
+			t.Errorf("field %d: expected %s %s, got %s %s", i, want.Name, want.Type, got.Name, got.Type)
+		}
+		if i > 0 && got.Null != want.Null {
+			t.Errorf("field %q: expected nullable %v, got %v", got.Name, want.Null, got.Null)
+		}
+	}
+}
+
+// TestSQLiteSchemaViews tests converting the views of a SQLite database to read-only table data
+func TestSQLiteSchemaViews(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open(dialect.SQLite, path)
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE users (id integer PRIMARY KEY, name varchar(100) NOT NULL, score integer NOT NULL)`,
+		`CREATE VIEW user_scores AS SELECT id, name, score FROM users WHERE score > 0`,
+		`CREATE VIEW top_users AS SELECT id FROM users ORDER BY score DESC LIMIT 10`,
+	} {
+		if _, err = db.Exec(stmt); err != nil {
+			t.Fatalf("failed to execute %q: %v", stmt, err)
+		}
+	}
+	_ = db.Close()
+
+	drv, err := mux.Default.OpenConvert("sqlite://" + path)
+	if err != nil {
+		t.Fatalf("failed to open sqlite driver: %v", err)
+	}
+
+	converter, err := NewConvert(
+		WithDriver(drv),
+		WithExcludedTables([]string{"top_users"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create SQLite converter: %v", err)
+	}
+
+	tables, err := converter.SchemaTables(context.Background())
+	if err != nil {
+		t.Fatalf("failed to inspect schema tables: %v", err)
+	}
+
+	if len(tables) != 2 {
+		t.Fatalf("expected 1 table and 1 view, got %d", len(tables))
+	}
+	if tables[0].Name != "users" || tables[0].View {
+		t.Errorf("expected table 'users', got %q (view: %v)", tables[0].Name, tables[0].View)
+	}
+	view := tables[1]
+	if view.Name != "user_scores" || !view.View {
+		t.Fatalf("expected view 'user_scores', got %q (view: %v)", view.Name, view.View)
+	}
+
+	// SQLite does not keep the NOT NULL constraints of the columns of a view.
+	expected := []FieldData{
+		{Name: "id", Type: "int64", Null: true},
+		{Name: "name", Type: "string", Null: true},
+		{Name: "score", Type: "int64", Null: true},
+	}
+	if len(view.Fields) != len(expected) {
+		t.Fatalf("expected %d fields, got %d", len(expected), len(view.Fields))
+	}
+	for i, want := range expected {
+		if got := view.Fields[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("field %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+// TestSQLiteFieldType tests the SQLite type mapping, including the type affinity fallback
+func TestSQLiteFieldType(t *testing.T) {
+	tests := map[string]string{
+		"INTEGER":        "int64",
+		"varchar(255)":   "string",
+		"DOUBLE":         "double",
+		"decimal(10, 2)": "string",
+		"blob":           "bytes",
+		"boolean":        "bool",
+		"datetime":       "string",
+		"UNSIGNED INT":   "int64",
+		"NATIVE TEXT":    "string",
+		"FLOAT4":         "double",
+		"geometry":       "",
+	}
+	for sqlType, want := range tests {
+		if got := SQLiteFieldType(sqlType); got != want {
+			t.Errorf("SQLiteFieldType(%q): expected %q, got %q", sqlType, want, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/postgres"
@@ -12,6 +13,11 @@ import (
 	"ariga.io/atlas/sql/sqlite"
 
 	ddlparser "github.com/tx7do/go-utils/ddl_parser"
+
+	"github.com/tx7do/go-wind-toolkit/generators/atlashcl"
+	"github.com/tx7do/go-wind-toolkit/generators/dbml"
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
+	"github.com/tx7do/go-wind-toolkit/generators/prisma"
 )
 
 type Text struct {
@@ -19,6 +25,9 @@ type Text struct {
 }
 
 func NewText(i *ConvertOptions) (*Text, error) {
+	if err := i.compileFilter(); err != nil {
+		return nil, err
+	}
 	return &Text{
 		ConvertOptions: i,
 	}, nil
@@ -170,14 +179,7 @@ func (t *Text) InspectSchema(sqlContent string, s *schema.Schema) (*schema.Schem
 }
 
 func (t *Text) SchemaTables(_ context.Context) ([]*TableData, error) {
-	// 加载 SQL 文本
-	sqlText := t.loadSQLFromFile()
-	if sqlText == "" {
-		return nil, fmt.Errorf("无法加载 SQL 文件: %v", t.schemaPath)
-	}
-
-	var s schema.Schema
-	_, err := t.InspectSchema(sqlText, &s)
+	s, err := t.loadSchema()
 	if err != nil {
 		return nil, err
 	}
@@ -187,38 +189,35 @@ func (t *Text) SchemaTables(_ context.Context) ([]*TableData, error) {
 		return nil, fmt.Errorf("无效的 SQL: 无法解析任何有效的表")
 	}
 
-	tables := s.Tables
+	tables := t.filterTables(s.Tables)
 
-	// Filter by includedTables if specified
-	if t.includedTables != nil && len(t.includedTables) > 0 {
-		includedTableNames := make(map[string]bool)
-		for _, tableName := range t.includedTables {
-			includedTableNames[tableName] = true
-		}
-		tables = nil
-		for _, table := range s.Tables {
-			if includedTableNames[table.Name] {
-				tables = append(tables, table)
-			}
-		}
+	return schemaTables(t.fieldType, tables)
+}
+
+// loadSchema 加载表结构：migrations:// 目录按版本顺序回放其中的迁移文件，dbml://、prisma:// 与 hcl:// 由各自的解析器读取，否则解析 SQL 文本
+func (t *Text) loadSchema() (*schema.Schema, error) {
+	switch scheme, path, _ := strings.Cut(t.schemaPath, "://"); scheme {
+	case migrations.Scheme:
+		return migrations.Load(path)
+	case dbml.Scheme:
+		return dbml.Load(path)
+	case prisma.Scheme:
+		return prisma.Load(path)
+	case atlashcl.Scheme:
+		return atlashcl.Load(path)
 	}
 
-	// Filter out excludedTables
-	if t.excludedTables != nil && len(t.excludedTables) > 0 {
-		excludedTableNames := make(map[string]bool)
-		for _, tableName := range t.excludedTables {
-			excludedTableNames[tableName] = true
-		}
-		filteredTables := make([]*schema.Table, 0)
-		for _, table := range tables {
-			if !excludedTableNames[table.Name] {
-				filteredTables = append(filteredTables, table)
-			}
-		}
-		tables = filteredTables
+	// 加载 SQL 文本
+	sqlText := t.loadSQLFromFile()
+	if sqlText == "" {
+		return nil, fmt.Errorf("无法加载 SQL 文件: %v", t.schemaPath)
 	}
 
-	return schemaTables(t.fieldType, tables)
+	var s schema.Schema
+	if _, err := t.InspectSchema(sqlText, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
 }
 
 func (t *Text) fieldType(sqlType string) (f string) {
@@ -1,8 +1,13 @@
 package internal
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/tx7do/go-wind-toolkit/generators/dbml"
+	"github.com/tx7do/go-wind-toolkit/generators/migrations"
 	"github.com/tx7do/go-wind-toolkit/sql-proto/internal/mux"
 )
 
@@ -25,3 +30,121 @@ func TestNewTextConvert(t *testing.T) {
 		t.Error("converter should not be nil")
 	}
 }
+
+// TestTextMigrations tests replaying a folder of migrations, including the ALTER TABLE statements
+func TestTextMigrations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"V1__create_users.sql": "CREATE TABLE users (id BIGINT PRIMARY KEY, name VARCHAR(64), legacy TEXT);",
+		"V2__alter_users.sql": `
+ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL;
+ALTER TABLE users DROP COLUMN legacy;
+ALTER TABLE users MODIFY COLUMN name VARCHAR(64) NOT NULL COMMENT 'user name';`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	converter, err := NewText(&ConvertOptions{
+		schemaPath: migrations.Scheme + "://" + dir,
+		driver: &mux.ConvertDriver{
+			Dialect:    "text",
+			SchemaName: dir,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create Text converter: %v", err)
+	}
+
+	tables, err := converter.SchemaTables(context.Background())
+	if err != nil {
+		t.Fatalf("failed to replay the migrations: %v", err)
+	}
+	if len(tables) != 1 || len(tables[0].Fields) != 3 {
+		t.Fatalf("expected the users table with 3 fields, got %+v", tables)
+	}
+	for i, want := range []string{"id", "name", "email"} {
+		if got := tables[0].Fields[i].Name; got != want {
+			t.Errorf("expected field %d to be %q, got %q", i, want, got)
+		}
+	}
+	if name := tables[0].Fields[1]; name.Null || name.Comment != "user name" {
+		t.Errorf("expected field 'name' to be not null and commented, got %+v", name)
+	}
+}
+
+// TestTextDBML tests reading the tables and notes of a DBML file
+func TestTextDBML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shop.dbml")
+	content := `
+Table users [note: 'the users'] {
+  id bigint [pk]
+  name varchar(64) [not null, note: 'user name']
+  status user_status
+}
+
+Enum user_status {
+  active
+  disabled
+}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	converter, err := NewText(&ConvertOptions{
+		schemaPath: dbml.Scheme + "://" + path,
+		driver: &mux.ConvertDriver{
+			Dialect:    "text",
+			SchemaName: path,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create Text converter: %v", err)
+	}
+
+	tables, err := converter.SchemaTables(context.Background())
+	if err != nil {
+		t.Fatalf("failed to read the DBML file: %v", err)
+	}
+	if len(tables) != 1 || len(tables[0].Fields) != 3 {
+		t.Fatalf("expected the users table with 3 fields, got %+v", tables)
+	}
+	if name := tables[0].Fields[1]; name.Null || name.Comment != "user name" {
+		t.Errorf("expected field 'name' to be not null and commented, got %+v", name)
+	}
+}
+
+// TestTextCommentAnnotations tests parsing the annotations of the column comments
+func TestTextCommentAnnotations(t *testing.T) {
+	converter, err := NewText(&ConvertOptions{
+		schemaPath: `CREATE TABLE users (
+  id BIGINT PRIMARY KEY,
+  status INT COMMENT '用户状态 @enum(1:ACTIVE,2:DISABLED) @json(status_code)',
+  password VARCHAR(64) COMMENT '密码 @sensitive'
+)`,
+		driver: &mux.ConvertDriver{
+			Dialect:    "text",
+			SchemaName: "public",
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create Text converter: %v", err)
+	}
+
+	tables, err := converter.SchemaTables(context.Background())
+	if err != nil {
+		t.Fatalf("failed to parse the SQL: %v", err)
+	}
+	if len(tables) != 1 || len(tables[0].Fields) != 3 {
+		t.Fatalf("expected the users table with 3 fields, got %+v", tables)
+	}
+	status := tables[0].Fields[1]
+	if status.Comment != "用户状态" || len(status.Annotations.Enum) != 2 || status.Annotations.JSON != "status_code" {
+		t.Errorf("expected field 'status' to be an enum named status_code, got %+v", status)
+	}
+	if password := tables[0].Fields[2]; password.Comment != "密码" || !password.Annotations.Sensitive {
+		t.Errorf("expected field 'password' to be sensitive, got %+v", password)
+	}
+}
@@ -2,29 +2,48 @@ package internal
 
 import (
 	"context"
+
+	"github.com/tx7do/go-wind-toolkit/generators/annotation"
 )
 
 // FieldData 字段数据
 type FieldData struct {
-	Name    string // 字段名
-	Type    string // 字段类型
-	Null    bool   // 是否允许为 NULL
-	Comment string // 字段注释
+	Name        string                 // 字段名
+	Type        string                 // 字段类型
+	Null        bool                   // 是否允许为 NULL
+	Comment     string                 // 字段注释，不含注解
+	Annotations annotation.Annotations // 字段注释中的注解
+}
+
+// setComment 解析字段注释中的注解，保存不含注解的注释
+func (f *FieldData) setComment(text string) {
+	f.Annotations = annotation.Parse(text)
+	f.Comment = f.Annotations.Comment
 }
 
 // TableData 表数据
 type TableData struct {
+	Schema    string      // 模式名，仅在转换多个 PostgreSQL 模式时设置
 	Name      string      // 表名
 	Comment   string      // 表注释
 	Charset   string      // 字符集
 	Collation string      // 排序规则
 	Fields    []FieldData // 字段数据
+	View      bool        // 是否为视图，视图只生成只读的服务
 }
 
 func (t TableData) WithComment() bool {
 	return t.Comment != ""
 }
 
+// QualifiedName 返回带模式名的表名，例如 "auth.users"；未设置模式时返回表名
+func (t TableData) QualifiedName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
 type SchemaConverter interface {
 	SchemaTables(context.Context) ([]*TableData, error)
 }
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tx7do/go-wind-toolkit/generators/dbview"
+)
+
+// schemaViews 转换模式中的视图，视图同样适用包含和排除的表，表名可以带模式名，例如 "report.user_scores"
+func (c *ConvertOptions) schemaViews(ctx context.Context, fnc fieldTypeFunc, schemaName string) ([]*TableData, error) {
+	if c.driver.DB == nil {
+		return nil, nil
+	}
+	dbViews, err := dbview.Views(ctx, c.driver.DB, c.driver.Dialect, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("sqlproto: inspect views: %w", err)
+	}
+	var views []*TableData
+	for _, v := range dbViews {
+		if !c.convertedTable(schemaName, v.Name) {
+			continue
+		}
+		view := &TableData{Name: v.Name, Comment: v.Comment, View: true}
+		if view.Fields, err = c.viewFields(ctx, fnc, schemaName, v.Name); err != nil {
+			return nil, fmt.Errorf("sqlproto: inspect columns of view %v: %w", v.Name, err)
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// viewFields 返回视图列的字段数据
+func (c *ConvertOptions) viewFields(ctx context.Context, fnc fieldTypeFunc, schemaName, name string) ([]FieldData, error) {
+	columns, err := dbview.Columns(ctx, c.driver.DB, c.driver.Dialect, schemaName, name)
+	if err != nil {
+		return nil, err
+	}
+	var fields []FieldData
+	for _, col := range columns {
+		field := FieldData{Name: col.Name, Null: col.Null}
+		field.setComment(col.Comment)
+		if field.Type = fnc(col.Type); field.Type == "" {
+			field.Type = "string"
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// convertedTable 判断表或视图是否需要转换
+func (c *ConvertOptions) convertedTable(schemaName, name string) bool {
+	return c.filter.Match(schemaName, name)
+}
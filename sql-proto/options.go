@@ -0,0 +1,48 @@
+package sqlproto
+
+import "github.com/tx7do/go-wind-toolkit/generators/naming"
+
+// ConvertOptions holds the optional settings of Convert.
+type ConvertOptions struct {
+	// Naming derives the message names from the table names. The English inflection is applied by default.
+	Naming *naming.Strategy
+	// SchemaModules puts the messages of each PostgreSQL schema into a module named after the schema.
+	SchemaModules bool
+	// ReadOnly generates the services exposing List, Count and Get only, e.g. for the views.
+	ReadOnly bool
+}
+
+// ConvertOption configures the optional settings of Convert.
+type ConvertOption func(*ConvertOptions)
+
+// WithNaming sets the strategy deriving the message names from the table names,
+// shared with sql2orm and sql2kratos.
+func WithNaming(s *naming.Strategy) ConvertOption {
+	return func(o *ConvertOptions) {
+		o.Naming = s
+	}
+}
+
+// WithSchemaModules puts the messages of each converted PostgreSQL schema into a module named after
+// the schema, instead of the given module. The REST services then refer to the module of the schema.
+func WithSchemaModules(b bool) ConvertOption {
+	return func(o *ConvertOptions) {
+		o.SchemaModules = b
+	}
+}
+
+// WithReadOnly generates the services of a read-only model, e.g. a view, without the methods
+// creating, updating or deleting it.
+func WithReadOnly(b bool) ConvertOption {
+	return func(o *ConvertOptions) {
+		o.ReadOnly = b
+	}
+}
+
+func newConvertOptions(opts []ConvertOption) *ConvertOptions {
+	o := &ConvertOptions{}
+	for _, apply := range opts {
+		apply(o)
+	}
+	return o
+}
@@ -2,13 +2,14 @@ package sqlproto
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"regexp"
 	"strings"
 
-	"github.com/jinzhu/inflection"
 	"github.com/tx7do/go-wind-toolkit/generators"
 
+	"github.com/tx7do/go-wind-toolkit/sql-proto/internal"
 	"github.com/tx7do/go-wind-toolkit/sql-proto/internal/render"
 )
 
@@ -22,16 +23,20 @@ func WriteServiceProto(
 	tableName string,
 	tableComment string,
 	protoFields ProtoFieldArray,
+	opts ...ConvertOption,
 ) error {
+	o := newConvertOptions(opts)
 	switch strings.TrimSpace(strings.ToLower(serviceType)) {
 	case "grpc":
 		data := render.GrpcProtoTemplateData{
 			Module:  targetModuleName,
 			Version: moduleVersion,
 
-			Name:    inflection.Singular(tableName),
+			Name:    o.Naming.Name(tableName),
 			Comment: RemoveTableCommentSuffix(tableComment),
 			Fields:  render.ProtoFieldArray(protoFields),
+
+			ReadOnly: o.ReadOnly,
 		}
 		return render.WriteGrpcServiceProto(outputPath, data)
 
@@ -41,8 +46,11 @@ func WriteServiceProto(
 			TargetModule: targetModuleName,
 			Version:      moduleVersion,
 
-			Name:    inflection.Singular(tableName),
+			Name:    o.Naming.Name(tableName),
 			Comment: RemoveTableCommentSuffix(tableComment),
+			Naming:  o.Naming,
+
+			ReadOnly: o.ReadOnly,
 		}
 		return render.WriteRestServiceProto(outputPath, data)
 
@@ -56,7 +64,14 @@ func WriteServicesProto(
 	serviceType string,
 	targetModuleName, sourceModuleName, moduleVersion string,
 	tables TableDataArray,
+	opts ...ConvertOption,
 ) error {
+	o := newConvertOptions(opts)
+
+	if err := checkTypeNames(serviceType, targetModuleName, sourceModuleName, tables, o); err != nil {
+		return err
+	}
+
 	var protoFields ProtoFieldArray
 
 	for i := 0; i < len(tables); i++ {
@@ -66,21 +81,25 @@ func WriteServicesProto(
 		for n := 0; n < len(table.Fields); n++ {
 			field := table.Fields[n]
 			protoFields = append(protoFields, generators.ProtoField{
-				Number:  n + 1,
-				Name:    field.Name,
-				Comment: field.Comment,
-				Type:    field.Type,
+				Number:      n + 1,
+				Name:        field.Name,
+				Comment:     field.Comment,
+				Type:        field.Type,
+				Annotations: field.Annotations,
 			})
 		}
 
 		//log.Printf("Generating proto for table: [%s] [%s]", table.Name, table.Comment)
 
+		targetModule, sourceModule := tableModules(serviceType, targetModuleName, sourceModuleName, table, o)
+
 		if err := WriteServiceProto(
 			outputPath,
 			serviceType,
-			targetModuleName, sourceModuleName, moduleVersion,
-			table.Name, table.Comment,
+			targetModule, sourceModule, moduleVersion,
+			table.QualifiedName(), table.Comment,
 			protoFields,
+			append(opts, WithReadOnly(table.View))...,
 		); err != nil {
 			log.Fatal(err)
 			return err
@@ -90,6 +109,37 @@ func WriteServicesProto(
 	return nil
 }
 
+// tableModules returns the target and source modules of the service of a table. With the SchemaModules
+// option, the module named after the schema of the table replaces the source module of the REST services,
+// and the target module of the others.
+func tableModules(serviceType, targetModule, sourceModule string, table *internal.TableData, o *ConvertOptions) (string, string) {
+	if o.SchemaModules && table.Schema != "" {
+		if strings.TrimSpace(strings.ToLower(serviceType)) == "rest" {
+			sourceModule = table.Schema
+		} else {
+			targetModule = table.Schema
+		}
+	}
+	return targetModule, sourceModule
+}
+
+// checkTypeNames returns an error when two tables are named the same in the target module of their
+// services, e.g. "auth.users" and "billing.users" without the SchemaModules option, as the proto file of
+// one would overwrite the other.
+func checkTypeNames(serviceType, targetModule, sourceModule string, tables TableDataArray, o *ConvertOptions) error {
+	typeTables := make(map[string]string, len(tables))
+	for _, table := range tables {
+		module, _ := tableModules(serviceType, targetModule, sourceModule, table, o)
+		name := o.Naming.Name(table.QualifiedName())
+		key := strings.ToLower(module) + "." + name
+		if other, ok := typeTables[key]; ok {
+			return fmt.Errorf("sqlproto: tables %v and %v are both imported as %v, rename one of them with the type_names of the naming rules", other, table.QualifiedName(), name)
+		}
+		typeTables[key] = table.QualifiedName()
+	}
+	return nil
+}
+
 func RemoveTableCommentSuffix(input string) string {
 	re := regexp.MustCompile(`(表|table)$`)
 	return re.ReplaceAllString(input, "")
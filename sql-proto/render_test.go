@@ -0,0 +1,33 @@
+package sqlproto
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tx7do/go-wind-toolkit/sql-proto/internal"
+)
+
+func TestWriteServicesProtoTypeCollision(t *testing.T) {
+	tables := TableDataArray{
+		{Schema: "auth", Name: "users", Fields: []internal.FieldData{{Name: "id", Type: "int64"}}},
+		{Schema: "billing", Name: "users", Fields: []internal.FieldData{{Name: "id", Type: "int64"}}},
+	}
+
+	dir := t.TempDir()
+	err := WriteServicesProto(dir, "grpc", "admin", "admin", "v1", tables)
+	require.EqualError(t, err, "sqlproto: tables auth.users and billing.users are both imported as user, rename one of them with the type_names of the naming rules")
+	require.NoFileExists(t, filepath.Join(dir, "admin", "service", "v1", "user.proto"))
+
+	// REST services of the schema modules are written to the same target module.
+	err = WriteServicesProto(dir, "rest", "admin", "admin", "v1", tables, WithSchemaModules(true))
+	require.ErrorContains(t, err, "are both imported as user")
+
+	require.NoError(t, WriteServicesProto(dir, "grpc", "admin", "admin", "v1", tables, WithSchemaModules(true)))
+	for _, module := range []string{"auth", "billing"} {
+		_, err = os.Stat(filepath.Join(dir, module, "service", "v1", "user.proto"))
+		require.NoError(t, err)
+	}
+}